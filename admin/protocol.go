@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error codes of the responses, following the JSON-RPC conventions.
+const (
+	ErrCodeParse          = -32700 // The request line could not be parsed.
+	ErrCodeMethodNotFound = -32601 // The method of the request does not exist.
+	ErrCodeInvalidParams  = -32602 // The params of the request are invalid.
+	ErrCodeInternal       = -32000 // The method failed while handling the request.
+)
+
+// Request represents a command sent over the admin socket. A request line is either a JSON object, or a method
+// followed by whitespace-separated key=value params, such as "reload-prices gigabyte_prices=udvpn:0.005,25".
+type Request struct {
+	ID     json.RawMessage   `json:"id,omitempty"`
+	Method string            `json:"method"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// ParseRequest parses the request line.
+func ParseRequest(line string) (*Request, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, errors.New("empty request")
+	}
+
+	req := &Request{}
+
+	if strings.HasPrefix(line, "{") {
+		if err := json.Unmarshal([]byte(line), req); err != nil {
+			return nil, fmt.Errorf("decoding request: %w", err)
+		}
+	} else {
+		fields := strings.Fields(line)
+
+		req.Method = fields[0]
+		req.Params = make(map[string]string, len(fields)-1)
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok || key == "" {
+				return nil, fmt.Errorf("param %q is not of the form key=value", field)
+			}
+
+			req.Params[key] = value
+		}
+	}
+
+	if req.Method == "" {
+		return nil, errors.New("method cannot be empty")
+	}
+
+	return req, nil
+}
+
+// ResponseError represents the error of a failed request.
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Response represents the reply to a request, written as a single JSON line.
+type Response struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Error  *ResponseError  `json:"error,omitempty"`
+	Result interface{}     `json:"result,omitempty"`
+}
+
+// NewResponseResult creates a successful response to the request.
+func NewResponseResult(req *Request, result interface{}) *Response {
+	return &Response{
+		ID:     req.ID,
+		Result: result,
+	}
+}
+
+// NewResponseError creates a failed response to the request, which is nil if the request could not be parsed.
+func NewResponseError(req *Request, code int, err error) *Response {
+	res := &Response{
+		Error: &ResponseError{
+			Code:    code,
+			Message: err.Error(),
+		},
+	}
+
+	if req != nil {
+		res.ID = req.ID
+	}
+
+	return res
+}
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// maxRequestSize is the maximum size of a request line.
+const maxRequestSize = 64 * 1024
+
+// ErrInvalidParams is wrapped by the errors of handlers rejecting the params of a request.
+var ErrInvalidParams = errors.New("invalid params")
+
+// HandlerFunc handles the params of a request and returns its result.
+type HandlerFunc func(ctx context.Context, params map[string]string) (interface{}, error)
+
+// Server serves line-based requests on a Unix domain socket accessible only to the user running the node.
+type Server struct {
+	handlers map[string]HandlerFunc
+	path     string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a Server listening on the Unix domain socket at the path.
+func NewServer(path string) *Server {
+	return &Server{
+		handlers: make(map[string]HandlerFunc),
+		path:     path,
+	}
+}
+
+// WithHandler registers the handler of the method and returns the updated Server.
+func (s *Server) WithHandler(method string, fn HandlerFunc) *Server {
+	s.handlers[method] = fn
+
+	return s
+}
+
+// Path returns the path of the socket.
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Methods returns the sorted names of the registered methods.
+func (s *Server) Methods() []string {
+	items := make([]string, 0, len(s.handlers))
+	for method := range s.handlers {
+		items = append(items, method)
+	}
+
+	sort.Strings(items)
+
+	return items
+}
+
+// Listen creates the socket with 0600 permissions. A socket left behind by a previous instance is removed,
+// unless another process still accepts connections on it.
+func (s *Server) Listen(ctx context.Context) error {
+	if _, err := os.Stat(s.path); err == nil {
+		d := &net.Dialer{}
+		if conn, err := d.DialContext(ctx, "unix", s.path); err == nil {
+			_ = conn.Close()
+
+			return fmt.Errorf("admin socket %q is in use by another process", s.path)
+		}
+
+		if err := os.Remove(s.path); err != nil {
+			return fmt.Errorf("removing stale admin socket %q: %w", s.path, err)
+		}
+	}
+
+	lc := &net.ListenConfig{}
+
+	listener, err := lc.Listen(ctx, "unix", s.path)
+	if err != nil {
+		return fmt.Errorf("creating listener on %q: %w", s.path, err)
+	}
+
+	if err := os.Chmod(s.path, 0o600); err != nil {
+		_ = listener.Close()
+
+		return fmt.Errorf("changing permissions of admin socket %q: %w", s.path, err)
+	}
+
+	s.listener = listener
+
+	return nil
+}
+
+// Serve accepts connections until the context is done, then closes the listener and waits for the connections
+// being served.
+func (s *Server) Serve(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		_ = s.listener.Close()
+	}()
+
+	defer s.wg.Wait()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("accepting connection: %w", err)
+		}
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn reads the request lines of the connection and writes a response line for each of them, until the
+// client closes the connection or the context is done.
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+
+		_ = conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		res := s.handle(ctx, scanner.Text())
+		if err := encoder.Encode(res); err != nil {
+			log.Debug("Failed to write admin socket response", "cause", err)
+
+			return
+		}
+	}
+}
+
+// handle parses the request line and runs the handler of its method.
+func (s *Server) handle(ctx context.Context, line string) *Response {
+	req, err := ParseRequest(line)
+	if err != nil {
+		return NewResponseError(nil, ErrCodeParse, err)
+	}
+
+	fn, ok := s.handlers[req.Method]
+	if !ok {
+		return NewResponseError(req, ErrCodeMethodNotFound, fmt.Errorf("method %q not found", req.Method))
+	}
+
+	log.Info("Handling admin socket request", "method", req.Method)
+
+	result, err := fn(ctx, req.Params)
+	if err != nil {
+		if errors.Is(err, ErrInvalidParams) {
+			return NewResponseError(req, ErrCodeInvalidParams, err)
+		}
+
+		return NewResponseError(req, ErrCodeInternal, err)
+	}
+
+	return NewResponseResult(req, result)
+}
+
+// Cleanup removes the socket file, if it exists.
+func (s *Server) Cleanup() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing admin socket %q: %w", s.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetSessionEvents returns a handler function to retrieve the connection events of a session.
+func handlerGetSessionEvents(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Parse the request.
+		req, err := NewGetSessionEventsRequest(ctx)
+		if err != nil {
+			err = fmt.Errorf("parsing request from context: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		// Retrieve the session events from the database.
+		query := map[string]interface{}{
+			"session_id": req.ID,
+		}
+
+		items, err := c.SessionStore().FindEvents(query)
+		if err != nil {
+			err = fmt.Errorf("retrieving events for session %d from database: %w", req.ID, err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		res := make([]*SessionEventResult, 0, len(items))
+		for i := range items {
+			res = append(res, NewSessionEventResult(&items[i], c.HumanReadableBytes()))
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
+
+// handlerReloadPrices returns a handler function to replace the prices of the node without a restart.
+// The prices are broadcast to the blockchain before they take effect in the context.
+func handlerReloadPrices(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Parse the request.
+		req, err := NewReloadPricesRequest(ctx)
+		if err != nil {
+			err = fmt.Errorf("parsing request from context: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		if err := c.ReloadPrices(ctx, req.GigabytePrices, req.HourlyPrices); err != nil {
+			err = fmt.Errorf("reloading prices: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		res := &ReloadPricesResult{
+			GigabytePrices: c.GigabytePrices().String(),
+			HourlyPrices:   c.HourlyPrices().String(),
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
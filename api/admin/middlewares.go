@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// AuthMiddleware returns a middleware rejecting requests without the configured admin bearer token.
+func AuthMiddleware(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(c.AdminToken())) != 1 {
+			err := errors.New("invalid or missing bearer token")
+			apierrors.ErrUnauthorized.Abort(ctx, err)
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+)
+
+// GetSessionEventsRequest represents the request for retrieving the events of a session.
+type GetSessionEventsRequest struct {
+	ID uint64
+}
+
+// NewGetSessionEventsRequest parses and validates the session events request.
+func NewGetSessionEventsRequest(c *gin.Context) (req *GetSessionEventsRequest, err error) {
+	req = &GetSessionEventsRequest{}
+
+	// Parse the session ID from the path.
+	req.ID, err = strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing session id %q: %w", c.Param("id"), err)
+	}
+
+	return req, nil
+}
+
+// ReloadPricesRequest represents the request for replacing the prices of the node.
+type ReloadPricesRequest struct {
+	Body struct {
+		GigabytePrices string `json:"gigabyte_prices"`
+		HourlyPrices   string `json:"hourly_prices"`
+	}
+
+	GigabytePrices v1.Prices
+	HourlyPrices   v1.Prices
+}
+
+// NewReloadPricesRequest parses and validates the reload prices request.
+// The prices use the same format as the gigabyte_prices and hourly_prices of the configuration.
+func NewReloadPricesRequest(c *gin.Context) (req *ReloadPricesRequest, err error) {
+	req = &ReloadPricesRequest{}
+
+	// Bind JSON request to the struct.
+	if err = c.ShouldBindJSON(&req.Body); err != nil {
+		return nil, fmt.Errorf("binding JSON request body: %w", err)
+	}
+
+	req.GigabytePrices, err = v1.NewPricesFromString(req.Body.GigabytePrices)
+	if err != nil {
+		return nil, fmt.Errorf("parsing gigabyte_prices %q: %w", req.Body.GigabytePrices, err)
+	}
+
+	req.HourlyPrices, err = v1.NewPricesFromString(req.Body.HourlyPrices)
+	if err != nil {
+		return nil, fmt.Errorf("parsing hourly_prices %q: %w", req.Body.HourlyPrices, err)
+	}
+
+	return req, nil
+}
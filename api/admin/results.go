@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// SessionEventResult represents a single session event in the response.
+type SessionEventResult struct {
+	EventType    string    `json:"event_type"`
+	RxBytes      string    `json:"rx_bytes"`
+	RxBytesHuman string    `json:"rx_bytes_human,omitempty"`
+	SessionID    uint64    `json:"session_id"`
+	Timestamp    time.Time `json:"timestamp"`
+	TxBytes      string    `json:"tx_bytes"`
+	TxBytesHuman string    `json:"tx_bytes_human,omitempty"`
+}
+
+// NewSessionEventResult creates a SessionEventResult from the session event record.
+// The human-readable byte fields are included only if humanReadable is true.
+func NewSessionEventResult(v *models.SessionEvent, humanReadable bool) *SessionEventResult {
+	res := &SessionEventResult{
+		EventType: v.GetEventType(),
+		RxBytes:   v.GetRxBytes().String(),
+		SessionID: v.GetSessionID(),
+		Timestamp: v.GetCreatedAt(),
+		TxBytes:   v.GetTxBytes().String(),
+	}
+
+	if humanReadable {
+		res.RxBytesHuman = core.FormatBytes(v.GetRxBytes())
+		res.TxBytesHuman = core.FormatBytes(v.GetTxBytes())
+	}
+
+	return res
+}
+
+// ReloadPricesResult represents the prices of the node after a reload.
+type ReloadPricesResult struct {
+	GigabytePrices string `json:"gigabyte_prices"`
+	HourlyPrices   string `json:"hourly_prices"`
+}
@@ -0,0 +1,18 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// RegisterRoutes registers the routes for the admin API if an admin token is configured.
+func RegisterRoutes(c *core.Context, r gin.IRouter) {
+	if c.AdminToken() == "" {
+		return
+	}
+
+	g := r.Group("/admin", AuthMiddleware(c))
+	g.GET("/sessions/:id/events", handlerGetSessionEvents(c))
+	g.POST("/reload-prices", handlerReloadPrices(c))
+}
@@ -0,0 +1,44 @@
+package analytics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetAccounts returns a handler function to list the accounts with the most bytes served, largest first.
+// The totals are read from the persisted usage of the accounts, so they include the bytes of deleted sessions.
+func handlerGetAccounts(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Parse the request.
+		req, err := NewGetAccountsRequest(ctx)
+		if err != nil {
+			err = fmt.Errorf("parsing request from context: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		// Retrieve the accounts with the most bytes served.
+		items, err := c.SessionStore().TopAccountsByUsage(req.Top)
+		if err != nil {
+			err = fmt.Errorf("retrieving account usage from database: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		res := make([]*AccountResult, 0, len(items))
+		for _, item := range items {
+			res = append(res, NewAccountResult(item))
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
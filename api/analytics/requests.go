@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bounds of the number of accounts returned in a single response.
+const (
+	defaultTop = 10
+	maxTop     = 1000
+)
+
+// GetAccountsRequest represents the request for listing the accounts with the most bytes served.
+type GetAccountsRequest struct {
+	Query struct {
+		Top string `form:"top"`
+	}
+
+	Top int
+}
+
+// NewGetAccountsRequest parses and validates the top accounts request.
+func NewGetAccountsRequest(c *gin.Context) (req *GetAccountsRequest, err error) {
+	req = &GetAccountsRequest{
+		Top: defaultTop,
+	}
+
+	// Bind the query parameters.
+	if err = c.ShouldBindQuery(&req.Query); err != nil {
+		return nil, fmt.Errorf("binding query: %w", err)
+	}
+
+	// Parse the optional number of accounts.
+	if req.Query.Top != "" {
+		req.Top, err = strconv.Atoi(req.Query.Top)
+		if err != nil {
+			return nil, fmt.Errorf("parsing top %q: %w", req.Query.Top, err)
+		}
+
+		if req.Top <= 0 || req.Top > maxTop {
+			return nil, fmt.Errorf("top %d must be between 1 and %d", req.Top, maxTop)
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,24 @@
+package analytics
+
+import (
+	"strconv"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/operations"
+)
+
+// AccountResult represents the total bytes served to a single account in the response.
+// The total is a decimal string since it may exceed the range of a JSON number.
+type AccountResult struct {
+	AccAddr    string `json:"acc_addr"`
+	Sessions   int64  `json:"sessions"`
+	TotalBytes string `json:"total_bytes"`
+}
+
+// NewAccountResult creates an AccountResult from the total usage of the account.
+func NewAccountResult(v *operations.AccountUsageTotal) *AccountResult {
+	return &AccountResult{
+		AccAddr:    v.AccAddr,
+		Sessions:   v.Sessions,
+		TotalBytes: strconv.FormatInt(v.TotalBytes, 10),
+	}
+}
@@ -0,0 +1,18 @@
+package analytics
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/api/admin"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// RegisterRoutes registers the routes for the analytics API if an admin token is configured.
+// The analytics reveal the account addresses and usage of the clients, so they are served only to admin requests.
+func RegisterRoutes(c *core.Context, r gin.IRouter) {
+	if c.AdminToken() == "" {
+		return
+	}
+
+	r.GET("/analytics/accounts", admin.AuthMiddleware(c), handlerGetAccounts(c))
+}
@@ -0,0 +1,86 @@
+package capabilities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+	"github.com/sentinel-official/sentinel-go-sdk/version"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// buildCapabilities assembles the capabilities document and signs it with the node key if enabled.
+func buildCapabilities(c *core.Context, sign bool) (*GetCapabilitiesResult, error) {
+	doc := &CapabilitiesDocument{
+		Addr:           c.NodeAddr().String(),
+		GigabytePrices: c.GigabytePrices(),
+		HourlyPrices:   c.HourlyPrices(),
+		Location:       c.PublicLocation(),
+		MaxPeers:       c.MaxPeers(),
+		Moniker:        c.Moniker(),
+		Protocols:      c.Protocols(),
+		RemoteAddrs:    c.RemoteAddrs(),
+		ServiceType:    c.Service().Type().String(),
+		Timestamp:      time.Now().UTC(),
+		Version:        version.Get(),
+	}
+
+	buf, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding capabilities document: %w", err)
+	}
+
+	res := &GetCapabilitiesResult{Document: buf}
+	if !sign {
+		return res, nil
+	}
+
+	signature, pubKey, err := c.Client().Sign("", buf)
+	if err != nil {
+		return nil, fmt.Errorf("signing capabilities document: %w", err)
+	}
+
+	res.PubKey = base64.StdEncoding.EncodeToString(pubKey.Bytes())
+	res.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	return res, nil
+}
+
+// handlerGetCapabilities returns a handler function to retrieve the capabilities document of the node.
+// The document is rebuilt and signed at most once per configured TTL.
+func handlerGetCapabilities(c *core.Context) gin.HandlerFunc {
+	var (
+		expiresAt time.Time
+		mu        sync.Mutex
+		result    *GetCapabilitiesResult
+	)
+
+	sign, cacheTTL := c.Capabilities()
+
+	return func(ctx *gin.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if result == nil || !time.Now().Before(expiresAt) {
+			res, err := buildCapabilities(c, sign)
+			if err != nil {
+				apierrors.ErrInternal.JSON(ctx, err)
+
+				return
+			}
+
+			result = res
+			expiresAt = time.Now().Add(cacheTTL)
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(result))
+	}
+}
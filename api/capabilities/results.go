@@ -0,0 +1,33 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/geoip"
+	"github.com/sentinel-official/sentinel-go-sdk/version"
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+)
+
+// CapabilitiesDocument describes the capabilities of the node at the time it was built.
+type CapabilitiesDocument struct {
+	Addr           string          `json:"addr"`
+	GigabytePrices v1.Prices       `json:"gigabyte_prices"`
+	HourlyPrices   v1.Prices       `json:"hourly_prices"`
+	Location       *geoip.Location `json:"location"`
+	MaxPeers       uint            `json:"max_peers"`
+	Moniker        string          `json:"moniker"`
+	Protocols      []string        `json:"protocols"`
+	RemoteAddrs    []string        `json:"remote_addrs"`
+	ServiceType    string          `json:"service_type"`
+	Timestamp      time.Time       `json:"timestamp"`
+	Version        *version.Info   `json:"version"`
+}
+
+// GetCapabilitiesResult represents the capabilities document along with its signature.
+// The signature covers the exact bytes of the document, which are returned as is.
+type GetCapabilitiesResult struct {
+	Document  json.RawMessage `json:"document"`
+	PubKey    string          `json:"pub_key,omitempty"`
+	Signature string          `json:"signature,omitempty"`
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+)
+
+// Code is the numeric code of an error response. Codes are part of the API contract: a code is never reused for
+// another error, and new errors are appended with new codes.
+type Code int
+
+// Error represents a class of error responses with a stable code and the HTTP status it is returned with.
+type Error struct {
+	Code   Code   // Code is the stable numeric code of the error.
+	Name   string // Name is the identifier of the error used in documentation.
+	Status int    // Status is the HTTP status of the error response.
+}
+
+// response is an error response with the correlation ID of the request.
+type response struct {
+	*types.Response
+
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// JSON writes the error response with the code and HTTP status of the error and the message of err, along with
+// the correlation ID of the request if one was assigned.
+func (e *Error) JSON(ctx *gin.Context, err error) {
+	ctx.JSON(e.Status, &response{
+		Response:  types.NewResponseError(int(e.Code), err),
+		RequestID: RequestID(ctx),
+	})
+}
+
+// Abort stops the remaining handlers of the request and writes the error response, for use in middlewares.
+func (e *Error) Abort(ctx *gin.Context, err error) {
+	ctx.Abort()
+	e.JSON(ctx, err)
+}
+
+// Errors returned by the API handlers.
+var (
+	ErrInternal           = &Error{Code: 1, Name: "internal", Status: http.StatusInternalServerError}          // The node failed to process a valid request.
+	ErrInvalidRequest     = &Error{Code: 2, Name: "invalid_request", Status: http.StatusBadRequest}            // The request could not be parsed or is malformed.
+	ErrNodeInactive       = &Error{Code: 3, Name: "node_inactive", Status: http.StatusServiceUnavailable}      // The node is inactive on the blockchain.
+	ErrMaxPeersReached    = &Error{Code: 4, Name: "max_peers_reached", Status: http.StatusConflict}            // The node serves the maximum number of peers.
+	ErrSignatureInvalid   = &Error{Code: 5, Name: "signature_invalid", Status: http.StatusUnauthorized}        // The signature of the request does not verify.
+	ErrRequestReplayed    = &Error{Code: 6, Name: "request_replayed", Status: http.StatusUnauthorized}         // The request was already received or its timestamp is stale.
+	ErrSessionExists      = &Error{Code: 7, Name: "session_exists", Status: http.StatusConflict}               // A session with the ID already exists on the node.
+	ErrPeerRequestExists  = &Error{Code: 8, Name: "peer_request_exists", Status: http.StatusConflict}          // A session with the peer request already exists on the node.
+	ErrSessionNotFound    = &Error{Code: 9, Name: "session_not_found", Status: http.StatusNotFound}            // The session does not exist on the blockchain.
+	ErrSessionInactive    = &Error{Code: 10, Name: "session_inactive", Status: http.StatusBadRequest}          // The session is not active on the blockchain.
+	ErrSessionExhausted   = &Error{Code: 11, Name: "session_exhausted", Status: http.StatusPaymentRequired}    // The session has no remaining bytes or duration.
+	ErrDepositTooLow      = &Error{Code: 12, Name: "deposit_too_low", Status: http.StatusPaymentRequired}      // The deposit of the session is below the minimum of the node.
+	ErrNodeMismatch       = &Error{Code: 13, Name: "node_mismatch", Status: http.StatusBadRequest}             // The session belongs to another node.
+	ErrAccountMismatch    = &Error{Code: 14, Name: "account_mismatch", Status: http.StatusUnauthorized}        // The request is signed by an account other than the session account.
+	ErrAccountBlocked     = &Error{Code: 15, Name: "account_blocked", Status: http.StatusForbidden}            // The account of the session is blocked by the node.
+	ErrMaxSessionsReached = &Error{Code: 16, Name: "max_sessions_reached", Status: http.StatusTooManyRequests} // The account of the session has the maximum number of sessions.
+	ErrByteCapReached     = &Error{Code: 17, Name: "byte_cap_reached", Status: http.StatusServiceUnavailable}  // The bytes served in the current month reached the monthly byte cap of the node.
+	ErrUnauthorized       = &Error{Code: 18, Name: "unauthorized", Status: http.StatusUnauthorized}            // The request does not carry the admin bearer token.
+	ErrAccessDenied       = &Error{Code: 19, Name: "access_denied", Status: http.StatusForbidden}              // The remote address of the request is denied by the access lists.
+	ErrBodyTooLarge       = &Error{Code: 20, Name: "body_too_large", Status: http.StatusRequestEntityTooLarge} // The body of the request exceeds the maximum size.
+)
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+)
+
+func TestErrorAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var reached bool
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		SetRequestID(ctx, "abort-test")
+		ErrBodyTooLarge.Abort(ctx, errors.New("too large"))
+	})
+	router.GET("/", func(ctx *gin.Context) {
+		reached = true
+
+		ctx.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if reached {
+		t.Fatal("expected the handler to be skipped")
+	}
+
+	if rec.Code != ErrBodyTooLarge.Status {
+		t.Fatalf("expected status %d, got %d", ErrBodyTooLarge.Status, rec.Code)
+	}
+
+	var res struct {
+		types.Response
+		RequestID string `json:"request_id"`
+	}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if res.Error == nil || res.Error.Code != int(ErrBodyTooLarge.Code) {
+		t.Fatalf("expected error code %d, got %+v", ErrBodyTooLarge.Code, res.Error)
+	}
+
+	if res.RequestID != "abort-test" {
+		t.Fatalf("expected request ID %q, got %q", "abort-test", res.RequestID)
+	}
+}
@@ -0,0 +1,21 @@
+package errors
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header carrying the correlation ID of a request and its response.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the key of the correlation ID of a request in the gin context.
+const requestIDKey = "request_id"
+
+// RequestID returns the correlation ID of the request, or empty if none was assigned.
+func RequestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
+
+// SetRequestID sets the correlation ID of the request, which is then included in its error responses.
+func SetRequestID(ctx *gin.Context, id string) {
+	ctx.Set(requestIDKey, id)
+}
@@ -3,28 +3,114 @@ package handshake
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"cosmossdk.io/math"
 	cosmossdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/gin-gonic/gin"
+	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
 	"github.com/sentinel-official/sentinel-go-sdk/node"
 	"github.com/sentinel-official/sentinel-go-sdk/types"
 	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+	nodetypes "github.com/sentinel-official/sentinelhub/v12/x/node/types/v3"
+	"github.com/sentinel-official/sentinelhub/v12/x/session/types/v3"
 
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
 	"github.com/sentinel-official/sentinel-dvpnx/core"
 	"github.com/sentinel-official/sentinel-dvpnx/database/models"
 	"github.com/sentinel-official/sentinel-dvpnx/database/operations"
+	"github.com/sentinel-official/sentinel-dvpnx/events"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
 )
 
+// checkAllocation returns an error if the session has already consumed its bytes or duration allocation on-chain.
+func checkAllocation(session v3.Session) error {
+	if maxBytes := session.GetMaxBytes(); !maxBytes.IsZero() && session.TotalBytes().GTE(maxBytes) {
+		return fmt.Errorf("session %d has no remaining bytes; used %s of %s", session.GetID(), session.TotalBytes(), maxBytes)
+	}
+
+	if maxDuration := session.GetMaxDuration(); maxDuration != 0 && session.GetDuration() >= maxDuration {
+		return fmt.Errorf("session %d has no remaining duration; used %s of %s", session.GetID(), session.GetDuration(), maxDuration)
+	}
+
+	return nil
+}
+
+// checkDeposit returns an error if the session is paid directly to the node with a deposit below the minimum.
+// Sessions of subscriptions carry no deposit of their own and are not checked.
+func checkDeposit(session v3.Session, minDeposit cosmossdk.Coins) error {
+	v, ok := session.(*nodetypes.Session)
+	if !ok {
+		return nil
+	}
+
+	deposit := v.DepositAmount()
+	if minAmount := minDeposit.AmountOf(deposit.Denom); deposit.Amount.LT(minAmount) {
+		return fmt.Errorf("session %d deposit %s is below the minimum %s%s", session.GetID(), deposit, minAmount, deposit.Denom)
+	}
+
+	return nil
+}
+
+// withTunnelSettings adds the configured MTU and keepalive to the encoded add-peer response so that clients can
+// configure their tunnel without manual tuning. Unset values are omitted and leave the client defaults in place.
+func withTunnelSettings(data []byte, mtu, keepalive uint) ([]byte, error) {
+	if mtu == 0 && keepalive == 0 {
+		return data, nil
+	}
+
+	var v map[string]json.RawMessage
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("decoding add-peer service response: %w", err)
+	}
+
+	if mtu != 0 {
+		v["mtu"] = json.RawMessage(strconv.FormatUint(uint64(mtu), 10))
+	}
+
+	if keepalive != 0 {
+		v["keepalive"] = json.RawMessage(strconv.FormatUint(uint64(keepalive), 10))
+	}
+
+	return json.Marshal(v) //nolint:wrapcheck
+}
+
 // handlerInitHandshake returns a handler function to process the request for performing a handshake.
 func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		// Tag the log lines of the handshake with the correlation ID returned to the client.
+		log := logger.With("module", "api", "name", "handshake", "request_id", apierrors.RequestID(ctx))
+
+		// Reject handshake if the node is inactive on the blockchain
+		if c.Inactive() {
+			err := errors.New("node is inactive on blockchain")
+			apierrors.ErrNodeInactive.JSON(ctx, err)
+
+			return
+		}
+
 		// Reject handshake if maximum peer limit is reached
 		if n := c.Service().PeersLen(); uint(n) >= c.MaxPeers() {
 			err := fmt.Errorf("maximum peer limit %d reached", n)
-			ctx.JSON(http.StatusConflict, types.NewResponseError(1, err))
+			apierrors.ErrMaxPeersReached.JSON(ctx, err)
+
+			return
+		}
+
+		// Reject handshake if the bytes served in the current month reached the monthly byte cap
+		if err := c.CheckMonthlyByteCap(time.Now()); err != nil {
+			if errors.Is(err, core.ErrMonthlyByteCapReached) {
+				apierrors.ErrByteCapReached.JSON(ctx, err)
+
+				return
+			}
+
+			err = fmt.Errorf("checking monthly byte cap: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
@@ -32,28 +118,56 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 		// Parse and verify the request.
 		req, err := NewInitHandshakeRequest(ctx)
 		if err != nil {
+			if errors.Is(err, errVerifyRequest) {
+				log.Warn("Rejecting handshake with invalid signature", "client_ip", ctx.ClientIP(), "cause", err)
+
+				apierrors.ErrSignatureInvalid.JSON(ctx, err)
+
+				return
+			}
+
 			err = fmt.Errorf("parsing request from context: %w", err)
-			ctx.JSON(http.StatusBadRequest, types.NewResponseError(2, err))
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
 
 			return
 		}
 
+		// Reject replayed requests and requests with a stale timestamp before querying the database.
+		if guard := c.ReplayGuard(); guard != nil {
+			if err := guard.Check(req.Body.ID, req.Body.Data, req.Body.PubKey, req.Body.Signature); err != nil {
+				log.Warn("Rejecting replayed handshake",
+					"client_ip", ctx.ClientIP(), "id", req.Body.ID, "acc_addr", req.AccAddr(), "cause", err,
+				)
+
+				apierrors.ErrRequestReplayed.JSON(ctx, err)
+
+				return
+			}
+
+			// Forget the request if it fails on the side of the node, so that the client can retry it.
+			defer func() {
+				if ctx.Writer.Status() >= http.StatusInternalServerError {
+					guard.Forget(req.Body.ID, req.Body.Data, req.Body.PubKey, req.Body.Signature)
+				}
+			}()
+		}
+
 		// Check if a session already exists by ID.
 		query := map[string]interface{}{
 			"id": req.Body.ID,
 		}
 
-		record, err := operations.SessionFindOne(c.Database(), query)
+		record, err := c.SessionStore().FindOne(query)
 		if err != nil {
 			err = fmt.Errorf("retrieving session %d from database: %w", req.Body.ID, err)
-			ctx.JSON(http.StatusInternalServerError, types.NewResponseError(3, err))
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
 
 		if record != nil {
 			err = fmt.Errorf("session %d already exists in database", req.Body.ID)
-			ctx.JSON(http.StatusConflict, types.NewResponseError(3, err))
+			apierrors.ErrSessionExists.JSON(ctx, err)
 
 			return
 		}
@@ -64,17 +178,83 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 			"peer_request": peerReqStr,
 		}
 
-		record, err = operations.SessionFindOne(c.Database(), query)
+		record, err = c.SessionStore().FindOne(query)
 		if err != nil {
 			err = fmt.Errorf("retrieving session for peer request %q from database: %w", peerReqStr, err)
-			ctx.JSON(http.StatusInternalServerError, types.NewResponseError(4, err))
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		if record != nil {
+			// Release a stale peer request whose peer is no longer in the service, if reuse is enabled.
+			stale := false
+			if c.PeerRequestReuse() {
+				ok, err := c.Service().HasPeer(ctx, record.GetPeerID())
+				if err != nil {
+					err = fmt.Errorf("checking if peer %q exists in service: %w", record.GetPeerID(), err)
+					apierrors.ErrInternal.JSON(ctx, err)
+
+					return
+				}
+
+				stale = !ok
+			}
+
+			if !stale {
+				err = fmt.Errorf("session already exists for peer request %q", peerReqStr)
+				apierrors.ErrPeerRequestExists.JSON(ctx, err)
+
+				return
+			}
+
+			if err := c.ReleasePeerRequest(record); err != nil {
+				apierrors.ErrInternal.JSON(ctx, err)
+
+				return
+			}
+		}
+
+		// Derive the peer ID the service assigns to the peer, and reject requests of a peer that is already in use
+		// under a differently encoded peer request, since adding it again would replace the existing peer.
+		peerID, err := core.PeerIDFromRequest(c.Service().Type(), req.PeerRequest())
+		if err != nil {
+			err = fmt.Errorf("decoding peer request: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		query = map[string]interface{}{
+			"peer_id": peerID,
+		}
+
+		record, err = c.SessionStore().FindOne(query)
+		if err != nil {
+			err = fmt.Errorf("retrieving session for peer %q from database: %w", peerID, err)
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
 
 		if record != nil {
-			err = fmt.Errorf("session already exists for peer request %q", peerReqStr)
-			ctx.JSON(http.StatusConflict, types.NewResponseError(4, err))
+			err = fmt.Errorf("session already exists for peer %q", peerID)
+			apierrors.ErrPeerRequestExists.JSON(ctx, err)
+
+			return
+		}
+
+		ok, err := c.Service().HasPeer(ctx, peerID)
+		if err != nil {
+			err = fmt.Errorf("checking if peer %q exists in service: %w", peerID, err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		if ok {
+			err = fmt.Errorf("peer %q already exists in service", peerID)
+			apierrors.ErrPeerRequestExists.JSON(ctx, err)
 
 			return
 		}
@@ -82,15 +262,17 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 		// Fetch session details from blockchain.
 		session, err := c.Client().Session(ctx, req.Body.ID)
 		if err != nil {
+			log.Error("Failed to query session from blockchain", "id", req.Body.ID, "cause", err)
+
 			err = fmt.Errorf("querying session %d from blockchain: %w", req.Body.ID, err)
-			ctx.JSON(http.StatusInternalServerError, types.NewResponseError(5, err))
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
 
 		if session == nil {
 			err = fmt.Errorf("session %d does not exist on blockchain", req.Body.ID)
-			ctx.JSON(http.StatusNotFound, types.NewResponseError(5, err))
+			apierrors.ErrSessionNotFound.JSON(ctx, err)
 
 			return
 		}
@@ -98,15 +280,33 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 		// Validate session status.
 		if !session.GetStatus().Equal(v1.StatusActive) {
 			err = fmt.Errorf("invalid session status %q, expected %q", session.GetStatus(), v1.StatusActive)
-			ctx.JSON(http.StatusBadRequest, types.NewResponseError(5, err))
+			apierrors.ErrSessionInactive.JSON(ctx, err)
 
 			return
 		}
 
+		// Reject handshake if the session has no remaining allocation, if required.
+		if c.RequireAllocation() {
+			if err := checkAllocation(session); err != nil {
+				apierrors.ErrSessionExhausted.JSON(ctx, err)
+
+				return
+			}
+		}
+
+		// Reject handshake if the session deposit is below the configured minimum.
+		if minDeposit := c.MinDeposit(); !minDeposit.Empty() {
+			if err := checkDeposit(session, minDeposit); err != nil {
+				apierrors.ErrDepositTooLow.JSON(ctx, err)
+
+				return
+			}
+		}
+
 		// Validate node address.
 		if session.GetNodeAddress() != c.NodeAddr().String() {
 			err = fmt.Errorf("node address mismatch: got %q, expected %q", session.GetNodeAddress(), c.NodeAddr())
-			ctx.JSON(http.StatusBadRequest, types.NewResponseError(6, err))
+			apierrors.ErrNodeMismatch.JSON(ctx, err)
 
 			return
 		}
@@ -115,23 +315,57 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 		accAddr, err := cosmossdk.AccAddressFromBech32(session.GetAccAddress())
 		if err != nil {
 			err = fmt.Errorf("decoding Bech32 account addr %q: %w", session.GetAccAddress(), err)
-			ctx.JSON(http.StatusInternalServerError, types.NewResponseError(6, err))
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
 
 		if got := req.AccAddr(); !got.Equals(accAddr) {
 			err = fmt.Errorf("account addr mismatch; got %q, expected %q", got, accAddr)
-			ctx.JSON(http.StatusUnauthorized, types.NewResponseError(6, err))
+			apierrors.ErrAccountMismatch.JSON(ctx, err)
 
 			return
 		}
 
-		// Add the peer to the active service.
+		// Reject handshake if the account is blocked locally or by the remote blocklist.
+		if c.Blocklist().Contains(accAddr.String()) {
+			err = fmt.Errorf("account %q is blocked", accAddr)
+			apierrors.ErrAccountBlocked.JSON(ctx, err)
+
+			return
+		}
+
+		// Reject handshake if the account reached the maximum concurrent sessions.
+		if maxSessions := c.MaxSessionsPerAccount(); maxSessions > 0 {
+			query = map[string]interface{}{
+				"acc_addr":  accAddr.String(),
+				"node_addr": c.NodeAddr().String(),
+			}
+
+			count, err := c.SessionStore().Count(query)
+			if err != nil {
+				err = fmt.Errorf("counting sessions for account %q in database: %w", accAddr, err)
+				apierrors.ErrInternal.JSON(ctx, err)
+
+				return
+			}
+
+			if uint64(count) >= uint64(maxSessions) {
+				err = fmt.Errorf("maximum session limit %d reached for account %q", maxSessions, accAddr)
+				apierrors.ErrMaxSessionsReached.JSON(ctx, err)
+
+				return
+			}
+		}
+
+		// Add the peer to the node's only service; any failure past this point rolls back the peer so that the
+		// handshake either fully succeeds or leaves no partial state in the service or the database.
 		id, data, err := c.Service().AddPeer(ctx, req.PeerRequest())
 		if err != nil {
+			log.Error("Failed to add peer to service", "id", session.GetID(), "cause", err)
+
 			err = fmt.Errorf("adding peer to service: %w", err)
-			ctx.JSON(http.StatusInternalServerError, types.NewResponseError(7, err))
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
@@ -139,8 +373,21 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 		// Encode and prepare the handshake response.
 		res := &node.InitHandshakeResult{Addrs: c.RemoteAddrs()}
 		if res.Data, err = json.Marshal(data); err != nil {
+			c.RollbackPeer(ctx, id, session.GetID())
+
 			err = fmt.Errorf("encoding add-peer service response: %w", err)
-			ctx.JSON(http.StatusInternalServerError, types.NewResponseError(8, err))
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		// Surface the tunnel settings of the service to the client.
+		mtu, keepalive := c.Tunnel()
+		if res.Data, err = withTunnelSettings(res.Data, mtu, keepalive); err != nil {
+			c.RollbackPeer(ctx, id, session.GetID())
+
+			err = fmt.Errorf("adding tunnel settings to add-peer service response: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
@@ -150,6 +397,7 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 			WithAccAddr(accAddr).
 			WithDuration(0).
 			WithID(session.GetID()).
+			WithLastSyncedBytes(math.ZeroInt()).
 			WithMaxBytes(session.GetMaxBytes()).
 			WithMaxDuration(session.GetMaxDuration()).
 			WithNodeAddr(c.NodeAddr()).
@@ -157,17 +405,46 @@ func handlerInitHandshake(c *core.Context) gin.HandlerFunc {
 			WithPeerMetadata(res.Data).
 			WithPeerRequest(req.PeerRequest()).
 			WithRxBytes(math.ZeroInt()).
+			WithRxBytesBase(math.ZeroInt()).
 			WithServiceType(c.Service().Type()).
 			WithSignature(nil).
-			WithTxBytes(math.ZeroInt())
+			WithTxBytes(math.ZeroInt()).
+			WithTxBytesBase(math.ZeroInt())
+
+		if err = c.SessionStore().InsertOne(item); err != nil {
+			log.Error("Failed to insert session into database", "id", item.GetID(), "peer_id", id, "cause", err)
+
+			c.RollbackPeer(ctx, id, item.GetID())
 
-		if err = operations.SessionInsertOne(c.Database(), item); err != nil {
 			err = fmt.Errorf("inserting session %d into database: %w", item.GetID(), err)
-			ctx.JSON(http.StatusInternalServerError, types.NewResponseError(9, err))
+			apierrors.ErrInternal.JSON(ctx, err)
 
 			return
 		}
 
+		// Record the connect event for the session.
+		event := models.NewSessionEventFromSession(item, models.SessionEventTypeConnect)
+		if err = operations.SessionEventInsertOne(c.Database(), event); err != nil {
+			c.RollbackPeer(ctx, id, item.GetID())
+
+			err = fmt.Errorf("inserting connect event for session %d into database: %w", item.GetID(), err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		// Record the creation of the session in the event log.
+		c.RecordSessionEvent(events.EventTypeSessionCreated, item)
+
+		// Notify the webhook of the added peer.
+		c.EmitEvent(webhook.EventTypePeerAdded, map[string]interface{}{
+			"acc_addr":   accAddr.String(),
+			"peer_id":    id,
+			"session_id": item.GetID(),
+		})
+
+		log.Info("Handshake completed", "id", item.GetID(), "acc_addr", accAddr, "peer_id", id)
+
 		// Return a successful response.
 		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
 	}
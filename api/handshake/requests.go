@@ -1,6 +1,7 @@
 package handshake
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/types"
@@ -8,6 +9,9 @@ import (
 	"github.com/sentinel-official/sentinel-go-sdk/node"
 )
 
+// errVerifyRequest is returned for handshake requests whose signature does not verify against their public key.
+var errVerifyRequest = errors.New("verifying request body")
+
 // InitHandshakeRequest represents the request for performing a handshake.
 type InitHandshakeRequest struct {
 	Body node.InitHandshakeRequestBody
@@ -24,7 +28,7 @@ func NewInitHandshakeRequest(c *gin.Context) (req *InitHandshakeRequest, err err
 
 	// Verify the request body.
 	if err := req.Body.Verify(); err != nil {
-		return nil, fmt.Errorf("verifying request body: %w", err)
+		return nil, fmt.Errorf("%w: %w", errVerifyRequest, err)
 	}
 
 	return req, nil
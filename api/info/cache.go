@@ -0,0 +1,44 @@
+package info
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/node"
+)
+
+// infoCache caches the assembled node information for a short duration, or until the version of the information
+// it was built from changes.
+// The cached result must not be modified; callers copy it before overriding the live fields.
+type infoCache struct {
+	expiresAt time.Time
+	result    *node.GetInfoResult
+	ttl       time.Duration
+	version   uint64
+
+	mu sync.Mutex
+}
+
+// newInfoCache creates a new infoCache with the given TTL, where zero disables caching.
+func newInfoCache(ttl time.Duration) *infoCache {
+	return &infoCache{ttl: ttl}
+}
+
+// Get returns the cached result if it has neither expired nor been built from another version, otherwise it rebuilds
+// and caches the result using fn.
+func (c *infoCache) Get(version uint64, fn func() *node.GetInfoResult) *node.GetInfoResult {
+	if c.ttl <= 0 {
+		return fn()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.result == nil || c.version != version || time.Now().After(c.expiresAt) {
+		c.result = fn()
+		c.expiresAt = time.Now().Add(c.ttl)
+		c.version = version
+	}
+
+	return c.result
+}
@@ -0,0 +1,74 @@
+package info
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/node"
+)
+
+// countingBuild returns a build function of results with a moniker counting the builds, and the counter.
+func countingBuild() (func() *node.GetInfoResult, *int) {
+	calls := 0
+
+	return func() *node.GetInfoResult {
+		calls++
+
+		return &node.GetInfoResult{Moniker: strconv.Itoa(calls)}
+	}, &calls
+}
+
+func TestInfoCacheWithinTTL(t *testing.T) {
+	cache := newInfoCache(time.Hour)
+	build, calls := countingBuild()
+
+	first := cache.Get(1, build)
+	for i := 0; i < 10; i++ {
+		if got := cache.Get(1, build); got != first {
+			t.Fatalf("expected the cached result, got a rebuilt one with moniker %q", got.Moniker)
+		}
+	}
+
+	if *calls != 1 {
+		t.Fatalf("expected one computation within the TTL, got %d", *calls)
+	}
+}
+
+func TestInfoCacheExpires(t *testing.T) {
+	cache := newInfoCache(10 * time.Millisecond)
+	build, calls := countingBuild()
+
+	cache.Get(1, build)
+	time.Sleep(20 * time.Millisecond)
+	cache.Get(1, build)
+
+	if *calls != 2 {
+		t.Fatalf("expected a computation after the TTL expired, got %d computations", *calls)
+	}
+}
+
+func TestInfoCacheVersionChange(t *testing.T) {
+	cache := newInfoCache(time.Hour)
+	build, calls := countingBuild()
+
+	cache.Get(1, build)
+	cache.Get(2, build)
+	cache.Get(2, build)
+
+	if *calls != 2 {
+		t.Fatalf("expected a computation per version, got %d computations", *calls)
+	}
+}
+
+func TestInfoCacheDisabled(t *testing.T) {
+	cache := newInfoCache(0)
+	build, calls := countingBuild()
+
+	cache.Get(1, build)
+	cache.Get(1, build)
+
+	if *calls != 2 {
+		t.Fatalf("expected a computation per call with caching disabled, got %d computations", *calls)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sentinel-official/sentinel-go-sdk/libs/geoip"
 	"github.com/sentinel-official/sentinel-go-sdk/node"
 	"github.com/sentinel-official/sentinel-go-sdk/types"
 	"github.com/sentinel-official/sentinel-go-sdk/version"
@@ -12,29 +11,45 @@ import (
 	"github.com/sentinel-official/sentinel-dvpnx/core"
 )
 
+// buildInfo assembles the node information from the context.
+func buildInfo(c *core.Context) *node.GetInfoResult {
+	dlSpeed, ulSpeed := c.SpeedtestResults()
+	if dlSpeed.IsZero() && ulSpeed.IsZero() {
+		// Fall back to the configured static speeds when no measurement is available.
+		dlSpeed, ulSpeed = c.StaticSpeedtestResults()
+	}
+
+	return &node.GetInfoResult{
+		Addr:         c.NodeAddr().String(),
+		Downlink:     ulSpeed.String(),
+		HandshakeDNS: false,
+		Location:     c.PublicLocation(),
+		Moniker:      c.Moniker(),
+		Peers:        c.Service().PeersLen(),
+		ServiceType:  c.Service().Type().String(),
+		Uplink:       dlSpeed.String(),
+		Version:      version.Get(),
+	}
+}
+
 // handlerGetInfo returns a handler function to retrieve node information.
+// The assembled information is cached for the configured TTL or until the location or speedtest results change,
+// while the peer counts are always served live.
 func handlerGetInfo(c *core.Context) gin.HandlerFunc {
+	cache := newInfoCache(c.InfoCacheTTL())
+
 	return func(ctx *gin.Context) {
-		dlSpeed, ulSpeed := c.SpeedtestResults()
-		loc := c.Location()
+		info := *cache.Get(c.InfoVersion(), func() *node.GetInfoResult { return buildInfo(c) })
+		info.Peers = c.Service().PeersLen()
 
 		// Construct the result structure with node information.
-		res := &node.GetInfoResult{
-			Addr:         c.NodeAddr().String(),
-			Downlink:     ulSpeed.String(),
-			HandshakeDNS: false,
-			Location: &geoip.Location{
-				City:        loc.City,
-				Country:     loc.Country,
-				CountryCode: loc.CountryCode,
-				Latitude:    loc.Latitude,
-				Longitude:   loc.Longitude,
-			},
-			Moniker:     c.Moniker(),
-			Peers:       c.Service().PeersLen(),
-			ServiceType: c.Service().Type().String(),
-			Uplink:      dlSpeed.String(),
-			Version:     version.Get(),
+		res := &GetInfoResult{
+			GetInfoResult: &info,
+			MaxPeers:      c.MaxPeers(),
+		}
+
+		if c.ExposeStartupTimings() {
+			res.StartupTimings = NewStartupPhaseResults(c.StartupTimings().Phases())
 		}
 
 		// Send the result as a JSON response with HTTP status 200.
@@ -0,0 +1,34 @@
+package info
+
+import (
+	"github.com/sentinel-official/sentinel-go-sdk/node"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// StartupPhaseResult represents the duration of a single startup phase in the response.
+type StartupPhaseResult struct {
+	Duration string `json:"duration"`
+	Name     string `json:"name"`
+}
+
+// NewStartupPhaseResults creates the StartupPhaseResult list from the startup phases.
+func NewStartupPhaseResults(items []core.StartupPhase) []*StartupPhaseResult {
+	res := make([]*StartupPhaseResult, 0, len(items))
+	for _, item := range items {
+		res = append(res, &StartupPhaseResult{
+			Duration: item.Duration.String(),
+			Name:     item.Name,
+		})
+	}
+
+	return res
+}
+
+// GetInfoResult represents the node information result, extending the SDK result with node-specific fields.
+type GetInfoResult struct {
+	*node.GetInfoResult
+
+	MaxPeers       uint                  `json:"max_peers"`                 // Effective maximum number of peers accepted by the node.
+	StartupTimings []*StartupPhaseResult `json:"startup_timings,omitempty"` // Durations of the startup phases, if exposed.
+}
@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/metrics"
+)
+
+// RegisterRoutes registers the routes for the metrics API.
+func RegisterRoutes(r gin.IRouter) {
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
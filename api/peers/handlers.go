@@ -0,0 +1,87 @@
+package peers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetPeers returns a handler function to list the peers of the service and the drift between them and the
+// session records, which the session sync workers are expected to keep in step.
+func handlerGetPeers(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Retrieve the peers from the service.
+		items, err := c.Service().PeerStatistics()
+		if err != nil {
+			err = fmt.Errorf("retrieving peer statistics from service: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		// Retrieve the sessions from the database.
+		sessions, err := c.SessionStore().Find(nil)
+		if err != nil {
+			err = fmt.Errorf("retrieving sessions from database: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		sessionIDs := make(map[string]uint64, len(sessions))
+		for i := range sessions {
+			sessionIDs[sessions[i].GetPeerID()] = sessions[i].GetID()
+		}
+
+		res := &GetPeersResult{
+			Missing: []*MissingPeerResult{},
+			Peers:   make([]*PeerResult, 0, len(items)),
+		}
+
+		for id, item := range items {
+			sessionID, ok := sessionIDs[id]
+			if !ok {
+				res.Untracked++
+			}
+
+			res.Peers = append(res.Peers, &PeerResult{
+				ID:        id,
+				RxBytes:   item.RxBytes,
+				SessionID: sessionID,
+				Tracked:   ok,
+				TxBytes:   item.TxBytes,
+				UpdatedAt: item.UpdatedAt,
+			})
+		}
+
+		// Sessions whose peer request was released no longer have a peer in the service.
+		for i := range sessions {
+			peerID := sessions[i].GetPeerID()
+			if _, ok := items[peerID]; ok || core.IsReleasedPeerID(peerID) {
+				continue
+			}
+
+			res.Missing = append(res.Missing, &MissingPeerResult{
+				PeerID:    peerID,
+				SessionID: sessions[i].GetID(),
+			})
+		}
+
+		slices.SortFunc(res.Peers, func(a, b *PeerResult) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+		slices.SortFunc(res.Missing, func(a, b *MissingPeerResult) int {
+			return strings.Compare(a.PeerID, b.PeerID)
+		})
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
@@ -0,0 +1,29 @@
+package peers
+
+import (
+	"time"
+)
+
+// PeerResult represents a single peer provisioned in the service.
+type PeerResult struct {
+	ID        string    `json:"id"`
+	RxBytes   int64     `json:"rx_bytes"`
+	SessionID uint64    `json:"session_id,omitempty"`
+	Tracked   bool      `json:"tracked"`
+	TxBytes   int64     `json:"tx_bytes"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MissingPeerResult represents a session record whose peer is not provisioned in the service.
+type MissingPeerResult struct {
+	PeerID    string `json:"peer_id"`
+	SessionID uint64 `json:"session_id"`
+}
+
+// GetPeersResult represents the peers of the service cross-referenced with the session records. Peers that are
+// not tracked have no session record, and missing peers are the session records with no peer in the service.
+type GetPeersResult struct {
+	Missing   []*MissingPeerResult `json:"missing"`
+	Peers     []*PeerResult        `json:"peers"`
+	Untracked int                  `json:"untracked"`
+}
@@ -0,0 +1,18 @@
+package peers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/api/admin"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// RegisterRoutes registers the routes for the peers API if an admin token is configured.
+// The listing reveals the peer IDs of the clients, so it is served only to admin requests.
+func RegisterRoutes(c *core.Context, r gin.IRouter) {
+	if c.AdminToken() == "" {
+		return
+	}
+
+	r.GET("/peers", admin.AuthMiddleware(c), handlerGetPeers(c))
+}
@@ -0,0 +1,41 @@
+package ping
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/metrics"
+)
+
+// handlerPing returns a handler function that echoes the client timestamp so clients can measure their latency to the node.
+func handlerPing(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Parse the request.
+		req, err := NewPingRequest(ctx)
+		if err != nil {
+			err = fmt.Errorf("parsing request from context: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		// Aggregate the round-trip time reported by the client if enabled.
+		if req.RTT > 0 && c.RecordClientLatency() {
+			metrics.ObserveClientLatency(req.RTT)
+		}
+
+		res := &PingResult{
+			ServerTime: time.Now().UnixMilli(),
+			Timestamp:  req.Timestamp,
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
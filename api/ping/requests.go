@@ -0,0 +1,55 @@
+package ping
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRTT is the largest client-reported round-trip time accepted for aggregation.
+const maxRTT = time.Minute
+
+// PingRequest represents the request for measuring the round-trip time to the node.
+type PingRequest struct {
+	Query struct {
+		RTT       string `form:"rtt"`
+		Timestamp string `form:"timestamp"`
+	}
+
+	RTT       time.Duration
+	Timestamp int64
+}
+
+// NewPingRequest parses and validates the ping request.
+func NewPingRequest(c *gin.Context) (req *PingRequest, err error) {
+	req = &PingRequest{}
+
+	// Bind the query parameters.
+	if err = c.ShouldBindQuery(&req.Query); err != nil {
+		return nil, fmt.Errorf("binding query: %w", err)
+	}
+
+	// Parse the optional client-supplied timestamp to echo back.
+	if req.Query.Timestamp != "" {
+		req.Timestamp, err = strconv.ParseInt(req.Query.Timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing timestamp %q: %w", req.Query.Timestamp, err)
+		}
+	}
+
+	// Parse the optional round-trip time measured by the client on a previous ping.
+	if req.Query.RTT != "" {
+		req.RTT, err = time.ParseDuration(req.Query.RTT)
+		if err != nil {
+			return nil, fmt.Errorf("parsing rtt %q: %w", req.Query.RTT, err)
+		}
+
+		if req.RTT <= 0 || req.RTT > maxRTT {
+			return nil, fmt.Errorf("rtt %s must be positive and at most %s", req.RTT, maxRTT)
+		}
+	}
+
+	return req, nil
+}
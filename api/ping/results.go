@@ -0,0 +1,7 @@
+package ping
+
+// PingResult represents the response to a ping request.
+type PingResult struct {
+	ServerTime int64 `json:"server_time"`
+	Timestamp  int64 `json:"timestamp,omitempty"`
+}
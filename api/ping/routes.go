@@ -0,0 +1,16 @@
+package ping
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// RegisterRoutes registers the routes for the ping API if the ping endpoint is enabled.
+func RegisterRoutes(c *core.Context, r gin.IRouter) {
+	if !c.PingEnabled() {
+		return
+	}
+
+	r.GET("/ping", handlerPing(c))
+}
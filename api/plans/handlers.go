@@ -0,0 +1,25 @@
+package plans
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetPlans returns a handler function to retrieve the pricing tiers advertised by the node.
+func handlerGetPlans(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		items := c.Plans()
+
+		res := make([]*PlanResult, 0, len(items))
+		for _, item := range items {
+			res = append(res, NewPlanResult(item))
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
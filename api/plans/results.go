@@ -0,0 +1,25 @@
+package plans
+
+import (
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+)
+
+// PlanResult represents a single pricing tier in the response.
+type PlanResult struct {
+	Description    string    `json:"description"`
+	GigabytePrices v1.Prices `json:"gigabyte_prices"`
+	HourlyPrices   v1.Prices `json:"hourly_prices"`
+	Name           string    `json:"name"`
+}
+
+// NewPlanResult creates a PlanResult from the plan configuration.
+func NewPlanResult(v *config.PlanConfig) *PlanResult {
+	return &PlanResult{
+		Description:    v.GetDescription(),
+		GigabytePrices: v.GetGigabytePrices(),
+		HourlyPrices:   v.GetHourlyPrices(),
+		Name:           v.GetName(),
+	}
+}
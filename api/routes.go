@@ -3,12 +3,45 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/sentinel-official/sentinel-dvpnx/api/admin"
+	"github.com/sentinel-official/sentinel-dvpnx/api/analytics"
+	"github.com/sentinel-official/sentinel-dvpnx/api/capabilities"
 	"github.com/sentinel-official/sentinel-dvpnx/api/handshake"
 	"github.com/sentinel-official/sentinel-dvpnx/api/info"
+	"github.com/sentinel-official/sentinel-dvpnx/api/peers"
+	"github.com/sentinel-official/sentinel-dvpnx/api/ping"
+	"github.com/sentinel-official/sentinel-dvpnx/api/plans"
+	"github.com/sentinel-official/sentinel-dvpnx/api/session"
+	"github.com/sentinel-official/sentinel-dvpnx/api/speedtest"
+	"github.com/sentinel-official/sentinel-dvpnx/api/status"
+	"github.com/sentinel-official/sentinel-dvpnx/api/workers"
 	"github.com/sentinel-official/sentinel-dvpnx/core"
 )
 
-func RegisterRoutes(c *core.Context, r gin.IRouter) {
-	handshake.RegisterRoutes(c, r)
-	info.RegisterRoutes(c, r)
+// RegisterHandshakeRoutes registers the handshake route, limited with handshakeLimiter.
+func RegisterHandshakeRoutes(c *core.Context, r gin.IRouter, handshakeLimiter gin.HandlerFunc) {
+	handshake.RegisterRoutes(c, r.Group("", handshakeLimiter))
+}
+
+// RegisterInfoRoutes registers the info route, limited with limiter, on a router serving the handshake route apart
+// from the other routes.
+func RegisterInfoRoutes(c *core.Context, r gin.IRouter, limiter gin.HandlerFunc) {
+	info.RegisterRoutes(c, r.Group("", limiter))
+}
+
+// RegisterRoutes registers the routes of all APIs other than the handshake, limited with limiter.
+func RegisterRoutes(c *core.Context, r gin.IRouter, limiter gin.HandlerFunc) {
+	g := r.Group("", limiter)
+
+	admin.RegisterRoutes(c, g)
+	analytics.RegisterRoutes(c, g)
+	capabilities.RegisterRoutes(c, g)
+	info.RegisterRoutes(c, g)
+	peers.RegisterRoutes(c, g)
+	ping.RegisterRoutes(c, g)
+	plans.RegisterRoutes(c, g)
+	session.RegisterRoutes(c, g)
+	speedtest.RegisterRoutes(c, g)
+	status.RegisterRoutes(c, g)
+	workers.RegisterRoutes(c, g)
 }
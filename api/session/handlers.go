@@ -0,0 +1,96 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetSession returns a handler function to retrieve a single session record by its ID.
+func handlerGetSession(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Parse the request.
+		req, err := NewGetSessionRequest(ctx)
+		if err != nil {
+			err = fmt.Errorf("parsing request from context: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		// Retrieve the session from the database.
+		query := map[string]interface{}{
+			"id": req.ID,
+		}
+
+		item, err := c.SessionStore().FindOne(query)
+		if err != nil {
+			err = fmt.Errorf("retrieving session %d from database: %w", req.ID, err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		if item == nil {
+			err = fmt.Errorf("session %d does not exist in database", req.ID)
+			apierrors.ErrSessionNotFound.JSON(ctx, err)
+
+			return
+		}
+
+		res := NewGetSessionResult(item, c.HumanReadableBytes())
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
+
+// handlerGetSessions returns a handler function to list a page of the session records.
+func handlerGetSessions(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Parse the request.
+		req, err := NewGetSessionsRequest(ctx)
+		if err != nil {
+			err = fmt.Errorf("parsing request from context: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		// Count the sessions and retrieve the requested page from the database.
+		total, err := c.SessionStore().Count(nil)
+		if err != nil {
+			err = fmt.Errorf("counting sessions in database: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		items, err := c.SessionStore().FindPaginated(nil, req.Limit, req.Offset, "id ASC")
+		if err != nil {
+			err = fmt.Errorf("retrieving sessions from database: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		res := &GetSessionsResult{
+			Limit:    req.Limit,
+			Offset:   req.Offset,
+			Sessions: make([]*SessionResult, 0, len(items)),
+			Total:    int(total),
+		}
+
+		for i := range items {
+			res.Sessions = append(res.Sessions, NewSessionResult(&items[i], c.HumanReadableBytes()))
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
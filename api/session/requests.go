@@ -0,0 +1,81 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bounds of the number of sessions returned in a single page.
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// GetSessionRequest represents the request for retrieving a single session.
+type GetSessionRequest struct {
+	ID uint64
+}
+
+// NewGetSessionRequest parses and validates the single session request.
+func NewGetSessionRequest(c *gin.Context) (req *GetSessionRequest, err error) {
+	req = &GetSessionRequest{}
+
+	// Parse the session ID from the path.
+	req.ID, err = strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing session id %q: %w", c.Param("id"), err)
+	}
+
+	return req, nil
+}
+
+// GetSessionsRequest represents the request for listing the sessions.
+type GetSessionsRequest struct {
+	Query struct {
+		Limit  string `form:"limit"`
+		Offset string `form:"offset"`
+	}
+
+	Limit  int
+	Offset int
+}
+
+// NewGetSessionsRequest parses and validates the sessions listing request.
+func NewGetSessionsRequest(c *gin.Context) (req *GetSessionsRequest, err error) {
+	req = &GetSessionsRequest{
+		Limit: defaultLimit,
+	}
+
+	// Bind the query parameters.
+	if err = c.ShouldBindQuery(&req.Query); err != nil {
+		return nil, fmt.Errorf("binding query: %w", err)
+	}
+
+	// Parse the optional page size.
+	if req.Query.Limit != "" {
+		req.Limit, err = strconv.Atoi(req.Query.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("parsing limit %q: %w", req.Query.Limit, err)
+		}
+
+		if req.Limit <= 0 || req.Limit > maxLimit {
+			return nil, fmt.Errorf("limit %d must be between 1 and %d", req.Limit, maxLimit)
+		}
+	}
+
+	// Parse the optional number of sessions to skip.
+	if req.Query.Offset != "" {
+		req.Offset, err = strconv.Atoi(req.Query.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("parsing offset %q: %w", req.Query.Offset, err)
+		}
+
+		if req.Offset < 0 {
+			return nil, fmt.Errorf("offset %d cannot be negative", req.Offset)
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"cosmossdk.io/math"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// SessionResult represents a single session record in the response. The peer request is never included.
+type SessionResult struct {
+	AccAddr      string `json:"acc_addr"`
+	Duration     string `json:"duration"`
+	ID           uint64 `json:"id"`
+	PeerID       string `json:"peer_id"`
+	RxBytes      string `json:"rx_bytes"`
+	RxBytesHuman string `json:"rx_bytes_human,omitempty"`
+	ServiceType  string `json:"service_type"`
+	TxBytes      string `json:"tx_bytes"`
+	TxBytesHuman string `json:"tx_bytes_human,omitempty"`
+}
+
+// NewSessionResult creates a SessionResult from the session record.
+// The human-readable byte fields are included only if humanReadable is true.
+func NewSessionResult(v *models.Session, humanReadable bool) *SessionResult {
+	res := &SessionResult{
+		AccAddr:     v.AccAddr,
+		Duration:    v.GetDuration().String(),
+		ID:          v.GetID(),
+		PeerID:      v.GetPeerID(),
+		RxBytes:     v.GetRxBytes().String(),
+		ServiceType: v.ServiceType,
+		TxBytes:     v.GetTxBytes().String(),
+	}
+
+	if humanReadable {
+		res.RxBytesHuman = core.FormatBytes(v.GetRxBytes())
+		res.TxBytesHuman = core.FormatBytes(v.GetTxBytes())
+	}
+
+	return res
+}
+
+// GetSessionsResult represents a page of session records with the total number of sessions.
+type GetSessionsResult struct {
+	Limit    int              `json:"limit"`
+	Offset   int              `json:"offset"`
+	Sessions []*SessionResult `json:"sessions"`
+	Total    int              `json:"total"`
+}
+
+// GetSessionResult represents a single session record with its usage against the allocation of the session.
+// The remaining fields are omitted for an allocation without a maximum.
+type GetSessionResult struct {
+	*SessionResult
+
+	MaxBytes            string `json:"max_bytes"`
+	MaxDuration         string `json:"max_duration"`
+	RemainingBytes      string `json:"remaining_bytes,omitempty"`
+	RemainingBytesHuman string `json:"remaining_bytes_human,omitempty"`
+	RemainingDuration   string `json:"remaining_duration,omitempty"`
+	TotalBytes          string `json:"total_bytes"`
+	TotalBytesHuman     string `json:"total_bytes_human,omitempty"`
+}
+
+// NewGetSessionResult creates a GetSessionResult from the session record.
+// The human-readable byte fields are included only if humanReadable is true.
+func NewGetSessionResult(v *models.Session, humanReadable bool) *GetSessionResult {
+	res := &GetSessionResult{
+		SessionResult: NewSessionResult(v, humanReadable),
+		MaxBytes:      v.GetMaxBytes().String(),
+		MaxDuration:   v.GetMaxDuration().String(),
+		TotalBytes:    v.GetTotalBytes().String(),
+	}
+
+	if maxBytes := v.GetMaxBytes(); maxBytes.IsPositive() {
+		remaining := math.MaxInt(maxBytes.Sub(v.GetTotalBytes()), math.ZeroInt())
+		res.RemainingBytes = remaining.String()
+
+		if humanReadable {
+			res.RemainingBytesHuman = core.FormatBytes(remaining)
+		}
+	}
+
+	if maxDuration := v.GetMaxDuration(); maxDuration > 0 {
+		res.RemainingDuration = max(maxDuration-v.GetDuration(), 0).String()
+	}
+
+	if humanReadable {
+		res.TotalBytesHuman = core.FormatBytes(v.GetTotalBytes())
+	}
+
+	return res
+}
@@ -0,0 +1,19 @@
+package session
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/api/admin"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// RegisterRoutes registers the routes for the sessions API if an admin token is configured.
+// The sessions reveal the account addresses of the clients, so they are served only to admin requests.
+func RegisterRoutes(c *core.Context, r gin.IRouter) {
+	if c.AdminToken() == "" {
+		return
+	}
+
+	r.GET("/sessions", admin.AuthMiddleware(c), handlerGetSessions(c))
+	r.GET("/sessions/:id", admin.AuthMiddleware(c), handlerGetSession(c))
+}
@@ -0,0 +1,43 @@
+package speedtest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetSpeedtests returns a handler function to list the most recent speed test results, newest first.
+func handlerGetSpeedtests(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Parse the request.
+		req, err := NewGetSpeedtestsRequest(ctx)
+		if err != nil {
+			err = fmt.Errorf("parsing request from context: %w", err)
+			apierrors.ErrInvalidRequest.JSON(ctx, err)
+
+			return
+		}
+
+		// Retrieve the speed test results from the database.
+		items, err := c.SpeedtestStore().FindRecent(req.Limit)
+		if err != nil {
+			err = fmt.Errorf("retrieving speedtests from database: %w", err)
+			apierrors.ErrInternal.JSON(ctx, err)
+
+			return
+		}
+
+		res := make([]*SpeedtestResult, 0, len(items))
+		for i := range items {
+			res = append(res, NewSpeedtestResult(&items[i]))
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
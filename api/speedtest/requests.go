@@ -0,0 +1,49 @@
+package speedtest
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bounds of the number of speed test results returned in a single response.
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// GetSpeedtestsRequest represents the request for listing the recent speed test results.
+type GetSpeedtestsRequest struct {
+	Query struct {
+		Limit string `form:"limit"`
+	}
+
+	Limit int
+}
+
+// NewGetSpeedtestsRequest parses and validates the speed test history request.
+func NewGetSpeedtestsRequest(c *gin.Context) (req *GetSpeedtestsRequest, err error) {
+	req = &GetSpeedtestsRequest{
+		Limit: defaultLimit,
+	}
+
+	// Bind the query parameters.
+	if err = c.ShouldBindQuery(&req.Query); err != nil {
+		return nil, fmt.Errorf("binding query: %w", err)
+	}
+
+	// Parse the optional number of results.
+	if req.Query.Limit != "" {
+		req.Limit, err = strconv.Atoi(req.Query.Limit)
+		if err != nil {
+			return nil, fmt.Errorf("parsing limit %q: %w", req.Query.Limit, err)
+		}
+
+		if req.Limit <= 0 || req.Limit > maxLimit {
+			return nil, fmt.Errorf("limit %d must be between 1 and %d", req.Limit, maxLimit)
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,23 @@
+package speedtest
+
+import (
+	"time"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// SpeedtestResult represents a single speed test result in the response.
+type SpeedtestResult struct {
+	CreatedAt           time.Time `json:"created_at"`
+	DownloadBytesPerSec string    `json:"download_bytes_per_sec"`
+	UploadBytesPerSec   string    `json:"upload_bytes_per_sec"`
+}
+
+// NewSpeedtestResult creates a SpeedtestResult from the speedtest record.
+func NewSpeedtestResult(v *models.Speedtest) *SpeedtestResult {
+	return &SpeedtestResult{
+		CreatedAt:           v.GetCreatedAt(),
+		DownloadBytesPerSec: v.GetDownloadBytesPerSec().String(),
+		UploadBytesPerSec:   v.GetUploadBytesPerSec().String(),
+	}
+}
@@ -0,0 +1,26 @@
+package status
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetStatus returns a handler function reporting that the node is up along with its uptime.
+// It makes no blockchain or service calls and writes the response without JSON encoding, so it is cheap enough
+// for load balancers and uptime checks to poll frequently.
+func handlerGetStatus(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var buf [64]byte
+
+		res := append(buf[:0], `{"ok":true,"uptime_seconds":`...)
+		res = strconv.AppendInt(res, int64(c.Uptime().Seconds()), 10)
+		res = append(res, '}')
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.Data(http.StatusOK, "application/json; charset=utf-8", res)
+	}
+}
@@ -0,0 +1,12 @@
+package status
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// RegisterRoutes registers the routes for the status API.
+func RegisterRoutes(c *core.Context, r gin.IRouter) {
+	r.GET("/status", handlerGetStatus(c))
+}
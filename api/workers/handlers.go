@@ -0,0 +1,25 @@
+package workers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// handlerGetWorkerSchedule returns a handler function to retrieve the last and next run times of the scheduler workers.
+func handlerGetWorkerSchedule(c *core.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		items := c.WorkerSchedule().Timings()
+
+		res := make([]*WorkerScheduleResult, 0, len(items))
+		for _, item := range items {
+			res = append(res, NewWorkerScheduleResult(item))
+		}
+
+		// Send the result as a JSON response with HTTP status 200.
+		ctx.JSON(http.StatusOK, types.NewResponseResult(res))
+	}
+}
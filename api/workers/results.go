@@ -0,0 +1,37 @@
+package workers
+
+import (
+	"time"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// WorkerScheduleResult represents the timing state of a single scheduler worker in the response.
+type WorkerScheduleResult struct {
+	Interval string     `json:"interval"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	Name     string     `json:"name"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	Running  bool       `json:"running"`
+}
+
+// NewWorkerScheduleResult creates a WorkerScheduleResult from the worker timing.
+func NewWorkerScheduleResult(v core.WorkerTiming) *WorkerScheduleResult {
+	res := &WorkerScheduleResult{
+		Interval: v.Interval.String(),
+		Name:     v.Name,
+		Running:  v.Running,
+	}
+
+	if !v.LastRun.IsZero() {
+		lastRun := v.LastRun.UTC()
+		res.LastRun = &lastRun
+	}
+
+	if nextRun := v.NextRun(); !nextRun.IsZero() {
+		nextRun = nextRun.UTC()
+		res.NextRun = &nextRun
+	}
+
+	return res
+}
@@ -0,0 +1,16 @@
+package workers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// RegisterRoutes registers the routes for the workers API if the worker schedule is exposed.
+func RegisterRoutes(c *core.Context, r gin.IRouter) {
+	if c.WorkerSchedule() == nil {
+		return
+	}
+
+	r.GET("/workers/schedule", handlerGetWorkerSchedule(c))
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+	"github.com/sentinel-official/sentinel-go-sdk/openvpn"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v2ray"
+	"github.com/sentinel-official/sentinel-go-sdk/wireguard"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/node"
+)
+
+// readLine reads a single line from the reader one byte at a time, so that the input following the line is left
+// for the keyring prompts.
+func readLine(r io.Reader) (string, error) {
+	var (
+		buf []byte
+		b   = make([]byte, 1)
+	)
+
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+
+			buf = append(buf, b[0])
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return "", err //nolint:wrapcheck
+		}
+	}
+
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// NewDeregisterCmd creates and returns a new Cobra command to take the node out of the network.
+func NewDeregisterCmd(cfg *config.Config) *cobra.Command {
+	// Initialize default server configs for all supported services
+	cfg.Services = map[types.ServiceType]types.ServiceConfig{
+		types.ServiceTypeOpenVPN:   openvpn.DefaultServerConfig(),
+		types.ServiceTypeV2Ray:     v2ray.DefaultServerConfig(),
+		types.ServiceTypeWireGuard: wireguard.DefaultServerConfig(),
+	}
+
+	var (
+		wipeDatabase bool
+		yes          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "deregister",
+		Short: "Deregister the node from the network",
+		Long: `Deregisters the node for decommissioning. Sets up the context without starting the scheduler,
+API server, or service, broadcasts a transaction marking the node as inactive so that clients no
+longer start sessions with it, and removes the peers of the recorded sessions from the service.
+
+With --wipe-database, the session usage recorded in the local database is synced with the
+blockchain first and the records are deleted afterwards; nothing is changed if the sync fails.
+Asks for confirmation before proceeding unless --yes is given. The node must not be running.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				_, _ = fmt.Fprint(cmd.ErrOrStderr(), "Deregister the node from the network? [y/N]: ")
+
+				answer, err := readLine(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("reading confirmation: %w", err)
+				}
+
+				if v := strings.ToLower(answer); v != "y" && v != "yes" {
+					return errors.New("deregistration aborted")
+				}
+			}
+
+			// Retrieve the home directory from the configuration
+			homeDir := viper.GetString("home")
+
+			n := node.New("node")
+
+			// Refuse to deregister while the node is running with the same home directory.
+			if err := n.AcquireLock(homeDir); err != nil {
+				return fmt.Errorf("acquiring lock: %w", err)
+			}
+
+			log.Info("Setting up context")
+
+			if err := n.SetupContext(cmd.Context(), homeDir, cfg.Keyring.GetInput(), cfg); err != nil {
+				_ = n.ReleaseLock()
+
+				return fmt.Errorf("setting up context: %w", err)
+			}
+
+			log.Info("Deregistering node")
+
+			if err := n.Deregister(cmd.Context(), wipeDatabase); err != nil {
+				return fmt.Errorf("deregistering node: %w", err)
+			}
+
+			log.Info("Node deregistered successfully")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&wipeDatabase, "wipe-database", false, "delete the records of the local database after deregistering")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip the confirmation prompt")
+
+	return cmd
+}
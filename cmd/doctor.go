@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/openvpn"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v2ray"
+	"github.com/sentinel-official/sentinel-go-sdk/wireguard"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database"
+)
+
+// Diagnostic check statuses reported by the doctor command.
+const (
+	doctorStatusPass = "PASS"
+	doctorStatusWarn = "WARN"
+	doctorStatusFail = "FAIL"
+)
+
+// doctorMinFreeDiskSpace is the free disk space below which the doctor command reports a warning.
+const doctorMinFreeDiskSpace = 1 << 30
+
+// doctorCheck represents a single diagnostic check and its outcome.
+type doctorCheck struct {
+	name string
+	fn   func(ctx context.Context) (status, detail string)
+}
+
+// doctor holds the state shared by the diagnostic checks.
+type doctor struct {
+	cfg     *config.Config
+	ctx     *core.Context
+	homeDir string
+}
+
+// checkConfig verifies that the configuration is valid.
+func (d *doctor) checkConfig(_ context.Context) (string, string) {
+	if err := d.cfg.Validate(); err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	return doctorStatusPass, "configuration is valid"
+}
+
+// checkKey verifies that the transaction key exists in the keyring and its account is funded.
+func (d *doctor) checkKey(ctx context.Context) (string, string) {
+	if d.ctx.Client() == nil {
+		return doctorStatusFail, "blockchain client is not initialized"
+	}
+
+	name := d.cfg.Tx.GetFromName()
+
+	addr, err := d.ctx.Client().KeyAddr(name)
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("getting addr for key %q: %s", name, err)
+	}
+
+	acc, err := d.ctx.Client().Account(ctx, addr)
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("querying account %q: %s", addr, err)
+	}
+
+	if acc == nil {
+		return doctorStatusFail, fmt.Sprintf("account %s does not exist on the blockchain", addr)
+	}
+
+	balances, _, err := d.ctx.Client().Balances(ctx, addr, nil)
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("querying balances of account %q: %s", addr, err)
+	}
+
+	if balances.IsZero() {
+		return doctorStatusWarn, fmt.Sprintf("account %s has no balance to pay transaction fees", addr)
+	}
+
+	return doctorStatusPass, fmt.Sprintf("key %q with account %s holds %s", name, addr, balances)
+}
+
+// checkRPC verifies that the RPC endpoint is reachable and synced.
+func (d *doctor) checkRPC(ctx context.Context) (string, string) {
+	if d.ctx.Client() == nil {
+		return doctorStatusFail, "blockchain client is not initialized"
+	}
+
+	client, err := d.ctx.Client().HTTP()
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("creating RPC client: %s", err)
+	}
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("querying RPC status of %q: %s", d.cfg.RPC.GetAddr(), err)
+	}
+
+	if status.SyncInfo.CatchingUp {
+		return doctorStatusWarn, fmt.Sprintf("RPC %q is catching up at height %d", d.cfg.RPC.GetAddr(), status.SyncInfo.LatestBlockHeight)
+	}
+
+	return doctorStatusPass, fmt.Sprintf("RPC %q is synced at height %d", d.cfg.RPC.GetAddr(), status.SyncInfo.LatestBlockHeight)
+}
+
+// checkOracle verifies that the oracle returns quote prices for the configured prices.
+func (d *doctor) checkOracle(ctx context.Context) (string, string) {
+	client := d.ctx.OracleClient()
+	if client == nil {
+		return doctorStatusPass, "oracle is disabled"
+	}
+
+	prices := d.cfg.Node.GetGigabytePrices()
+	prices = append(prices, d.cfg.Node.GetHourlyPrices()...)
+
+	for _, price := range prices {
+		if _, err := price.UpdateQuoteValue(ctx, client.GetQuotePrice); err != nil {
+			return doctorStatusFail, fmt.Sprintf("getting quote price for denom %q: %s", price.Denom, err)
+		}
+	}
+
+	return doctorStatusPass, fmt.Sprintf("oracle %q returned quote prices for %d price(s)", d.cfg.Oracle.GetName(), len(prices))
+}
+
+// checkService verifies that the service binaries are installed and the service is not already running.
+func (d *doctor) checkService(_ context.Context) (string, string) {
+	serviceType := d.cfg.Node.GetServiceType()
+
+	if err := core.CheckServiceBinaries(serviceType); err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	service, err := core.NewService(d.homeDir, d.cfg)
+	if err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	ok, err := service.IsRunning()
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("checking service %q status: %s", serviceType, err)
+	}
+
+	if ok {
+		return doctorStatusWarn, fmt.Sprintf("service %q is already running", serviceType)
+	}
+
+	return doctorStatusPass, fmt.Sprintf("service %q binaries are installed", serviceType)
+}
+
+// checkTLS verifies that the TLS certificate is valid, unexpired, and covers the remote addresses.
+func (d *doctor) checkTLS(_ context.Context) (string, string) {
+	if !d.cfg.Node.GetAPITLSEnable() {
+		return doctorStatusPass, "TLS is disabled, terminated by a reverse proxy"
+	}
+
+	if err := core.ValidateTLS(d.homeDir, d.cfg.Node.GetRemoteAddrs()); err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	return doctorStatusPass, fmt.Sprintf("certificate covers remote addrs %v", d.cfg.Node.GetRemoteAddrs())
+}
+
+// checkDatabase verifies the integrity of the database.
+func (d *doctor) checkDatabase(_ context.Context) (string, string) {
+	db, err := d.ctx.OpenDatabase(d.cfg.Database)
+	if err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	defer func() {
+		if v, err := db.DB(); err == nil {
+			_ = v.Close()
+		}
+	}()
+
+	if err := database.CheckIntegrity(db); err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	if d.cfg.Database.GetDriver() != config.DatabaseDriverSQLite {
+		return doctorStatusPass, fmt.Sprintf("%s database is reachable", d.cfg.Database.GetDriver())
+	}
+
+	return doctorStatusPass, fmt.Sprintf("database %q passed the integrity check", d.ctx.DatabaseFile())
+}
+
+// checkDiskSpace verifies that the home directory has enough free disk space.
+func (d *doctor) checkDiskSpace(_ context.Context) (string, string) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(d.homeDir, &stat); err != nil {
+		return doctorStatusFail, fmt.Sprintf("getting file system stats of %q: %s", d.homeDir, err)
+	}
+
+	free := stat.Bavail * uint64(stat.Bsize)
+	if free < doctorMinFreeDiskSpace {
+		return doctorStatusWarn, fmt.Sprintf("only %d MiB free in %q", free>>20, d.homeDir)
+	}
+
+	return doctorStatusPass, fmt.Sprintf("%d MiB free in %q", free>>20, d.homeDir)
+}
+
+// checkReachability verifies that the API port can be reached through the remote addresses.
+// A temporary listener is opened on the API port if it is not already in use by a running node.
+func (d *doctor) checkReachability(ctx context.Context) (string, string) {
+	lc := &net.ListenConfig{}
+
+	listener, err := lc.Listen(ctx, "tcp", d.cfg.Node.APIListenAddr())
+	if err == nil {
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				_ = conn.Close()
+			}
+		}()
+
+		defer func() {
+			_ = listener.Close()
+		}()
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+
+	for _, addr := range d.cfg.Node.APIAddrs() {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return doctorStatusWarn, fmt.Sprintf("dialing %q: %s (reachability cannot be tested from behind some NATs)", addr, err)
+		}
+
+		_ = conn.Close()
+	}
+
+	return doctorStatusPass, fmt.Sprintf("API port is reachable at %v", d.cfg.Node.APIAddrs())
+}
+
+// writeDoctorResult writes the outcome of a diagnostic check as a line of the report.
+func writeDoctorResult(w io.Writer, name, status, detail string) {
+	_, _ = fmt.Fprintf(w, "[%s] %-12s %s\n", status, name, detail)
+}
+
+// run executes the diagnostic checks, writes a report, and returns the number of failed checks.
+func (d *doctor) run(ctx context.Context, w io.Writer) int {
+	checks := []doctorCheck{
+		{name: "config", fn: d.checkConfig},
+		{name: "key", fn: d.checkKey},
+		{name: "rpc", fn: d.checkRPC},
+		{name: "oracle", fn: d.checkOracle},
+		{name: "service", fn: d.checkService},
+		{name: "tls", fn: d.checkTLS},
+		{name: "database", fn: d.checkDatabase},
+		{name: "disk", fn: d.checkDiskSpace},
+		{name: "reachability", fn: d.checkReachability},
+	}
+
+	failed := 0
+
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		status, detail := check.fn(checkCtx)
+
+		cancel()
+
+		if status == doctorStatusFail {
+			failed++
+		}
+
+		writeDoctorResult(w, check.name, status, detail)
+	}
+
+	return failed
+}
+
+// NewDoctorCmd creates and returns a new Cobra command for running node diagnostics.
+func NewDoctorCmd(cfg *config.Config) *cobra.Command {
+	// Initialize default server configs for all supported services
+	cfg.Services = map[types.ServiceType]types.ServiceConfig{
+		types.ServiceTypeOpenVPN:   openvpn.DefaultServerConfig(),
+		types.ServiceTypeV2Ray:     v2ray.DefaultServerConfig(),
+		types.ServiceTypeWireGuard: wireguard.DefaultServerConfig(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Run diagnostics on the node setup",
+		Long: `Runs diagnostic checks on the node setup and prints a pass/warn/fail report. The checks cover
+the configuration, keyring key and account balance, RPC reachability and sync status, oracle
+connectivity, service binaries and status, TLS certificate, database integrity, free disk space,
+and public reachability of the API port. Exits with an error if any check fails.`,
+		Annotations: map[string]string{
+			annotationSkipConfigValidation: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			homeDir := viper.GetString("home")
+
+			d := &doctor{
+				cfg:     cfg,
+				ctx:     core.NewContext().WithHomeDir(homeDir),
+				homeDir: filepath.Clean(homeDir),
+			}
+
+			// The setup of the clients and the other checks read the configuration through getters that assume it is
+			// valid, so an invalid configuration is reported on its own.
+			if status, detail := d.checkConfig(cmd.Context()); status == doctorStatusFail {
+				writeDoctorResult(cmd.OutOrStdout(), "config", status, detail)
+
+				return errors.New("1 check(s) failed")
+			}
+
+			// Initialize the clients used by the checks; failures are reported by the checks themselves.
+			if err := d.ctx.SetupClient(cfg); err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "setting up client: %s\n", err)
+			}
+
+			if err := d.ctx.SetupOracleClient(cfg); err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "setting up oracle client: %s\n", err)
+			}
+
+			if failed := d.run(cmd.Context(), cmd.OutOrStdout()); failed > 0 {
+				return fmt.Errorf("%d check(s) failed", failed)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// earningsSummary holds the estimated earnings of the sessions aggregated for a single billing mode.
+type earningsSummary struct {
+	amounts  map[string]math.LegacyDec
+	bytes    math.Int
+	duration time.Duration
+	sessions int
+}
+
+// writeEarnings writes the earnings summaries as a table sorted by billing mode and denom.
+func writeEarnings(w io.Writer, summaries map[string]*earningsSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "MODE\tSESSIONS\tBYTES\tDURATION\tDENOM\tESTIMATE")
+
+	modes := make([]string, 0, len(summaries))
+	for mode := range summaries {
+		modes = append(modes, mode)
+	}
+
+	slices.Sort(modes)
+
+	for _, mode := range modes {
+		s := summaries[mode]
+
+		denoms := make([]string, 0, len(s.amounts))
+		for denom := range s.amounts {
+			denoms = append(denoms, denom)
+		}
+
+		slices.Sort(denoms)
+
+		if len(denoms) == 0 {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t-\t-\n", mode, s.sessions, s.bytes, s.duration)
+
+			continue
+		}
+
+		for _, denom := range denoms {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n", mode, s.sessions, s.bytes, s.duration, denom, s.amounts[denom])
+		}
+	}
+
+	return tw.Flush() //nolint:wrapcheck
+}
+
+// NewEarningsCmd creates and returns a new Cobra command for estimating the node earnings.
+func NewEarningsCmd(cfg *config.Config) *cobra.Command {
+	var since time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "earnings",
+		Short: "Estimate the node earnings from the local database",
+		Long: `Estimates the node earnings from the sessions recorded in the local database and the prices
+advertised in the configuration. Sessions limited by bytes are priced per gigabyte and sessions limited
+by duration are priced per hour. Prints a summary broken down by billing mode and denom. Use --since to
+include only sessions created within the given duration. A database shared by several nodes is limited
+to the sessions of the node of the transaction key.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if since < 0 {
+				return errors.New("since cannot be negative")
+			}
+
+			homeDir := viper.GetString("home")
+
+			db, err := core.NewContext().WithHomeDir(homeDir).OpenDatabase(cfg.Database)
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			defer func() {
+				if v, err := db.DB(); err == nil {
+					_ = v.Close()
+				}
+			}()
+
+			// Restrict the sessions to those created within the given duration.
+			if since > 0 {
+				db = db.Where("created_at >= ?", time.Now().Add(-since))
+			}
+
+			store, err := newSessionStore(cfg, db)
+			if err != nil {
+				return err
+			}
+
+			items, err := store.Find(nil)
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			gigabytePrices := cfg.Node.GetGigabytePrices()
+			hourlyPrices := cfg.Node.GetHourlyPrices()
+
+			// Aggregate the estimated earnings by billing mode and denom.
+			summaries := make(map[string]*earningsSummary)
+
+			for i := range items {
+				mode, earnings := core.EstimateSessionEarnings(&items[i], gigabytePrices, hourlyPrices)
+
+				s, ok := summaries[mode]
+				if !ok {
+					s = &earningsSummary{
+						amounts: make(map[string]math.LegacyDec),
+						bytes:   math.ZeroInt(),
+					}
+					summaries[mode] = s
+				}
+
+				s.bytes = s.bytes.Add(items[i].GetTotalBytes())
+				s.duration += items[i].GetDuration()
+				s.sessions++
+
+				for denom, amount := range earnings {
+					if v, ok := s.amounts[denom]; ok {
+						amount = amount.Add(v)
+					}
+
+					s.amounts[denom] = amount
+				}
+			}
+
+			return writeEarnings(cmd.OutOrStdout(), summaries)
+		},
+	}
+
+	cmd.Flags().DurationVar(&since, "since", 0, "include only sessions created within this duration (0 for all sessions)")
+
+	return cmd
+}
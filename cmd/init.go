@@ -6,7 +6,6 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/sentinel-official/sentinel-go-sdk/libs/crypto"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
 	"github.com/sentinel-official/sentinel-go-sdk/openvpn"
 	"github.com/sentinel-official/sentinel-go-sdk/types"
@@ -17,8 +16,57 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
 )
 
+// writeAppConfigWithTemplate renders the config file with a custom template, replacing the file only if the
+// rendered configuration can be loaded and passes validation.
+func writeAppConfigWithTemplate(cfg *config.Config, file, templateFile string) error {
+	// Render the template into a staging file next to the config file
+	f, err := os.CreateTemp(filepath.Dir(file), ".config-*.toml")
+	if err != nil {
+		return fmt.Errorf("creating staging file: %w", err)
+	}
+
+	_ = f.Close()
+
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	if err := cfg.WriteAppConfigWithTemplate(f.Name(), templateFile); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// Load the rendered configuration and validate it
+	v := viper.New()
+	v.SetConfigFile(f.Name())
+	v.SetConfigType("toml")
+
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("reading rendered config: %w", err)
+	}
+
+	rendered := config.DefaultConfig()
+	if err := v.Unmarshal(rendered); err != nil {
+		return fmt.Errorf("unmarshaling rendered config: %w", err)
+	}
+
+	rendered.Keyring.HomeDir = cfg.Keyring.HomeDir
+	rendered.Keyring.Input = cfg.Keyring.Input
+
+	if err := rendered.Validate(); err != nil {
+		return fmt.Errorf("validating rendered config: %w", err)
+	}
+
+	// Replace the config file with the validated one
+	if err := os.Rename(f.Name(), file); err != nil {
+		return fmt.Errorf("replacing file %q: %w", file, err)
+	}
+
+	return nil
+}
+
 // NewInitCmd creates and returns a new Cobra command for initializing the application configuration.
 func NewInitCmd(cfg *config.Config) *cobra.Command {
 	// Initialize default server configs for all supported services
@@ -30,9 +78,10 @@ func NewInitCmd(cfg *config.Config) *cobra.Command {
 
 	// Declare variables for CLI flags
 	var (
-		force       bool
-		skipTLS     bool
-		skipService bool
+		configTemplate string
+		force          bool
+		skipTLS        bool
+		skipService    bool
 	)
 
 	cmd := &cobra.Command{
@@ -40,7 +89,8 @@ func NewInitCmd(cfg *config.Config) *cobra.Command {
 		Short: "Initialize the application configuration",
 		Long: `Creates the application home directory and generates a default config.toml file.
 If a configuration file already exists, this command will abort unless the "force" flag
-is set to overwrite the existing configuration.`,
+is set to overwrite the existing configuration. A custom template can be supplied with the
+"config-template" flag; the rendered configuration must pass validation to be written.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Create the home directory if it doesn't exist
 			homeDir := viper.GetString("home")
@@ -59,30 +109,25 @@ is set to overwrite the existing configuration.`,
 
 			// Write default config only if file doesn't exist or force flag is set
 			if !exists || force {
-				log.Info("Writing app config", "file", cfgFile)
-
-				if err := cfg.WriteAppConfig(cfgFile); err != nil {
-					return fmt.Errorf("writing config file %q: %w", cfgFile, err)
+				if configTemplate == "" {
+					log.Info("Writing app config", "file", cfgFile)
+
+					if err := cfg.WriteAppConfig(cfgFile); err != nil {
+						return fmt.Errorf("writing config file %q: %w", cfgFile, err)
+					}
+				} else {
+					log.Info("Writing app config", "file", cfgFile, "template", configTemplate)
+
+					if err := writeAppConfigWithTemplate(cfg, cfgFile, configTemplate); err != nil {
+						return fmt.Errorf("writing config file %q with template %q: %w", cfgFile, configTemplate, err)
+					}
 				}
 			}
 
-			// Generate TLS keys if "skipTLS" is disabled
-			if !skipTLS {
-				log.Info("Initializing PKI with CA certificate and key", "dir", homeDir)
-
-				pki := crypto.NewPKI(homeDir)
-				if err := pki.Init(); err != nil {
-					return fmt.Errorf("initializing PKI: %w", err)
-				}
-
-				opts := []crypto.CertOption{
-					crypto.CertSAN(cfg.Node.GetRemoteAddrs()...),
-				}
-
-				log.Info("Issuing certificate and key", "name", "tls")
-
-				if _, _, err := pki.Issue("tls", opts...); err != nil {
-					return fmt.Errorf("issuing TLS certificate and key: %w", err)
+			// Generate TLS keys if "skipTLS" is disabled and the API server serves TLS
+			if !skipTLS && cfg.Node.GetAPITLSEnable() {
+				if err := core.InitPKI(homeDir, cfg.Node.GetRemoteAddrs()); err != nil {
+					return err //nolint:wrapcheck
 				}
 			}
 
@@ -127,6 +172,7 @@ is set to overwrite the existing configuration.`,
 	cfg.Services[types.ServiceTypeWireGuard].SetForFlags(cmd.Flags(), "wireguard")
 
 	// Bind command-line flags to local variables
+	cmd.Flags().StringVar(&configTemplate, "config-template", configTemplate, "path to a custom config template used instead of the built-in one")
 	cmd.Flags().BoolVar(&force, "force", force, "overwrite the existing configuration file if it exists")
 	cmd.Flags().BoolVar(&skipTLS, "skip-tls", false, "skip TLS key and certificate generation")
 	cmd.Flags().BoolVar(&skipService, "skip-service", false, "skip initialization of the selected service")
@@ -16,6 +16,10 @@ import (
 	"github.com/sentinel-official/sentinel-dvpnx/config"
 )
 
+// annotationSkipConfigValidation marks the commands that validate the configuration themselves, such as the doctor
+// command reporting an invalid configuration as one of its checks.
+const annotationSkipConfigValidation = "skip_config_validation"
+
 // NewRootCmd returns the main/root command for the Sentinel dVPN node CLI.
 func NewRootCmd(userDir string) *cobra.Command {
 	// Declare variables for CLI flags
@@ -92,10 +96,23 @@ tools for key management and node initialization, ensuring privacy, performance,
 			cfg.Keyring.HomeDir = homeDir
 			cfg.Keyring.Input = cmd.InOrStdin()
 
-			log.Info("Validating configuration")
+			// Validate the configuration, unless the command reports an invalid configuration itself.
+			if _, ok := cmd.Annotations[annotationSkipConfigValidation]; !ok {
+				log.Info("Validating configuration")
+
+				if err := cfg.Validate(); err != nil {
+					return fmt.Errorf("validating config: %w", err)
+				}
+			}
+
+			// Answer the keyring passphrase prompts from the configured source, if any.
+			if cfg.KeyringPassphrase.IsSet() {
+				input, err := cfg.KeyringPassphrase.Reader()
+				if err != nil {
+					return fmt.Errorf("reading keyring passphrase: %w", err)
+				}
 
-			if err := cfg.Validate(); err != nil {
-				return fmt.Errorf("validating config: %w", err)
+				cfg.Keyring.Input = input
 			}
 
 			return nil
@@ -106,7 +123,12 @@ tools for key management and node initialization, ensuring privacy, performance,
 	rootCmd.AddCommand(
 		cmd.NewKeysCmd(cfg.Keyring),
 		cmd.NewVersionCmd(),
+		NewDeregisterCmd(cfg),
+		NewDoctorCmd(cfg),
+		NewEarningsCmd(cfg),
 		NewInitCmd(cfg),
+		NewResetTLSCmd(cfg),
+		NewSessionsCmd(cfg),
 		NewStartCmd(cfg),
 	)
 
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// openSessionsDatabase opens the configured database of the node.
+func openSessionsDatabase(cfg *config.Config) (*gorm.DB, func(), error) {
+	homeDir := viper.GetString("home")
+
+	db, err := core.NewContext().WithHomeDir(homeDir).OpenDatabase(cfg.Database)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck
+	}
+
+	closeFunc := func() {
+		if v, err := db.DB(); err == nil {
+			_ = v.Close()
+		}
+	}
+
+	return db, closeFunc, nil
+}
+
+// newSessionStore returns the session store of the database, scoped to the sessions of the node of the transaction
+// key if the database is shared by several nodes.
+func newSessionStore(cfg *config.Config, db *gorm.DB) (*database.GormSessionStore, error) {
+	store := database.NewGormSessionStore(db)
+	if !database.IsShared(db) {
+		return store, nil
+	}
+
+	addr, err := core.KeyNodeAddr(cfg)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return store.WithNodeAddr(addr), nil
+}
+
+// sessionsExportCmd creates a command that exports the active sessions for a handover to another node instance.
+func sessionsExportCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export the active sessions for a handover to another node instance",
+		Long: `Exports the active sessions recorded in the local database, including their peer requests and current
+byte totals, as JSON to the given file. The file can be imported by another node instance with the
+import command so that the sessions continue there without losing usage. A database shared by several
+nodes is limited to the sessions of the node of the transaction key.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, closeFunc, err := openSessionsDatabase(cfg)
+			if err != nil {
+				return err
+			}
+
+			defer closeFunc()
+
+			store, err := newSessionStore(cfg, db)
+			if err != nil {
+				return err
+			}
+
+			items, err := store.Find(nil)
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			res := make([]*core.HandoverSession, 0, len(items))
+			for i := range items {
+				res = append(res, core.NewHandoverSession(&items[i]))
+			}
+
+			file, err := os.Create(args[0])
+			if err != nil {
+				return fmt.Errorf("creating output file %q: %w", args[0], err)
+			}
+
+			defer func() {
+				_ = file.Close()
+			}()
+
+			enc := json.NewEncoder(file)
+			enc.SetIndent("", "  ")
+
+			if err := enc.Encode(res); err != nil {
+				return fmt.Errorf("encoding sessions: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d session(s)\n", len(res))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// sessionsImportCmd creates a command that imports the sessions exported by another node instance.
+func sessionsImportCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import the sessions exported by another node instance",
+		Long: `Imports the sessions exported by another node instance into the local database. Run it before
+starting the node; the node re-adds the peers of the imported sessions to the service at startup, and
+the usage of each re-added peer is added to the imported byte totals. Sessions that already exist in
+the local database are skipped, as are the sessions of other nodes in a database shared by several
+nodes.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("opening input file %q: %w", args[0], err)
+			}
+
+			defer func() {
+				_ = file.Close()
+			}()
+
+			items, err := readHandoverSessions(file, cfg)
+			if err != nil {
+				return err
+			}
+
+			db, closeFunc, err := openSessionsDatabase(cfg)
+			if err != nil {
+				return err
+			}
+
+			defer closeFunc()
+
+			store, err := newSessionStore(cfg, db)
+			if err != nil {
+				return err
+			}
+
+			sessions := make([]models.Session, 0, len(items))
+
+			for _, item := range items {
+				// Sessions of other nodes would not be restored by this node, nor be visible to it.
+				if !store.OwnsNodeAddr(item.NodeAddr) {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Skipping session %d: belongs to node %s\n", item.ID, item.NodeAddr)
+
+					continue
+				}
+
+				query := map[string]interface{}{
+					"id": item.ID,
+				}
+
+				session, err := store.FindOne(query)
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				if session != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Skipping session %d: already exists\n", item.ID)
+
+					continue
+				}
+
+				sessions = append(sessions, *item.Session())
+			}
+
+			if len(sessions) > 0 {
+				if err := store.InsertMany(sessions); err != nil {
+					return err //nolint:wrapcheck
+				}
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Imported %d session(s)\n", len(sessions))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// readHandoverSessions decodes and validates the exported sessions against the configured service type.
+func readHandoverSessions(r io.Reader, cfg *config.Config) ([]*core.HandoverSession, error) {
+	var items []*core.HandoverSession
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("decoding sessions: %w", err)
+	}
+
+	serviceType := cfg.Node.GetServiceType().String()
+
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		if item.ServiceType != serviceType {
+			return nil, fmt.Errorf("service type %q of session %d does not match the configured %q", item.ServiceType, item.ID, serviceType)
+		}
+	}
+
+	return items, nil
+}
+
+// NewSessionsCmd creates and returns a new Cobra command for handing over sessions between node instances.
+func NewSessionsCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Hand over active sessions between node instances",
+	}
+
+	cmd.AddCommand(
+		sessionsExportCmd(cfg),
+		sessionsImportCmd(cfg),
+	)
+
+	return cmd
+}
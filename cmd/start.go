@@ -27,11 +27,16 @@ func NewStartCmd(cfg *config.Config) *cobra.Command {
 		types.ServiceTypeWireGuard: wireguard.DefaultServerConfig(),
 	}
 
+	var dryRun bool
+
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Start the Sentinel dVPN node",
 		Long: `Starts the Sentinel dVPN node. Initializes the logger, sets up the context and node,
-explicitly starts the node, and handles SIGINT/SIGTERM for graceful shutdown.`,
+explicitly starts the node, and handles SIGINT/SIGTERM for graceful shutdown.
+
+With --dry-run, the node is set up and the pre-flight checks are run, but the node is neither
+registered nor updated on-chain and the scheduler, API server, and service are not started.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithCancel(cmd.Context())
 			defer cancel()
@@ -44,10 +49,23 @@ explicitly starts the node, and handles SIGINT/SIGTERM for graceful shutdown.`,
 
 			log.Info("Setting up node")
 
-			if err := n.Setup(ctx, homeDir, cmd.InOrStdin(), cfg); err != nil {
+			if err := n.Setup(ctx, homeDir, cfg.Keyring.GetInput(), cfg); err != nil {
 				return fmt.Errorf("setting up node: %w", err)
 			}
 
+			// Stop after the pre-flight checks if only the configuration is being validated.
+			if dryRun {
+				log.Info("Running dry run")
+
+				if err := n.DryRun(ctx); err != nil {
+					return fmt.Errorf("running dry run: %w", err)
+				}
+
+				log.Info("Dry run finished successfully")
+
+				return nil
+			}
+
 			// Use errgroup to manage concurrent start/wait and shutdown operations
 			eg, ctx := errgroup.WithContext(ctx)
 
@@ -81,6 +99,10 @@ explicitly starts the node, and handles SIGINT/SIGTERM for graceful shutdown.`,
 
 				log.Info("Node stopped successfully")
 
+				if err := n.Cleanup(); err != nil {
+					return app.NewErrShutdown(fmt.Errorf("cleaning up node: %w", err))
+				}
+
 				return nil
 			})
 
@@ -94,6 +116,7 @@ explicitly starts the node, and handles SIGINT/SIGTERM for graceful shutdown.`,
 	}
 
 	// Set CLI flags for application and service configuration
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "set up the node and run the pre-flight checks without registering or starting it")
 	cfg.SetForFlags(cmd.Flags())
 	cfg.Services[types.ServiceTypeOpenVPN].SetForFlags(cmd.Flags(), "openvpn")
 	cfg.Services[types.ServiceTypeV2Ray].SetForFlags(cmd.Flags(), "v2ray")
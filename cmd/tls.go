@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// NewResetTLSCmd creates and returns a new Cobra command for regenerating the PKI and TLS certificate.
+func NewResetTLSCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset-tls",
+		Short: "Regenerate the PKI and TLS certificate",
+		Long: `Regenerates the CA and TLS certificate in the application home directory, using the configured
+remote addresses as subject alternative names. The new material is generated and validated in a
+staging directory before it replaces the existing files, which are left untouched on failure.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			homeDir := viper.GetString("home")
+
+			if err := core.ResetTLS(homeDir, cfg.Node.GetRemoteAddrs()); err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			log.Info("PKI and TLS certificate regenerated successfully")
+
+			return nil
+		},
+	}
+
+	return cmd
+}
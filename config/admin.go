@@ -0,0 +1,54 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AdminConfig represents the admin API configuration.
+type AdminConfig struct {
+	Socket string `mapstructure:"socket"` // Socket specifies the path of the Unix domain socket for runtime introspection.
+	Token  string `mapstructure:"token"`  // Token specifies the bearer token required by the admin API.
+}
+
+// WithSocket sets the Socket field and returns the updated AdminConfig.
+func (c *AdminConfig) WithSocket(socket string) *AdminConfig {
+	c.Socket = socket
+
+	return c
+}
+
+// WithToken sets the Token field and returns the updated AdminConfig.
+func (c *AdminConfig) WithToken(token string) *AdminConfig {
+	c.Token = token
+
+	return c
+}
+
+// GetSocket returns the Socket field.
+func (c *AdminConfig) GetSocket() string {
+	return c.Socket
+}
+
+// GetToken returns the Token field.
+func (c *AdminConfig) GetToken() string {
+	return c.Token
+}
+
+// Validate checks the validity of the AdminConfig configuration.
+func (c *AdminConfig) Validate() error {
+	return nil
+}
+
+// SetForFlags adds admin configuration flags to the specified FlagSet.
+func (c *AdminConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.Socket, "admin.socket", c.Socket, "path of the admin socket, relative to the home directory unless absolute (empty to disable)")
+	f.StringVar(&c.Token, "admin.token", c.Token, "bearer token required by the admin API (empty to disable)")
+}
+
+// DefaultAdminConfig returns an AdminConfig instance with default values.
+func DefaultAdminConfig() *AdminConfig {
+	return &AdminConfig{
+		Socket: "",
+		Token:  "",
+	}
+}
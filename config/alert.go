@@ -0,0 +1,54 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AlertConfig represents the worker failure alerting configuration.
+type AlertConfig struct {
+	Webhook                bool `mapstructure:"webhook"`                  // Webhook specifies if alerts are also delivered as webhook events.
+	WorkerFailureThreshold uint `mapstructure:"worker_failure_threshold"` // WorkerFailureThreshold specifies the consecutive failed runs of a worker that raise an alert.
+}
+
+// WithWebhook sets the Webhook field and returns the updated AlertConfig.
+func (c *AlertConfig) WithWebhook(webhook bool) *AlertConfig {
+	c.Webhook = webhook
+
+	return c
+}
+
+// WithWorkerFailureThreshold sets the WorkerFailureThreshold field and returns the updated AlertConfig.
+func (c *AlertConfig) WithWorkerFailureThreshold(threshold uint) *AlertConfig {
+	c.WorkerFailureThreshold = threshold
+
+	return c
+}
+
+// GetWebhook returns the Webhook field.
+func (c *AlertConfig) GetWebhook() bool {
+	return c.Webhook
+}
+
+// GetWorkerFailureThreshold returns the WorkerFailureThreshold field.
+func (c *AlertConfig) GetWorkerFailureThreshold() uint {
+	return c.WorkerFailureThreshold
+}
+
+// Validate checks the validity of the AlertConfig configuration.
+func (c *AlertConfig) Validate() error {
+	return nil
+}
+
+// SetForFlags adds alert configuration flags to the specified FlagSet.
+func (c *AlertConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.Webhook, "alert.webhook", c.Webhook, "deliver worker failure alerts as webhook events")
+	f.UintVar(&c.WorkerFailureThreshold, "alert.worker-failure-threshold", c.WorkerFailureThreshold, "consecutive failed runs of a scheduler worker that raise an alert (0 to disable)")
+}
+
+// DefaultAlertConfig returns an AlertConfig instance with default values.
+func DefaultAlertConfig() *AlertConfig {
+	return &AlertConfig{
+		Webhook:                true,
+		WorkerFailureThreshold: 5,
+	}
+}
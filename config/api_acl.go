@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/spf13/pflag"
+)
+
+// APIACLConfig represents the configuration of the IP ranges allowed and denied access to the API server.
+type APIACLConfig struct {
+	Allow          []string `mapstructure:"allow"`           // Allow is the list of CIDR ranges allowed access, or empty to allow all.
+	Deny           []string `mapstructure:"deny"`            // Deny is the list of CIDR ranges denied access, taking precedence over Allow.
+	TrustedProxies []string `mapstructure:"trusted_proxies"` // TrustedProxies is the list of CIDR ranges of the reverse proxies whose forwarded client addresses are used.
+}
+
+// WithAllow sets the Allow field and returns the updated APIACLConfig.
+func (c *APIACLConfig) WithAllow(allow []string) *APIACLConfig {
+	c.Allow = allow
+
+	return c
+}
+
+// WithDeny sets the Deny field and returns the updated APIACLConfig.
+func (c *APIACLConfig) WithDeny(deny []string) *APIACLConfig {
+	c.Deny = deny
+
+	return c
+}
+
+// WithTrustedProxies sets the TrustedProxies field and returns the updated APIACLConfig.
+func (c *APIACLConfig) WithTrustedProxies(proxies []string) *APIACLConfig {
+	c.TrustedProxies = proxies
+
+	return c
+}
+
+// GetAllow returns the Allow field parsed as IP networks.
+func (c *APIACLConfig) GetAllow() []*net.IPNet {
+	v, err := parseCIDRs(c.Allow)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetDeny returns the Deny field parsed as IP networks.
+func (c *APIACLConfig) GetDeny() []*net.IPNet {
+	v, err := parseCIDRs(c.Deny)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetTrustedProxies returns the TrustedProxies field.
+func (c *APIACLConfig) GetTrustedProxies() []string {
+	return c.TrustedProxies
+}
+
+// Validate checks the validity of the APIACLConfig configuration.
+func (c *APIACLConfig) Validate() error {
+	if _, err := parseCIDRs(c.Allow); err != nil {
+		return fmt.Errorf("parsing allow: %w", err)
+	}
+
+	if _, err := parseCIDRs(c.Deny); err != nil {
+		return fmt.Errorf("parsing deny: %w", err)
+	}
+
+	if _, err := parseCIDRs(c.TrustedProxies); err != nil {
+		return fmt.Errorf("parsing trusted_proxies: %w", err)
+	}
+
+	return nil
+}
+
+// SetForFlags adds API ACL configuration flags to the specified FlagSet.
+func (c *APIACLConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringSliceVar(&c.Allow, "api-acl.allow", c.Allow, "CIDR ranges allowed access to the API server (empty to allow all)")
+	f.StringSliceVar(&c.Deny, "api-acl.deny", c.Deny, "CIDR ranges denied access to the API server")
+	f.StringSliceVar(&c.TrustedProxies, "api-acl.trusted-proxies", c.TrustedProxies, "CIDR ranges of the reverse proxies whose forwarded client addresses are used")
+}
+
+// DefaultAPIACLConfig returns an APIACLConfig instance with default values.
+func DefaultAPIACLConfig() *APIACLConfig {
+	return &APIACLConfig{
+		Allow:          []string{},
+		Deny:           []string{},
+		TrustedProxies: []string{},
+	}
+}
+
+// parseCIDRs parses the CIDR ranges into IP networks.
+func parseCIDRs(items []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(items))
+	for _, item := range items {
+		_, v, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("parsing cidr %q: %w", item, err)
+		}
+
+		nets = append(nets, v)
+	}
+
+	return nets, nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+)
+
+// APIRateLimitConfig represents the configuration of the per-client request rate limits of the API server.
+type APIRateLimitConfig struct {
+	Burst          int     `mapstructure:"burst"`           // Burst is the maximum number of requests of a client served at once by the routes other than the handshake.
+	HandshakeBurst int     `mapstructure:"handshake_burst"` // HandshakeBurst is the maximum number of handshake requests of a client served at once.
+	HandshakeRPS   float64 `mapstructure:"handshake_rps"`   // HandshakeRPS is the sustained number of handshake requests per second allowed for a client.
+	RPS            float64 `mapstructure:"rps"`             // RPS is the sustained number of requests per second allowed for a client by the routes other than the handshake.
+}
+
+// WithBurst sets the Burst field and returns the updated APIRateLimitConfig.
+func (c *APIRateLimitConfig) WithBurst(burst int) *APIRateLimitConfig {
+	c.Burst = burst
+
+	return c
+}
+
+// WithHandshakeBurst sets the HandshakeBurst field and returns the updated APIRateLimitConfig.
+func (c *APIRateLimitConfig) WithHandshakeBurst(burst int) *APIRateLimitConfig {
+	c.HandshakeBurst = burst
+
+	return c
+}
+
+// WithHandshakeRPS sets the HandshakeRPS field and returns the updated APIRateLimitConfig.
+func (c *APIRateLimitConfig) WithHandshakeRPS(rps float64) *APIRateLimitConfig {
+	c.HandshakeRPS = rps
+
+	return c
+}
+
+// WithRPS sets the RPS field and returns the updated APIRateLimitConfig.
+func (c *APIRateLimitConfig) WithRPS(rps float64) *APIRateLimitConfig {
+	c.RPS = rps
+
+	return c
+}
+
+// GetBurst returns the Burst field.
+func (c *APIRateLimitConfig) GetBurst() int {
+	return c.Burst
+}
+
+// GetHandshakeBurst returns the HandshakeBurst field.
+func (c *APIRateLimitConfig) GetHandshakeBurst() int {
+	return c.HandshakeBurst
+}
+
+// GetHandshakeRPS returns the HandshakeRPS field.
+func (c *APIRateLimitConfig) GetHandshakeRPS() float64 {
+	return c.HandshakeRPS
+}
+
+// GetRPS returns the RPS field.
+func (c *APIRateLimitConfig) GetRPS() float64 {
+	return c.RPS
+}
+
+// Validate checks the validity of the APIRateLimitConfig configuration.
+func (c *APIRateLimitConfig) Validate() error {
+	if c.Burst <= 0 {
+		return errors.New("burst must be positive")
+	}
+
+	if c.HandshakeBurst <= 0 {
+		return errors.New("handshake_burst must be positive")
+	}
+
+	if c.HandshakeRPS <= 0 {
+		return errors.New("handshake_rps must be positive")
+	}
+
+	if c.RPS <= 0 {
+		return errors.New("rps must be positive")
+	}
+
+	return nil
+}
+
+// SetForFlags adds API rate limit configuration flags to the specified FlagSet.
+func (c *APIRateLimitConfig) SetForFlags(f *pflag.FlagSet) {
+	f.IntVar(&c.Burst, "api-rate-limit.burst", c.Burst, "maximum number of requests of a client served at once by the routes other than the handshake")
+	f.IntVar(&c.HandshakeBurst, "api-rate-limit.handshake-burst", c.HandshakeBurst, "maximum number of handshake requests of a client served at once")
+	f.Float64Var(&c.HandshakeRPS, "api-rate-limit.handshake-rps", c.HandshakeRPS, "sustained number of handshake requests per second allowed for a client")
+	f.Float64Var(&c.RPS, "api-rate-limit.rps", c.RPS, "sustained number of requests per second allowed for a client by the routes other than the handshake")
+}
+
+// DefaultAPIRateLimitConfig returns an APIRateLimitConfig instance with default values.
+func DefaultAPIRateLimitConfig() *APIRateLimitConfig {
+	return &APIRateLimitConfig{
+		Burst:          5,
+		HandshakeBurst: 2,
+		HandshakeRPS:   0.2,
+		RPS:            1,
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/spf13/pflag"
+)
+
+// BlocklistConfig represents the configuration of the account addresses refused by the handshake.
+type BlocklistConfig struct {
+	Accounts  []string `mapstructure:"accounts"`   // Accounts is the list of locally blocked account addresses.
+	Interval  string   `mapstructure:"interval"`   // Interval is the duration between fetches of the remote blocklist.
+	PublicKey string   `mapstructure:"public_key"` // PublicKey is the hex-encoded Ed25519 key verifying the signature of the remote blocklist.
+	URL       string   `mapstructure:"url"`        // URL is the endpoint serving the remote blocklist, empty to disable syncing.
+}
+
+// WithAccounts sets the Accounts field and returns the updated BlocklistConfig.
+func (c *BlocklistConfig) WithAccounts(accounts []string) *BlocklistConfig {
+	c.Accounts = accounts
+
+	return c
+}
+
+// WithInterval sets the Interval field and returns the updated BlocklistConfig.
+func (c *BlocklistConfig) WithInterval(interval time.Duration) *BlocklistConfig {
+	c.Interval = interval.String()
+
+	return c
+}
+
+// WithPublicKey sets the PublicKey field and returns the updated BlocklistConfig.
+func (c *BlocklistConfig) WithPublicKey(key string) *BlocklistConfig {
+	c.PublicKey = key
+
+	return c
+}
+
+// WithURL sets the URL field and returns the updated BlocklistConfig.
+func (c *BlocklistConfig) WithURL(url string) *BlocklistConfig {
+	c.URL = url
+
+	return c
+}
+
+// GetAccounts returns the Accounts field.
+func (c *BlocklistConfig) GetAccounts() []string {
+	return c.Accounts
+}
+
+// GetInterval returns the Interval field.
+func (c *BlocklistConfig) GetInterval() time.Duration {
+	v, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetPublicKey returns the PublicKey field as an Ed25519 public key, or nil if it is empty.
+func (c *BlocklistConfig) GetPublicKey() ed25519.PublicKey {
+	if c.PublicKey == "" {
+		return nil
+	}
+
+	v, err := hex.DecodeString(c.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetURL returns the URL field.
+func (c *BlocklistConfig) GetURL() string {
+	return c.URL
+}
+
+// Validate checks the validity of the BlocklistConfig configuration.
+func (c *BlocklistConfig) Validate() error {
+	// Validate each address in the Accounts field.
+	for _, addr := range c.Accounts {
+		if _, _, err := bech32.DecodeAndConvert(addr); err != nil {
+			return fmt.Errorf("parsing account %q: %w", addr, err)
+		}
+	}
+
+	v, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return fmt.Errorf("parsing interval %q: %w", c.Interval, err)
+	}
+
+	// Validate the public key whenever it is set, since it is decoded regardless of the url.
+	if c.PublicKey != "" {
+		key, err := hex.DecodeString(c.PublicKey)
+		if err != nil {
+			return fmt.Errorf("decoding public_key %q: %w", c.PublicKey, err)
+		}
+
+		if len(key) != ed25519.PublicKeySize {
+			return fmt.Errorf("public_key must be %d bytes", ed25519.PublicKeySize)
+		}
+	}
+
+	// Validate the remote source only if syncing is enabled.
+	if c.URL == "" {
+		return nil
+	}
+
+	if v <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	u, err := url.ParseRequestURI(c.URL)
+	if err != nil {
+		return fmt.Errorf("parsing url %q: %w", c.URL, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q (allowed: http, https)", u.Scheme)
+	}
+
+	// Ensure the remote blocklist can be verified.
+	if c.PublicKey == "" {
+		return errors.New("public_key cannot be empty when url is set")
+	}
+
+	return nil
+}
+
+// SetForFlags adds blocklist configuration flags to the specified FlagSet.
+func (c *BlocklistConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringSliceVar(&c.Accounts, "blocklist.accounts", c.Accounts, "account addresses refused by the handshake")
+	f.StringVar(&c.Interval, "blocklist.interval", c.Interval, "interval between fetches of the remote blocklist")
+	f.StringVar(&c.PublicKey, "blocklist.public-key", c.PublicKey, "hex-encoded Ed25519 key verifying the remote blocklist")
+	f.StringVar(&c.URL, "blocklist.url", c.URL, "endpoint serving the remote blocklist (empty to disable)")
+}
+
+// DefaultBlocklistConfig returns a BlocklistConfig instance with default values.
+func DefaultBlocklistConfig() *BlocklistConfig {
+	return &BlocklistConfig{
+		Accounts:  []string{},
+		Interval:  (1 * time.Hour).String(),
+		PublicKey: "",
+		URL:       "",
+	}
+}
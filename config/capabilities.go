@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// CapabilitiesConfig represents the capabilities document configuration.
+type CapabilitiesConfig struct {
+	CacheTTL string `mapstructure:"cache_ttl"` // CacheTTL is the duration for which the capabilities document is cached.
+	Sign     bool   `mapstructure:"sign"`      // Sign specifies if the capabilities document is signed with the node key.
+}
+
+// WithCacheTTL sets the CacheTTL field and returns the updated CapabilitiesConfig.
+func (c *CapabilitiesConfig) WithCacheTTL(ttl time.Duration) *CapabilitiesConfig {
+	c.CacheTTL = ttl.String()
+
+	return c
+}
+
+// WithSign sets the Sign field and returns the updated CapabilitiesConfig.
+func (c *CapabilitiesConfig) WithSign(sign bool) *CapabilitiesConfig {
+	c.Sign = sign
+
+	return c
+}
+
+// GetCacheTTL returns the CacheTTL field.
+func (c *CapabilitiesConfig) GetCacheTTL() time.Duration {
+	v, err := time.ParseDuration(c.CacheTTL)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetSign returns the Sign field.
+func (c *CapabilitiesConfig) GetSign() bool {
+	return c.Sign
+}
+
+// Validate checks the validity of the CapabilitiesConfig configuration.
+func (c *CapabilitiesConfig) Validate() error {
+	v, err := time.ParseDuration(c.CacheTTL)
+	if err != nil {
+		return fmt.Errorf("parsing cache_ttl %q: %w", c.CacheTTL, err)
+	}
+
+	// Ensure CacheTTL is not negative.
+	if v < 0 {
+		return errors.New("cache_ttl cannot be negative")
+	}
+
+	return nil
+}
+
+// SetForFlags adds capabilities configuration flags to the specified FlagSet.
+func (c *CapabilitiesConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.CacheTTL, "capabilities.cache-ttl", c.CacheTTL, "duration for which the capabilities document is cached (0 to disable)")
+	f.BoolVar(&c.Sign, "capabilities.sign", c.Sign, "sign the capabilities document with the node key")
+}
+
+// DefaultCapabilitiesConfig returns a CapabilitiesConfig instance with default values.
+func DefaultCapabilitiesConfig() *CapabilitiesConfig {
+	return &CapabilitiesConfig{
+		CacheTTL: (1 * time.Minute).String(),
+		Sign:     true,
+	}
+}
@@ -20,10 +20,36 @@ var fs embed.FS
 type Config struct {
 	*config.Config `mapstructure:",squash"`
 
-	HandshakeDNS *HandshakeDNSConfig `mapstructure:"handshake_dns"` // HandshakeDNS contains Handshake DNS configuration.
-	Node         *NodeConfig         `mapstructure:"node"`          // Node contains node-specific configuration.
-	Oracle       *OracleConfig       `mapstructure:"oracle"`        // Oracle contains oracle-specific configuration.
-	QoS          *QoSConfig          `mapstructure:"qos"`           // QoS contains Quality of Service configuration.
+	Admin             *AdminConfig             `mapstructure:"admin"`              // Admin contains admin API configuration.
+	Alert             *AlertConfig             `mapstructure:"alert"`              // Alert contains worker failure alerting configuration.
+	APIACL            *APIACLConfig            `mapstructure:"api_acl"`            // APIACL contains configuration of the IP ranges allowed and denied access to the API server.
+	APIRateLimit      *APIRateLimitConfig      `mapstructure:"api_rate_limit"`     // APIRateLimit contains configuration of the per-client request rate limits of the API server.
+	Blocklist         *BlocklistConfig         `mapstructure:"blocklist"`          // Blocklist contains configuration of the account addresses refused by the handshake.
+	Capabilities      *CapabilitiesConfig      `mapstructure:"capabilities"`       // Capabilities contains capabilities document configuration.
+	Database          *DatabaseConfig          `mapstructure:"database"`           // Database contains database configuration.
+	Display           *DisplayConfig           `mapstructure:"display"`            // Display contains configuration of how values are displayed.
+	Drain             *DrainConfig             `mapstructure:"drain"`              // Drain contains configuration for draining peers at shutdown.
+	GeoIP             *GeoIPConfig             `mapstructure:"geoip"`              // GeoIP contains configuration of how the location of the node is resolved.
+	HandshakeDNS      *HandshakeDNSConfig      `mapstructure:"handshake_dns"`      // HandshakeDNS contains Handshake DNS configuration.
+	Info              *InfoConfig              `mapstructure:"info"`               // Info contains info endpoint configuration.
+	KeyringPassphrase *KeyringPassphraseConfig `mapstructure:"keyring_passphrase"` // KeyringPassphrase contains configuration of the source of the file keyring passphrase.
+	Log               *LogConfig               `mapstructure:"log"`                // Log contains configuration of the static log fields.
+	Metrics           *MetricsConfig           `mapstructure:"metrics"`            // Metrics contains metrics configuration.
+	Node              *NodeConfig              `mapstructure:"node"`               // Node contains node-specific configuration.
+	Oracle            *OracleConfig            `mapstructure:"oracle"`             // Oracle contains oracle-specific configuration.
+	Ping              *PingConfig              `mapstructure:"ping"`               // Ping contains client latency ping configuration.
+	Plans             []*PlanConfig            `mapstructure:"plans"`              // Plans contains the pricing tiers advertised to clients.
+	PricingSchedule   []*PricingWindowConfig   `mapstructure:"pricing_schedule"`   // PricingSchedule contains the daily windows of fixed prices applied on-chain.
+	QoS               *QoSConfig               `mapstructure:"qos"`                // QoS contains Quality of Service configuration.
+	RateLimit         *RateLimitConfig         `mapstructure:"rate_limit"`         // RateLimit contains configuration of the backoff from rate-limited RPC endpoints.
+	Reconcile         *ReconcileConfig         `mapstructure:"reconcile"`          // Reconcile contains configuration of the session and peer reconciliation check.
+	Replay            *ReplayConfig            `mapstructure:"replay"`             // Replay contains configuration of the protection against replayed handshake requests.
+	RPCTLS            *RPCTLSConfig            `mapstructure:"rpc_tls"`            // RPCTLS contains TLS configuration of the connections to the RPC addresses.
+	Scheduler         *SchedulerConfig         `mapstructure:"scheduler"`          // Scheduler contains scheduler configuration.
+	Speedtest         *SpeedtestConfig         `mapstructure:"speedtest"`          // Speedtest contains speed test configuration.
+	Tunnel            *TunnelConfig            `mapstructure:"tunnel"`             // Tunnel contains the tunnel settings surfaced to clients.
+	TxFallback        *TxFallbackConfig        `mapstructure:"tx_fallback"`        // TxFallback contains configuration of the fee settings retried on insufficient fees.
+	Webhook           *WebhookConfig           `mapstructure:"webhook"`            // Webhook contains webhook event delivery configuration.
 
 	Services map[types.ServiceType]types.ServiceConfig `mapstructure:"-"`
 }
@@ -34,10 +60,66 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("validating base config: %w", err)
 	}
 
+	if err := c.Admin.Validate(); err != nil {
+		return fmt.Errorf("validating admin config: %w", err)
+	}
+
+	if err := c.Alert.Validate(); err != nil {
+		return fmt.Errorf("validating alert config: %w", err)
+	}
+
+	if err := c.APIACL.Validate(); err != nil {
+		return fmt.Errorf("validating api_acl config: %w", err)
+	}
+
+	if err := c.APIRateLimit.Validate(); err != nil {
+		return fmt.Errorf("validating api_rate_limit config: %w", err)
+	}
+
+	if err := c.Blocklist.Validate(); err != nil {
+		return fmt.Errorf("validating blocklist config: %w", err)
+	}
+
+	if err := c.Capabilities.Validate(); err != nil {
+		return fmt.Errorf("validating capabilities config: %w", err)
+	}
+
+	if err := c.Database.Validate(); err != nil {
+		return fmt.Errorf("validating database config: %w", err)
+	}
+
+	if err := c.Display.Validate(); err != nil {
+		return fmt.Errorf("validating display config: %w", err)
+	}
+
+	if err := c.Drain.Validate(); err != nil {
+		return fmt.Errorf("validating drain config: %w", err)
+	}
+
+	if err := c.GeoIP.Validate(); err != nil {
+		return fmt.Errorf("validating geoip config: %w", err)
+	}
+
 	if err := c.HandshakeDNS.Validate(); err != nil {
 		return fmt.Errorf("validating handshake_dns config: %w", err)
 	}
 
+	if err := c.Info.Validate(); err != nil {
+		return fmt.Errorf("validating info config: %w", err)
+	}
+
+	if err := c.KeyringPassphrase.Validate(); err != nil {
+		return fmt.Errorf("validating keyring passphrase config: %w", err)
+	}
+
+	if err := c.Log.Validate(); err != nil {
+		return fmt.Errorf("validating log config: %w", err)
+	}
+
+	if err := c.Metrics.Validate(); err != nil {
+		return fmt.Errorf("validating metrics config: %w", err)
+	}
+
 	if err := c.Node.Validate(); err != nil {
 		return fmt.Errorf("validating node config: %w", err)
 	}
@@ -46,30 +128,145 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("validating oracle config: %w", err)
 	}
 
+	if err := c.Ping.Validate(); err != nil {
+		return fmt.Errorf("validating ping config: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for _, plan := range c.Plans {
+		if err := plan.Validate(); err != nil {
+			return fmt.Errorf("validating plan %q config: %w", plan.Name, err)
+		}
+
+		if names[plan.Name] {
+			return fmt.Errorf("duplicate plan name %q", plan.Name)
+		}
+
+		names[plan.Name] = true
+	}
+
+	if err := validatePricingSchedule(c.PricingSchedule); err != nil {
+		return fmt.Errorf("validating pricing_schedule config: %w", err)
+	}
+
 	if err := c.QoS.Validate(); err != nil {
 		return fmt.Errorf("validating QoS config: %w", err)
 	}
 
+	// Ensure idleness is confirmed by more than a single usage sync, so that peers between the measurement windows of
+	// the service are kept.
+	if c.QoS.GetIdleRateThreshold() > 0 {
+		if interval := c.Node.GetIntervalSessionUsageSyncWithDatabase(); c.QoS.GetIdleTimeout() < 2*interval {
+			return fmt.Errorf("qos idle_timeout must be at least twice interval_session_usage_sync_with_database %s", interval)
+		}
+	}
+
+	if err := c.RateLimit.Validate(); err != nil {
+		return fmt.Errorf("validating rate limit config: %w", err)
+	}
+
+	if err := c.Reconcile.Validate(); err != nil {
+		return fmt.Errorf("validating reconcile config: %w", err)
+	}
+
+	if err := c.Replay.Validate(); err != nil {
+		return fmt.Errorf("validating replay config: %w", err)
+	}
+
+	if err := c.RPCTLS.Validate(); err != nil {
+		return fmt.Errorf("validating rpc_tls config: %w", err)
+	}
+
+	if err := c.Scheduler.Validate(); err != nil {
+		return fmt.Errorf("validating scheduler config: %w", err)
+	}
+
+	if err := c.Speedtest.Validate(); err != nil {
+		return fmt.Errorf("validating speedtest config: %w", err)
+	}
+
+	if err := c.Tunnel.Validate(); err != nil {
+		return fmt.Errorf("validating tunnel config: %w", err)
+	}
+
+	if err := c.TxFallback.Validate(); err != nil {
+		return fmt.Errorf("validating tx fallback config: %w", err)
+	}
+
+	if err := c.Webhook.Validate(); err != nil {
+		return fmt.Errorf("validating webhook config: %w", err)
+	}
+
 	return nil
 }
 
 // SetForFlags adds configuration flags to the specified FlagSet.
 func (c *Config) SetForFlags(f *pflag.FlagSet) {
 	c.Config.SetForFlags(f)
+	c.Admin.SetForFlags(f)
+	c.Alert.SetForFlags(f)
+	c.APIACL.SetForFlags(f)
+	c.APIRateLimit.SetForFlags(f)
+	c.Blocklist.SetForFlags(f)
+	c.Capabilities.SetForFlags(f)
+	c.Database.SetForFlags(f)
+	c.Display.SetForFlags(f)
+	c.Drain.SetForFlags(f)
+	c.GeoIP.SetForFlags(f)
 	c.HandshakeDNS.SetForFlags(f)
+	c.Info.SetForFlags(f)
+	c.KeyringPassphrase.SetForFlags(f)
+	c.Log.SetForFlags(f)
+	c.Metrics.SetForFlags(f)
 	c.Node.SetForFlags(f)
 	c.Oracle.SetForFlags(f)
+	c.Ping.SetForFlags(f)
 	c.QoS.SetForFlags(f)
+	c.RateLimit.SetForFlags(f)
+	c.Reconcile.SetForFlags(f)
+	c.Replay.SetForFlags(f)
+	c.RPCTLS.SetForFlags(f)
+	c.Scheduler.SetForFlags(f)
+	c.Speedtest.SetForFlags(f)
+	c.Tunnel.SetForFlags(f)
+	c.TxFallback.SetForFlags(f)
+	c.Webhook.SetForFlags(f)
 }
 
 // DefaultConfig returns a configuration instance with default values.
 func DefaultConfig() *Config {
 	return &Config{
-		Config:       config.DefaultConfig(),
-		HandshakeDNS: DefaultHandshakeDNSConfig(),
-		Node:         DefaultNodeConfig(),
-		Oracle:       DefaultOracleConfig(),
-		QoS:          DefaultQoSConfig(),
+		Config:            config.DefaultConfig(),
+		Admin:             DefaultAdminConfig(),
+		Alert:             DefaultAlertConfig(),
+		APIACL:            DefaultAPIACLConfig(),
+		APIRateLimit:      DefaultAPIRateLimitConfig(),
+		Blocklist:         DefaultBlocklistConfig(),
+		Capabilities:      DefaultCapabilitiesConfig(),
+		Database:          DefaultDatabaseConfig(),
+		Display:           DefaultDisplayConfig(),
+		Drain:             DefaultDrainConfig(),
+		GeoIP:             DefaultGeoIPConfig(),
+		HandshakeDNS:      DefaultHandshakeDNSConfig(),
+		Info:              DefaultInfoConfig(),
+		KeyringPassphrase: DefaultKeyringPassphraseConfig(),
+		Log:               DefaultLogConfig(),
+		Metrics:           DefaultMetricsConfig(),
+		Node:              DefaultNodeConfig(),
+		Oracle:            DefaultOracleConfig(),
+		Ping:              DefaultPingConfig(),
+		Plans:             []*PlanConfig{},
+		PricingSchedule:   []*PricingWindowConfig{},
+		QoS:               DefaultQoSConfig(),
+		RateLimit:         DefaultRateLimitConfig(),
+		Reconcile:         DefaultReconcileConfig(),
+		Replay:            DefaultReplayConfig(),
+		RPCTLS:            DefaultRPCTLSConfig(),
+		Scheduler:         DefaultSchedulerConfig(),
+		Speedtest:         DefaultSpeedtestConfig(),
+		Tunnel:            DefaultTunnelConfig(),
+		TxFallback:        DefaultTxFallbackConfig(),
+		Webhook:           DefaultWebhookConfig(),
 	}
 }
 
@@ -81,8 +278,24 @@ func (c *Config) WriteAppConfig(file string) error {
 		return fmt.Errorf("reading config template: %w", err)
 	}
 
+	return c.writeAppConfig(string(text), file)
+}
+
+// WriteAppConfigWithTemplate generates the application-level configuration file using the template file.
+func (c *Config) WriteAppConfigWithTemplate(file, templateFile string) error {
+	// Load the application template from the template file.
+	text, err := os.ReadFile(templateFile)
+	if err != nil {
+		return fmt.Errorf("reading config template %q: %w", templateFile, err)
+	}
+
+	return c.writeAppConfig(string(text), file)
+}
+
+// writeAppConfig renders the template with Config data and writes the result to the specified file.
+func (c *Config) writeAppConfig(text, file string) error {
 	// Render the template with Config data and write the result to the specified file.
-	if err := utils.ExecTemplateToFile(string(text), c, file); err != nil {
+	if err := utils.ExecTemplateToFile(text, c, file); err != nil {
 		return fmt.Errorf("writing rendered config file %q: %w", file, err)
 	}
 
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Database drivers for storing the session records.
+const (
+	DatabaseDriverPostgres = "postgres" // Store the records in a PostgreSQL database, which can be shared by nodes.
+	DatabaseDriverSQLite   = "sqlite"   // Store the records in a single SQLite file in the home directory.
+)
+
+// DatabaseConfig represents the database configuration.
+type DatabaseConfig struct {
+	BusyRetryAttempts uint   `mapstructure:"busy_retry_attempts"` // BusyRetryAttempts is the number of attempts for a write failing with a busy error.
+	BusyRetryDelay    string `mapstructure:"busy_retry_delay"`    // BusyRetryDelay is the base duration between retries of a write failing with a busy error.
+	Driver            string `mapstructure:"driver"`              // Driver is the database driver used to store the session records.
+	DSN               string `mapstructure:"dsn"`                 // DSN is the data source name of the PostgreSQL database.
+}
+
+// WithBusyRetryAttempts sets the BusyRetryAttempts field and returns the updated DatabaseConfig.
+func (c *DatabaseConfig) WithBusyRetryAttempts(attempts uint) *DatabaseConfig {
+	c.BusyRetryAttempts = attempts
+
+	return c
+}
+
+// WithBusyRetryDelay sets the BusyRetryDelay field and returns the updated DatabaseConfig.
+func (c *DatabaseConfig) WithBusyRetryDelay(delay time.Duration) *DatabaseConfig {
+	c.BusyRetryDelay = delay.String()
+
+	return c
+}
+
+// WithDriver sets the Driver field and returns the updated DatabaseConfig.
+func (c *DatabaseConfig) WithDriver(driver string) *DatabaseConfig {
+	c.Driver = driver
+
+	return c
+}
+
+// WithDSN sets the DSN field and returns the updated DatabaseConfig.
+func (c *DatabaseConfig) WithDSN(dsn string) *DatabaseConfig {
+	c.DSN = dsn
+
+	return c
+}
+
+// GetBusyRetryAttempts returns the BusyRetryAttempts field.
+func (c *DatabaseConfig) GetBusyRetryAttempts() uint {
+	return c.BusyRetryAttempts
+}
+
+// GetBusyRetryDelay returns the BusyRetryDelay field.
+func (c *DatabaseConfig) GetBusyRetryDelay() time.Duration {
+	v, err := time.ParseDuration(c.BusyRetryDelay)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetDriver returns the Driver field.
+func (c *DatabaseConfig) GetDriver() string {
+	return c.Driver
+}
+
+// GetDSN returns the DSN field.
+func (c *DatabaseConfig) GetDSN() string {
+	return c.DSN
+}
+
+// Validate checks the validity of the DatabaseConfig configuration.
+func (c *DatabaseConfig) Validate() error {
+	// Ensure BusyRetryAttempts is not zero.
+	if c.BusyRetryAttempts == 0 {
+		return errors.New("busy_retry_attempts cannot be zero")
+	}
+
+	if _, err := time.ParseDuration(c.BusyRetryDelay); err != nil {
+		return fmt.Errorf("parsing busy_retry_delay %q: %w", c.BusyRetryDelay, err)
+	}
+
+	// Ensure Driver is one of the supported values.
+	if c.Driver != DatabaseDriverPostgres && c.Driver != DatabaseDriverSQLite {
+		return fmt.Errorf("driver must be either %q or %q", DatabaseDriverPostgres, DatabaseDriverSQLite)
+	}
+
+	// Ensure the PostgreSQL database has a DSN.
+	if c.Driver == DatabaseDriverPostgres && c.DSN == "" {
+		return fmt.Errorf("dsn cannot be empty for driver %q", DatabaseDriverPostgres)
+	}
+
+	return nil
+}
+
+// SetForFlags adds database configuration flags to the specified FlagSet.
+func (c *DatabaseConfig) SetForFlags(f *pflag.FlagSet) {
+	f.UintVar(&c.BusyRetryAttempts, "database.busy-retry-attempts", c.BusyRetryAttempts, "number of attempts for a database write failing with a busy error")
+	f.StringVar(&c.BusyRetryDelay, "database.busy-retry-delay", c.BusyRetryDelay, "base delay between retries of a database write failing with a busy error")
+	f.StringVar(&c.Driver, "database.driver", c.Driver, "database driver used to store the session records (postgres, sqlite)")
+	f.StringVar(&c.DSN, "database.dsn", c.DSN, "data source name of the PostgreSQL database")
+}
+
+// DefaultDatabaseConfig returns a DatabaseConfig instance with default values.
+func DefaultDatabaseConfig() *DatabaseConfig {
+	return &DatabaseConfig{
+		BusyRetryAttempts: 3,
+		BusyRetryDelay:    (100 * time.Millisecond).String(),
+		Driver:            DatabaseDriverSQLite,
+		DSN:               "",
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// DisplayConfig represents the configuration of how values are displayed in API responses and logs.
+type DisplayConfig struct {
+	HumanReadableBytes bool `mapstructure:"human_readable_bytes"` // HumanReadableBytes specifies if byte counts are also displayed in human-readable units.
+}
+
+// WithHumanReadableBytes sets the HumanReadableBytes field and returns the updated DisplayConfig.
+func (c *DisplayConfig) WithHumanReadableBytes(humanReadable bool) *DisplayConfig {
+	c.HumanReadableBytes = humanReadable
+
+	return c
+}
+
+// GetHumanReadableBytes returns the HumanReadableBytes field.
+func (c *DisplayConfig) GetHumanReadableBytes() bool {
+	return c.HumanReadableBytes
+}
+
+// Validate checks the validity of the DisplayConfig configuration.
+func (c *DisplayConfig) Validate() error {
+	return nil
+}
+
+// SetForFlags adds display configuration flags to the specified FlagSet.
+func (c *DisplayConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.HumanReadableBytes, "display.human-readable-bytes", c.HumanReadableBytes, "also display byte counts in human-readable units in API responses and logs")
+}
+
+// DefaultDisplayConfig returns a DisplayConfig instance with default values.
+func DefaultDisplayConfig() *DisplayConfig {
+	return &DisplayConfig{
+		HumanReadableBytes: false,
+	}
+}
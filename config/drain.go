@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Drain strategies for removing peers at shutdown.
+const (
+	DrainStrategyAll     = "all"     // Remove all peers at once.
+	DrainStrategyGradual = "gradual" // Remove peers in batches with a delay between batches.
+)
+
+// DrainConfig represents the configuration for draining peers at shutdown.
+type DrainConfig struct {
+	BatchDelay string `mapstructure:"batch_delay"` // BatchDelay is the duration between removing consecutive batches of peers.
+	BatchSize  uint   `mapstructure:"batch_size"`  // BatchSize is the number of peers removed per batch.
+	Strategy   string `mapstructure:"strategy"`    // Strategy is the order in which peers are removed at shutdown.
+	SyncUsage  bool   `mapstructure:"sync_usage"`  // SyncUsage specifies if the final session usage is synced with the blockchain before peers are removed.
+	Timeout    string `mapstructure:"timeout"`     // Timeout is the maximum duration of the drain before the node is stopped.
+}
+
+// WithBatchDelay sets the BatchDelay field and returns the updated DrainConfig.
+func (c *DrainConfig) WithBatchDelay(delay time.Duration) *DrainConfig {
+	c.BatchDelay = delay.String()
+
+	return c
+}
+
+// WithBatchSize sets the BatchSize field and returns the updated DrainConfig.
+func (c *DrainConfig) WithBatchSize(size uint) *DrainConfig {
+	c.BatchSize = size
+
+	return c
+}
+
+// WithStrategy sets the Strategy field and returns the updated DrainConfig.
+func (c *DrainConfig) WithStrategy(strategy string) *DrainConfig {
+	c.Strategy = strategy
+
+	return c
+}
+
+// WithSyncUsage sets the SyncUsage field and returns the updated DrainConfig.
+func (c *DrainConfig) WithSyncUsage(sync bool) *DrainConfig {
+	c.SyncUsage = sync
+
+	return c
+}
+
+// WithTimeout sets the Timeout field and returns the updated DrainConfig.
+func (c *DrainConfig) WithTimeout(timeout time.Duration) *DrainConfig {
+	c.Timeout = timeout.String()
+
+	return c
+}
+
+// GetBatchDelay returns the BatchDelay field.
+func (c *DrainConfig) GetBatchDelay() time.Duration {
+	v, err := time.ParseDuration(c.BatchDelay)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetBatchSize returns the BatchSize field.
+func (c *DrainConfig) GetBatchSize() uint {
+	return c.BatchSize
+}
+
+// GetStrategy returns the Strategy field.
+func (c *DrainConfig) GetStrategy() string {
+	return c.Strategy
+}
+
+// GetSyncUsage returns the SyncUsage field.
+func (c *DrainConfig) GetSyncUsage() bool {
+	return c.SyncUsage
+}
+
+// GetTimeout returns the Timeout field.
+func (c *DrainConfig) GetTimeout() time.Duration {
+	v, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Validate checks the validity of the DrainConfig configuration.
+func (c *DrainConfig) Validate() error {
+	if _, err := time.ParseDuration(c.BatchDelay); err != nil {
+		return fmt.Errorf("parsing batch_delay %q: %w", c.BatchDelay, err)
+	}
+
+	// Ensure BatchSize is not zero.
+	if c.BatchSize == 0 {
+		return errors.New("batch_size cannot be zero")
+	}
+
+	// Ensure Strategy is one of the supported values.
+	if c.Strategy != DrainStrategyAll && c.Strategy != DrainStrategyGradual {
+		return fmt.Errorf("strategy must be either %q or %q", DrainStrategyAll, DrainStrategyGradual)
+	}
+
+	v, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return fmt.Errorf("parsing timeout %q: %w", c.Timeout, err)
+	}
+
+	// Ensure Timeout is not negative.
+	if v < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	return nil
+}
+
+// SetForFlags adds drain configuration flags to the specified FlagSet.
+func (c *DrainConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.BatchDelay, "drain.batch-delay", c.BatchDelay, "delay between removing consecutive batches of peers at shutdown")
+	f.UintVar(&c.BatchSize, "drain.batch-size", c.BatchSize, "number of peers removed per batch at shutdown")
+	f.StringVar(&c.Strategy, "drain.strategy", c.Strategy, "order in which peers are removed at shutdown (all or gradual)")
+	f.BoolVar(&c.SyncUsage, "drain.sync-usage", c.SyncUsage, "sync the final session usage with the blockchain before removing peers at shutdown")
+	f.StringVar(&c.Timeout, "drain.timeout", c.Timeout, "maximum duration of the drain at shutdown (0 to wait indefinitely)")
+}
+
+// DefaultDrainConfig returns a DrainConfig instance with default values.
+func DefaultDrainConfig() *DrainConfig {
+	return &DrainConfig{
+		BatchDelay: (5 * time.Second).String(),
+		BatchSize:  10,
+		Strategy:   DrainStrategyAll,
+		SyncUsage:  false,
+		Timeout:    (1 * time.Minute).String(),
+	}
+}
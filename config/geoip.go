@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// GeoIP providers for resolving the location of the node.
+const (
+	GeoIPProviderDefault = "default" // Resolve the location using the default remote GeoIP API.
+	GeoIPProviderMaxMind = "maxmind" // Resolve the location using a local MaxMind database.
+)
+
+// GeoIPConfig represents the configuration for resolving the location of the node.
+type GeoIPConfig struct {
+	MaxMindDBPath string `mapstructure:"maxmind_db_path"` // MaxMindDBPath is the path of the local MaxMind .mmdb database file.
+	Provider      string `mapstructure:"provider"`        // Provider is the source used to resolve the location of the node.
+}
+
+// WithMaxMindDBPath sets the MaxMindDBPath field and returns the updated GeoIPConfig.
+func (c *GeoIPConfig) WithMaxMindDBPath(path string) *GeoIPConfig {
+	c.MaxMindDBPath = path
+
+	return c
+}
+
+// WithProvider sets the Provider field and returns the updated GeoIPConfig.
+func (c *GeoIPConfig) WithProvider(provider string) *GeoIPConfig {
+	c.Provider = provider
+
+	return c
+}
+
+// GetMaxMindDBPath returns the MaxMindDBPath field.
+func (c *GeoIPConfig) GetMaxMindDBPath() string {
+	return c.MaxMindDBPath
+}
+
+// GetProvider returns the Provider field.
+func (c *GeoIPConfig) GetProvider() string {
+	return c.Provider
+}
+
+// Validate checks the validity of the GeoIPConfig configuration.
+func (c *GeoIPConfig) Validate() error {
+	// Ensure Provider is one of the supported values.
+	if c.Provider != GeoIPProviderDefault && c.Provider != GeoIPProviderMaxMind {
+		return fmt.Errorf("provider must be either %q or %q", GeoIPProviderDefault, GeoIPProviderMaxMind)
+	}
+
+	if c.Provider != GeoIPProviderMaxMind {
+		return nil
+	}
+
+	// Ensure the MaxMind database file exists when the maxmind provider is selected.
+	if c.MaxMindDBPath == "" {
+		return errors.New("maxmind_db_path cannot be empty when provider is maxmind")
+	}
+
+	info, err := os.Stat(c.MaxMindDBPath)
+	if err != nil {
+		return fmt.Errorf("checking maxmind_db_path %q: %w", c.MaxMindDBPath, err)
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("maxmind_db_path %q is a directory", c.MaxMindDBPath)
+	}
+
+	return nil
+}
+
+// SetForFlags adds GeoIP configuration flags to the specified FlagSet.
+func (c *GeoIPConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.MaxMindDBPath, "geoip.maxmind-db-path", c.MaxMindDBPath, "path of the local MaxMind .mmdb database file")
+	f.StringVar(&c.Provider, "geoip.provider", c.Provider, "source used to resolve the location of the node (default or maxmind)")
+}
+
+// DefaultGeoIPConfig returns a GeoIPConfig instance with default values.
+func DefaultGeoIPConfig() *GeoIPConfig {
+	return &GeoIPConfig{
+		MaxMindDBPath: "",
+		Provider:      GeoIPProviderDefault,
+	}
+}
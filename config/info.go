@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// InfoConfig represents the info endpoint configuration.
+type InfoConfig struct {
+	CacheTTL             string `mapstructure:"cache_ttl"`              // CacheTTL is the duration for which the assembled info response is cached.
+	ExposeStartupTimings bool   `mapstructure:"expose_startup_timings"` // ExposeStartupTimings specifies if the durations of the startup phases are included in the info response.
+}
+
+// WithCacheTTL sets the CacheTTL field and returns the updated InfoConfig.
+func (c *InfoConfig) WithCacheTTL(ttl time.Duration) *InfoConfig {
+	c.CacheTTL = ttl.String()
+
+	return c
+}
+
+// WithExposeStartupTimings sets the ExposeStartupTimings field and returns the updated InfoConfig.
+func (c *InfoConfig) WithExposeStartupTimings(expose bool) *InfoConfig {
+	c.ExposeStartupTimings = expose
+
+	return c
+}
+
+// GetCacheTTL returns the CacheTTL field.
+func (c *InfoConfig) GetCacheTTL() time.Duration {
+	v, err := time.ParseDuration(c.CacheTTL)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetExposeStartupTimings returns the ExposeStartupTimings field.
+func (c *InfoConfig) GetExposeStartupTimings() bool {
+	return c.ExposeStartupTimings
+}
+
+// Validate checks the validity of the InfoConfig configuration.
+func (c *InfoConfig) Validate() error {
+	v, err := time.ParseDuration(c.CacheTTL)
+	if err != nil {
+		return fmt.Errorf("parsing cache_ttl %q: %w", c.CacheTTL, err)
+	}
+
+	// Ensure CacheTTL is not negative.
+	if v < 0 {
+		return errors.New("cache_ttl cannot be negative")
+	}
+
+	return nil
+}
+
+// SetForFlags adds info configuration flags to the specified FlagSet.
+func (c *InfoConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.CacheTTL, "info.cache-ttl", c.CacheTTL, "duration for which the info response is cached (0 to disable)")
+	f.BoolVar(&c.ExposeStartupTimings, "info.expose-startup-timings", c.ExposeStartupTimings, "include the durations of the startup phases in the info response")
+}
+
+// DefaultInfoConfig returns an InfoConfig instance with default values.
+func DefaultInfoConfig() *InfoConfig {
+	return &InfoConfig{
+		CacheTTL:             (5 * time.Second).String(),
+		ExposeStartupTimings: false,
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// KeyringPassphraseConfig represents the configuration of the source of the passphrase of the file keyring
+// backend, answering its prompts for unattended startup.
+type KeyringPassphraseConfig struct {
+	Env  string `mapstructure:"env"`  // Env is the name of the environment variable holding the passphrase.
+	File string `mapstructure:"file"` // File is the path of the file holding the passphrase on its first line.
+}
+
+// WithEnv sets the Env field and returns the updated KeyringPassphraseConfig.
+func (c *KeyringPassphraseConfig) WithEnv(env string) *KeyringPassphraseConfig {
+	c.Env = env
+
+	return c
+}
+
+// WithFile sets the File field and returns the updated KeyringPassphraseConfig.
+func (c *KeyringPassphraseConfig) WithFile(file string) *KeyringPassphraseConfig {
+	c.File = file
+
+	return c
+}
+
+// GetEnv returns the Env field.
+func (c *KeyringPassphraseConfig) GetEnv() string {
+	return c.Env
+}
+
+// GetFile returns the File field.
+func (c *KeyringPassphraseConfig) GetFile() string {
+	return c.File
+}
+
+// IsSet returns whether a source of the passphrase is configured.
+func (c *KeyringPassphraseConfig) IsSet() bool {
+	return c.Env != "" || c.File != ""
+}
+
+// Passphrase reads the passphrase from the configured source.
+func (c *KeyringPassphraseConfig) Passphrase() (string, error) {
+	if c.Env != "" {
+		v, ok := os.LookupEnv(c.Env)
+		if !ok || v == "" {
+			return "", fmt.Errorf("environment variable %q is not set", c.Env)
+		}
+
+		return v, nil
+	}
+
+	buf, err := os.ReadFile(c.File)
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase file %q: %w", c.File, err)
+	}
+
+	v, _, _ := strings.Cut(string(buf), "\n")
+
+	v = strings.TrimSuffix(v, "\r")
+	if v == "" {
+		return "", fmt.Errorf("passphrase file %q is empty", c.File)
+	}
+
+	return v, nil
+}
+
+// Reader returns a reader answering every passphrase prompt of the keyring with the passphrase.
+func (c *KeyringPassphraseConfig) Reader() (io.Reader, error) {
+	v, err := c.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	return &passphraseReader{line: []byte(v + "\n")}, nil
+}
+
+// Validate checks the validity of the KeyringPassphraseConfig configuration.
+func (c *KeyringPassphraseConfig) Validate() error {
+	if c.Env != "" && c.File != "" {
+		return errors.New("env and file cannot both be set")
+	}
+
+	if !c.IsSet() {
+		return nil
+	}
+
+	if _, err := c.Passphrase(); err != nil {
+		return fmt.Errorf("reading passphrase: %w", err)
+	}
+
+	return nil
+}
+
+// SetForFlags adds keyring passphrase configuration flags to the specified FlagSet.
+func (c *KeyringPassphraseConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.Env, "keyring-passphrase.env", c.Env, "environment variable holding the passphrase of the file keyring")
+	f.StringVar(&c.File, "keyring-passphrase.file", c.File, "path of the file holding the passphrase of the file keyring")
+}
+
+// DefaultKeyringPassphraseConfig returns a KeyringPassphraseConfig instance with default values.
+func DefaultKeyringPassphraseConfig() *KeyringPassphraseConfig {
+	return &KeyringPassphraseConfig{
+		Env:  "",
+		File: "",
+	}
+}
+
+// passphraseReader repeats the passphrase line endlessly. Each read returns at most the rest of the current line,
+// so that every prompt reading a line gets the passphrase, including re-entries and retries.
+type passphraseReader struct {
+	mu   sync.Mutex
+	line []byte
+	off  int
+}
+
+// Read reads the passphrase line into p.
+func (r *passphraseReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := copy(p, r.line[r.off:])
+	r.off = (r.off + n) % len(r.line)
+
+	return n, nil
+}
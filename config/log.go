@@ -0,0 +1,54 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// LogConfig represents the configuration of the static fields added to every log line.
+type LogConfig struct {
+	IdentityFields bool   `mapstructure:"identity_fields"` // IdentityFields specifies if the node moniker and address are added to every log line.
+	Region         string `mapstructure:"region"`          // Region specifies the region added to every log line, if not empty.
+}
+
+// WithIdentityFields sets the IdentityFields field and returns the updated LogConfig.
+func (c *LogConfig) WithIdentityFields(identityFields bool) *LogConfig {
+	c.IdentityFields = identityFields
+
+	return c
+}
+
+// WithRegion sets the Region field and returns the updated LogConfig.
+func (c *LogConfig) WithRegion(region string) *LogConfig {
+	c.Region = region
+
+	return c
+}
+
+// GetIdentityFields returns the IdentityFields field.
+func (c *LogConfig) GetIdentityFields() bool {
+	return c.IdentityFields
+}
+
+// GetRegion returns the Region field.
+func (c *LogConfig) GetRegion() string {
+	return c.Region
+}
+
+// Validate checks the validity of the LogConfig configuration.
+func (c *LogConfig) Validate() error {
+	return nil
+}
+
+// SetForFlags adds log configuration flags to the specified FlagSet.
+func (c *LogConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.IdentityFields, "log.identity-fields", c.IdentityFields, "add the node moniker and address to every log line")
+	f.StringVar(&c.Region, "log.region", c.Region, "region added to every log line (empty to omit)")
+}
+
+// DefaultLogConfig returns a LogConfig instance with default values.
+func DefaultLogConfig() *LogConfig {
+	return &LogConfig{
+		IdentityFields: false,
+		Region:         "",
+	}
+}
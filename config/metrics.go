@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// MetricsConfig represents the metrics configuration.
+type MetricsConfig struct {
+	Enable           bool   `mapstructure:"enable"`            // Enable specifies if the metrics endpoint is enabled.
+	IntervalSessions string `mapstructure:"interval_sessions"` // IntervalSessions is the duration between updating the session metrics.
+}
+
+// WithEnable sets the Enable field and returns the updated MetricsConfig.
+func (c *MetricsConfig) WithEnable(enable bool) *MetricsConfig {
+	c.Enable = enable
+
+	return c
+}
+
+// WithIntervalSessions sets the IntervalSessions field and returns the updated MetricsConfig.
+func (c *MetricsConfig) WithIntervalSessions(interval time.Duration) *MetricsConfig {
+	c.IntervalSessions = interval.String()
+
+	return c
+}
+
+// GetEnable returns the Enable field.
+func (c *MetricsConfig) GetEnable() bool {
+	return c.Enable
+}
+
+// GetIntervalSessions returns the IntervalSessions field.
+func (c *MetricsConfig) GetIntervalSessions() time.Duration {
+	v, err := time.ParseDuration(c.IntervalSessions)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Validate checks the validity of the MetricsConfig configuration.
+func (c *MetricsConfig) Validate() error {
+	if _, err := time.ParseDuration(c.IntervalSessions); err != nil {
+		return fmt.Errorf("parsing interval_sessions %q: %w", c.IntervalSessions, err)
+	}
+
+	return nil
+}
+
+// SetForFlags adds metrics configuration flags to the specified FlagSet.
+func (c *MetricsConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.Enable, "metrics.enable", c.Enable, "enable or disable the metrics endpoint")
+	f.StringVar(&c.IntervalSessions, "metrics.interval-sessions", c.IntervalSessions, "interval for updating the session metrics")
+}
+
+// DefaultMetricsConfig returns a MetricsConfig instance with default values.
+func DefaultMetricsConfig() *MetricsConfig {
+	return &MetricsConfig{
+		Enable:           false,
+		IntervalSessions: (1 * time.Minute).String(),
+	}
+}
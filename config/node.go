@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"math/rand/v2"
@@ -19,28 +20,87 @@ import (
 
 const MaxRemoteAddrLen = (1 << 6) - 1 // Maximum allowable length for a remote address.
 
+// MaxTxMemoLen is the maximum length of a transaction memo accepted by the chain.
+const MaxTxMemoLen = 256
+
+// MaxMonikerLen is the maximum length of a resolved moniker, matching the moniker limit of the chain's validator
+// descriptions.
+const MaxMonikerLen = 70
+
+// Placeholders of the moniker, replaced by the hostname of the system and the country code of the GeoIP location.
+const (
+	monikerHostnamePlaceholder = "{hostname}"
+	monikerRegionPlaceholder   = "{region}"
+)
+
+// txMemoMonikerPlaceholder is replaced by the moniker of the node in the transaction memo.
+const txMemoMonikerPlaceholder = "{moniker}"
+
+// tlsVersions maps the supported values of TLSMinVersion to their crypto/tls versions.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
 type NodeConfig struct {
+	APIHandshakePort                       string   `mapstructure:"api_handshake_port"`                          // APIHandshakePort is the port serving the handshake and info routes apart from the other routes, or empty to serve them on the API port.
+	APIListenHost                          string   `mapstructure:"api_listen_host"`                             // APIListenHost is the IP address the API server binds to, or empty for all interfaces.
+	APIMaxBodyBytes                        int64    `mapstructure:"api_max_body_bytes"`                          // APIMaxBodyBytes is the maximum size of the body of an API request.
 	APIPort                                string   `mapstructure:"api_port"`                                    // APIPort is the port for API access.
+	APITLSEnable                           bool     `mapstructure:"api_tls_enable"`                              // APITLSEnable specifies if the API server serves TLS, or plain HTTP behind a TLS-terminating reverse proxy.
+	CheckPriceDenoms                       bool     `mapstructure:"check_price_denoms"`                          // CheckPriceDenoms specifies if the denominations of the prices are checked against the node params at startup.
+	DenomExponents                         string   `mapstructure:"denom_exponents"`                             // DenomExponents is the display exponent of each price denomination.
+	DrainOnShutdown                        bool     `mapstructure:"drain_on_shutdown"`                           // DrainOnShutdown specifies if the connected peers are drained before the node is stopped.
+	EventLogFile                           string   `mapstructure:"event_log_file"`                              // EventLogFile is the path of the file the session lifecycle events are appended to.
+	EventLogMaxBytes                       int64    `mapstructure:"event_log_max_bytes"`                         // EventLogMaxBytes is the size of the event log file at which it is rotated.
 	GigabytePrices                         string   `mapstructure:"gigabyte_prices"`                             // GigabytePrices is the pricing information for gigabytes.
 	HourlyPrices                           string   `mapstructure:"hourly_prices"`                               // HourlyPrices is the pricing information for hourly usage.
 	IntervalBestRPCAddr                    string   `mapstructure:"interval_best_rpc_addr"`                      // IntervalBestRPCAddr is the duration between checking the best RPC address.
 	IntervalGeoIPLocation                  string   `mapstructure:"interval_geoip_location"`                     // IntervalGeoIPLocation is the duration between checking the GeoIP location.
 	IntervalPricesUpdate                   string   `mapstructure:"interval_prices_update"`                      // IntervalPricesUpdate is the duration between updating the prices of the node.
+	IntervalRemoteAddrsUpdate              string   `mapstructure:"interval_remote_addrs_update"`                // IntervalRemoteAddrsUpdate is the duration between checking the public IP address of the node.
+	IntervalServiceHealth                  string   `mapstructure:"interval_service_health"`                     // IntervalServiceHealth is the duration between checking that the service is running.
+	IntervalSessionRetention               string   `mapstructure:"interval_session_retention"`                  // IntervalSessionRetention is the duration between deleting expired sessions and vacuuming the database.
 	IntervalSessionUsageSyncWithBlockchain string   `mapstructure:"interval_session_usage_sync_with_blockchain"` // IntervalSessionUsageSyncWithBlockchain is the duration between syncing session usage with the blockchain.
 	IntervalSessionUsageSyncWithDatabase   string   `mapstructure:"interval_session_usage_sync_with_database"`   // IntervalSessionUsageSyncWithDatabase is the duration between syncing session usage with the database.
 	IntervalSessionUsageValidate           string   `mapstructure:"interval_session_usage_validate"`             // IntervalSessionUsageValidate is the duration between validating session usage.
 	IntervalSessionValidate                string   `mapstructure:"interval_session_validate"`                   // IntervalSessionValidate is the duration between validating sessions.
 	IntervalSpeedtest                      string   `mapstructure:"interval_speedtest"`                          // IntervalSpeedtest is the duration between performing speed tests.
+	IntervalStatusCheck                    string   `mapstructure:"interval_status_check"`                       // IntervalStatusCheck is the duration between checking the on-chain status of the node.
 	IntervalStatusUpdate                   string   `mapstructure:"interval_status_update"`                      // IntervalStatusUpdate is the duration between updating the status of the node.
-	Moniker                                string   `mapstructure:"moniker"`                                     // Moniker is the name or identifier for the node.
+	Moniker                                string   `mapstructure:"moniker"`                                     // Moniker is the name or identifier for the node, with {hostname} and {region} replaced at startup.
+	OutboundNetwork                        string   `mapstructure:"outbound_network"`                            // OutboundNetwork is the network used to dial outbound HTTP connections.
+	PersistState                           bool     `mapstructure:"persist_state"`                               // PersistState specifies if the location and speedtest results are persisted across restarts.
 	RemoteAddrs                            []string `mapstructure:"remote_addrs"`                                // RemoteAddrs is a list of remote addresses for operations.
+	RemoteAddrsAuto                        bool     `mapstructure:"remote_addrs_auto"`                           // RemoteAddrsAuto specifies if the IP remote addresses follow changes of the public IP address.
+	RemoteAddrsProbe                       string   `mapstructure:"remote_addrs_probe"`                          // RemoteAddrsProbe is the handling of remote addresses found unreachable before registering the node.
+	RemovePeersIfInactive                  bool     `mapstructure:"remove_peers_if_inactive"`                    // RemovePeersIfInactive specifies if existing peers are removed while the node is inactive.
+	ReregisterIfMissing                    bool     `mapstructure:"reregister_if_missing"`                       // ReregisterIfMissing specifies if the node registers again once it is no longer found on-chain while running.
+	RetryBackoffBase                       string   `mapstructure:"retry_backoff_base"`                          // RetryBackoffBase is the delay before the first retry of a failed blockchain or GeoIP worker run.
+	RetryBackoffMax                        string   `mapstructure:"retry_backoff_max"`                           // RetryBackoffMax is the maximum delay between retries of a failed blockchain or GeoIP worker run.
+	RPCFailoverCooldown                    string   `mapstructure:"rpc_failover_cooldown"`                       // RPCFailoverCooldown is the duration for which an RPC address is skipped after failing to connect.
+	ServiceMaxRestarts                     uint     `mapstructure:"service_max_restarts"`                        // ServiceMaxRestarts is the number of consecutive failed restarts of the service after which the node shuts down.
 	ServiceType                            string   `mapstructure:"service_type"`                                // ServiceType is the type of the service.
+	SessionRetention                       string   `mapstructure:"session_retention"`                           // SessionRetention is the age after which sessions absent on the blockchain are deleted from the database.
+	SessionUpdateBatchSize                 uint     `mapstructure:"session_update_batch_size"`                   // SessionUpdateBatchSize is the maximum number of session update messages broadcast in a single transaction.
+	SessionWorkerConcurrency               uint     `mapstructure:"session_worker_concurrency"`                  // SessionWorkerConcurrency is the maximum number of sessions processed concurrently by the session workers.
+	StaleSessions                          string   `mapstructure:"stale_sessions"`                              // StaleSessions is the handling of database sessions created against a previous node address.
+	StartupJitterMax                       string   `mapstructure:"startup_jitter_max"`                          // StartupJitterMax is the maximum random delay before registering the node and before the first run of each scheduler worker.
+	TLSMinVersion                          string   `mapstructure:"tls_min_version"`                             // TLSMinVersion is the minimum TLS version accepted by the API server.
+	TxFailureLog                           bool     `mapstructure:"tx_failure_log"`                              // TxFailureLog specifies if the full code, codespace, and raw log of failed transactions are logged at error level.
+	TxMemo                                 string   `mapstructure:"tx_memo"`                                     // TxMemo is the memo attached to every broadcast transaction, with {moniker} replaced by the moniker.
 }
 
-// APIAddrs generates the API addresses for the node.
+// APIAddrs generates the API addresses for the node. Clients start sessions at the registered addresses, so they
+// point to the handshake port when the handshake route is served apart from the other routes.
 func (c *NodeConfig) APIAddrs() []string {
+	v := c.GetAPIPort()
+	if hp := c.GetAPIHandshakePort(); hp != nil {
+		v = hp
+	}
+
 	addrs := make([]string, len(c.RemoteAddrs))
-	port := strconv.FormatUint(uint64(c.GetAPIPort().OutFrom), 10)
+	port := strconv.FormatUint(uint64(v.OutFrom), 10)
 
 	for i, addr := range c.RemoteAddrs {
 		addrs[i] = net.JoinHostPort(addr, port)
@@ -49,9 +109,20 @@ func (c *NodeConfig) APIAddrs() []string {
 	return addrs
 }
 
+// APIHandshakeListenAddr returns the listen address of the handshake route, or empty if it is served on the API
+// listen address.
+func (c *NodeConfig) APIHandshakeListenAddr() string {
+	v := c.GetAPIHandshakePort()
+	if v == nil {
+		return ""
+	}
+
+	return net.JoinHostPort(c.APIListenHost, strconv.FormatUint(uint64(v.InFrom), 10))
+}
+
 // APIListenAddr returns the API listen address.
 func (c *NodeConfig) APIListenAddr() string {
-	return fmt.Sprintf(":%d", c.APIListenPort())
+	return net.JoinHostPort(c.APIListenHost, strconv.FormatUint(uint64(c.APIListenPort()), 10))
 }
 
 // APIListenPort returns the API listen port.
@@ -59,6 +130,30 @@ func (c *NodeConfig) APIListenPort() uint16 {
 	return c.GetAPIPort().InFrom
 }
 
+// GetAPIHandshakePort returns the APIHandshakePort field, or nil if it is empty.
+func (c *NodeConfig) GetAPIHandshakePort() *netip.Port {
+	if c.APIHandshakePort == "" {
+		return nil
+	}
+
+	v, err := netip.NewPortFromString(c.APIHandshakePort)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetAPIListenHost returns the APIListenHost field.
+func (c *NodeConfig) GetAPIListenHost() string {
+	return c.APIListenHost
+}
+
+// GetAPIMaxBodyBytes returns the APIMaxBodyBytes field.
+func (c *NodeConfig) GetAPIMaxBodyBytes() int64 {
+	return c.APIMaxBodyBytes
+}
+
 // GetAPIPort returns the APIPort field.
 func (c *NodeConfig) GetAPIPort() *netip.Port {
 	v, err := netip.NewPortFromString(c.APIPort)
@@ -69,6 +164,41 @@ func (c *NodeConfig) GetAPIPort() *netip.Port {
 	return v
 }
 
+// GetAPITLSEnable returns the APITLSEnable field.
+func (c *NodeConfig) GetAPITLSEnable() bool {
+	return c.APITLSEnable
+}
+
+// GetCheckPriceDenoms returns the CheckPriceDenoms field.
+func (c *NodeConfig) GetCheckPriceDenoms() bool {
+	return c.CheckPriceDenoms
+}
+
+// GetDenomExponents returns the DenomExponents field as a map of denominations to exponents.
+func (c *NodeConfig) GetDenomExponents() map[string]uint {
+	v, err := parseDenomExponents(c.DenomExponents)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetDrainOnShutdown returns the DrainOnShutdown field.
+func (c *NodeConfig) GetDrainOnShutdown() bool {
+	return c.DrainOnShutdown
+}
+
+// GetEventLogFile returns the EventLogFile field.
+func (c *NodeConfig) GetEventLogFile() string {
+	return c.EventLogFile
+}
+
+// GetEventLogMaxBytes returns the EventLogMaxBytes field.
+func (c *NodeConfig) GetEventLogMaxBytes() int64 {
+	return c.EventLogMaxBytes
+}
+
 // GetGigabytePrices returns the GigabytePrices field.
 func (c *NodeConfig) GetGigabytePrices() v1.Prices {
 	v, err := v1.NewPricesFromString(c.GigabytePrices)
@@ -119,6 +249,36 @@ func (c *NodeConfig) GetIntervalPricesUpdate() time.Duration {
 	return v
 }
 
+// GetIntervalRemoteAddrsUpdate returns the IntervalRemoteAddrsUpdate field.
+func (c *NodeConfig) GetIntervalRemoteAddrsUpdate() time.Duration {
+	v, err := time.ParseDuration(c.IntervalRemoteAddrsUpdate)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetIntervalServiceHealth returns the IntervalServiceHealth field.
+func (c *NodeConfig) GetIntervalServiceHealth() time.Duration {
+	v, err := time.ParseDuration(c.IntervalServiceHealth)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetIntervalSessionRetention returns the IntervalSessionRetention field.
+func (c *NodeConfig) GetIntervalSessionRetention() time.Duration {
+	v, err := time.ParseDuration(c.IntervalSessionRetention)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
 // GetIntervalSessionUsageSyncWithBlockchain returns the IntervalSessionUsageSyncWithBlockchain field.
 func (c *NodeConfig) GetIntervalSessionUsageSyncWithBlockchain() time.Duration {
 	v, err := time.ParseDuration(c.IntervalSessionUsageSyncWithBlockchain)
@@ -169,6 +329,16 @@ func (c *NodeConfig) GetIntervalSpeedtest() time.Duration {
 	return v
 }
 
+// GetIntervalStatusCheck returns the IntervalStatusCheck field.
+func (c *NodeConfig) GetIntervalStatusCheck() time.Duration {
+	v, err := time.ParseDuration(c.IntervalStatusCheck)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
 // GetIntervalStatusUpdate returns the IntervalStatusUpdate field.
 func (c *NodeConfig) GetIntervalStatusUpdate() time.Duration {
 	v, err := time.ParseDuration(c.IntervalStatusUpdate)
@@ -184,18 +354,199 @@ func (c *NodeConfig) GetMoniker() string {
 	return c.Moniker
 }
 
+// GetOutboundNetwork returns the OutboundNetwork field.
+func (c *NodeConfig) GetOutboundNetwork() string {
+	return c.OutboundNetwork
+}
+
+// GetPersistState returns the PersistState field.
+func (c *NodeConfig) GetPersistState() bool {
+	return c.PersistState
+}
+
 // GetRemoteAddrs returns the RemoteAddrs field.
 func (c *NodeConfig) GetRemoteAddrs() []string {
 	return c.RemoteAddrs
 }
 
+// GetRemoteAddrsAuto returns the RemoteAddrsAuto field.
+func (c *NodeConfig) GetRemoteAddrsAuto() bool {
+	return c.RemoteAddrsAuto
+}
+
+// GetRemoteAddrsProbe returns the RemoteAddrsProbe field.
+func (c *NodeConfig) GetRemoteAddrsProbe() string {
+	return c.RemoteAddrsProbe
+}
+
+// GetRemovePeersIfInactive returns the RemovePeersIfInactive field.
+func (c *NodeConfig) GetRemovePeersIfInactive() bool {
+	return c.RemovePeersIfInactive
+}
+
+// GetReregisterIfMissing returns the ReregisterIfMissing field.
+func (c *NodeConfig) GetReregisterIfMissing() bool {
+	return c.ReregisterIfMissing
+}
+
+// GetRetryBackoffBase returns the RetryBackoffBase field.
+func (c *NodeConfig) GetRetryBackoffBase() time.Duration {
+	v, err := time.ParseDuration(c.RetryBackoffBase)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetRetryBackoffMax returns the RetryBackoffMax field.
+func (c *NodeConfig) GetRetryBackoffMax() time.Duration {
+	v, err := time.ParseDuration(c.RetryBackoffMax)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetRPCFailoverCooldown returns the RPCFailoverCooldown field.
+func (c *NodeConfig) GetRPCFailoverCooldown() time.Duration {
+	v, err := time.ParseDuration(c.RPCFailoverCooldown)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetServiceMaxRestarts returns the ServiceMaxRestarts field.
+func (c *NodeConfig) GetServiceMaxRestarts() uint {
+	return c.ServiceMaxRestarts
+}
+
 // GetServiceType returns the ServiceType field.
 func (c *NodeConfig) GetServiceType() types.ServiceType {
 	return types.ServiceTypeFromString(c.ServiceType)
 }
 
+// GetSessionRetention returns the SessionRetention field.
+func (c *NodeConfig) GetSessionRetention() time.Duration {
+	v, err := time.ParseDuration(c.SessionRetention)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetSessionUpdateBatchSize returns the SessionUpdateBatchSize field.
+func (c *NodeConfig) GetSessionUpdateBatchSize() uint {
+	return c.SessionUpdateBatchSize
+}
+
+// GetSessionWorkerConcurrency returns the SessionWorkerConcurrency field.
+func (c *NodeConfig) GetSessionWorkerConcurrency() uint {
+	return c.SessionWorkerConcurrency
+}
+
+// GetStaleSessions returns the StaleSessions field.
+func (c *NodeConfig) GetStaleSessions() string {
+	return c.StaleSessions
+}
+
+// GetStartupJitterMax returns the StartupJitterMax field.
+func (c *NodeConfig) GetStartupJitterMax() time.Duration {
+	v, err := time.ParseDuration(c.StartupJitterMax)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetTLSMinVersion returns the TLSMinVersion field as a crypto/tls version.
+func (c *NodeConfig) GetTLSMinVersion() uint16 {
+	v, ok := tlsVersions[c.TLSMinVersion]
+	if !ok {
+		panic(fmt.Errorf("unsupported tls_min_version %q", c.TLSMinVersion))
+	}
+
+	return v
+}
+
+// GetTxFailureLog returns the TxFailureLog field.
+func (c *NodeConfig) GetTxFailureLog() bool {
+	return c.TxFailureLog
+}
+
+// GetTxMemo returns the TxMemo field with the moniker placeholder replaced by the Moniker field.
+func (c *NodeConfig) GetTxMemo() string {
+	return c.TxMemoFor(c.Moniker)
+}
+
+// MonikerHasHostname returns whether the Moniker field contains the hostname placeholder.
+func (c *NodeConfig) MonikerHasHostname() bool {
+	return strings.Contains(c.Moniker, monikerHostnamePlaceholder)
+}
+
+// MonikerHasRegion returns whether the Moniker field contains the region placeholder.
+func (c *NodeConfig) MonikerHasRegion() bool {
+	return strings.Contains(c.Moniker, monikerRegionPlaceholder)
+}
+
+// ResolveMoniker returns the Moniker field with the placeholders replaced by the hostname and the region, and
+// validates that the result is not empty and fits the moniker limit.
+func (c *NodeConfig) ResolveMoniker(hostname, region string) (string, error) {
+	v := strings.NewReplacer(
+		monikerHostnamePlaceholder, hostname,
+		monikerRegionPlaceholder, region,
+	).Replace(c.Moniker)
+
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", fmt.Errorf("moniker %q resolves to an empty moniker", c.Moniker)
+	}
+
+	if len(v) > MaxMonikerLen {
+		return "", fmt.Errorf("resolved moniker length %d cannot be greater than %d", len(v), MaxMonikerLen)
+	}
+
+	if memo := c.TxMemoFor(v); len(memo) > MaxTxMemoLen {
+		return "", fmt.Errorf("tx_memo length %d cannot be greater than %d", len(memo), MaxTxMemoLen)
+	}
+
+	return v, nil
+}
+
+// TxMemoFor returns the TxMemo field with the moniker placeholder replaced by the moniker.
+func (c *NodeConfig) TxMemoFor(moniker string) string {
+	return strings.ReplaceAll(c.TxMemo, txMemoMonikerPlaceholder, moniker)
+}
+
 // Validate validates the node configuration.
 func (c *NodeConfig) Validate() error {
+	// Validate the handshake port if set, which must not share the listen port of the other routes.
+	if c.APIHandshakePort != "" {
+		v, err := netip.NewPortFromString(c.APIHandshakePort)
+		if err != nil {
+			return fmt.Errorf("parsing api_handshake_port %q: %w", c.APIHandshakePort, err)
+		}
+
+		if p, err := netip.NewPortFromString(c.APIPort); err == nil && p.InFrom == v.InFrom {
+			return fmt.Errorf("api_handshake_port %q must not use the same in_port as api_port %q", c.APIHandshakePort, c.APIPort)
+		}
+	}
+
+	// Ensure the API listen host is empty or an IP address.
+	if c.APIListenHost != "" && net.ParseIP(c.APIListenHost) == nil {
+		return fmt.Errorf("api_listen_host %q must be an IP address or empty", c.APIListenHost)
+	}
+
+	// Ensure the maximum size of request bodies is positive.
+	if c.APIMaxBodyBytes <= 0 {
+		return errors.New("api_max_body_bytes must be positive")
+	}
+
 	// Ensure the API port is not empty and validate it.
 	if c.APIPort == "" {
 		return errors.New("api_port cannot be empty")
@@ -205,6 +556,16 @@ func (c *NodeConfig) Validate() error {
 		return fmt.Errorf("parsing api_port %q: %w", c.APIPort, err)
 	}
 
+	// Validate the DenomExponents field.
+	if _, err := parseDenomExponents(c.DenomExponents); err != nil {
+		return fmt.Errorf("parsing denom_exponents %q: %w", c.DenomExponents, err)
+	}
+
+	// Ensure the event log is rotated at a positive size.
+	if c.EventLogMaxBytes <= 0 {
+		return errors.New("event_log_max_bytes must be positive")
+	}
+
 	// Validate the GigabytePrices field.
 	if _, err := v1.NewPricesFromString(c.GigabytePrices); err != nil {
 		return fmt.Errorf("parsing gigabyte_prices %q: %w", c.GigabytePrices, err)
@@ -228,6 +589,18 @@ func (c *NodeConfig) Validate() error {
 		return fmt.Errorf("parsing interval_prices_update %q: %w", c.IntervalPricesUpdate, err)
 	}
 
+	if _, err := time.ParseDuration(c.IntervalRemoteAddrsUpdate); err != nil {
+		return fmt.Errorf("parsing interval_remote_addrs_update %q: %w", c.IntervalRemoteAddrsUpdate, err)
+	}
+
+	if _, err := time.ParseDuration(c.IntervalServiceHealth); err != nil {
+		return fmt.Errorf("parsing interval_service_health %q: %w", c.IntervalServiceHealth, err)
+	}
+
+	if _, err := time.ParseDuration(c.IntervalSessionRetention); err != nil {
+		return fmt.Errorf("parsing interval_session_retention %q: %w", c.IntervalSessionRetention, err)
+	}
+
 	if _, err := time.ParseDuration(c.IntervalSessionUsageSyncWithBlockchain); err != nil {
 		return fmt.Errorf("parsing interval_session_usage_sync_with_blockchain %q: %w",
 			c.IntervalSessionUsageSyncWithBlockchain, err)
@@ -250,20 +623,66 @@ func (c *NodeConfig) Validate() error {
 		return fmt.Errorf("parsing interval_speedtest %q: %w", c.IntervalSpeedtest, err)
 	}
 
+	if _, err := time.ParseDuration(c.IntervalStatusCheck); err != nil {
+		return fmt.Errorf("parsing interval_status_check %q: %w", c.IntervalStatusCheck, err)
+	}
+
 	if _, err := time.ParseDuration(c.IntervalStatusUpdate); err != nil {
 		return fmt.Errorf("parsing interval_status_update %q: %w", c.IntervalStatusUpdate, err)
 	}
 
-	// Ensure the Moniker field is not empty.
+	// Ensure the Moniker field is not empty, and fits the moniker limit unless it is resolved at startup.
 	if c.Moniker == "" {
 		return errors.New("moniker cannot be empty")
 	}
 
+	if !c.MonikerHasHostname() && !c.MonikerHasRegion() && len(c.Moniker) > MaxMonikerLen {
+		return fmt.Errorf("moniker length %d cannot be greater than %d", len(c.Moniker), MaxMonikerLen)
+	}
+
+	// Validate the outbound network.
+	validOutboundNetworks := map[string]bool{
+		"tcp":  true,
+		"tcp4": true,
+		"tcp6": true,
+	}
+	if !validOutboundNetworks[c.OutboundNetwork] {
+		return fmt.Errorf("unsupported outbound_network %q (allowed: tcp, tcp4, tcp6)", c.OutboundNetwork)
+	}
+
 	// Ensure the RemoteAddrs field is not empty.
 	if len(c.RemoteAddrs) == 0 {
 		return errors.New("remote_addrs cannot be empty")
 	}
 
+	// Validate the retry backoff bounds.
+	retryBackoffBase, err := time.ParseDuration(c.RetryBackoffBase)
+	if err != nil {
+		return fmt.Errorf("parsing retry_backoff_base %q: %w", c.RetryBackoffBase, err)
+	}
+
+	retryBackoffMax, err := time.ParseDuration(c.RetryBackoffMax)
+	if err != nil {
+		return fmt.Errorf("parsing retry_backoff_max %q: %w", c.RetryBackoffMax, err)
+	}
+
+	if retryBackoffBase <= 0 {
+		return errors.New("retry_backoff_base must be positive")
+	}
+
+	if retryBackoffMax < retryBackoffBase {
+		return errors.New("retry_backoff_max cannot be less than retry_backoff_base")
+	}
+
+	rpcFailoverCooldown, err := time.ParseDuration(c.RPCFailoverCooldown)
+	if err != nil {
+		return fmt.Errorf("parsing rpc_failover_cooldown %q: %w", c.RPCFailoverCooldown, err)
+	}
+
+	if rpcFailoverCooldown < 0 {
+		return errors.New("rpc_failover_cooldown cannot be negative")
+	}
+
 	// Validate each address in the RemoteAddrs field.
 	for _, addr := range c.RemoteAddrs {
 		if err := validateRemoteAddr(addr); err != nil {
@@ -281,46 +700,177 @@ func (c *NodeConfig) Validate() error {
 		return fmt.Errorf("unsupported service_type %q (allowed: v2ray, wireguard, openvpn)", c.ServiceType)
 	}
 
+	// Ensure SessionRetention is a valid positive duration.
+	sessionRetention, err := time.ParseDuration(c.SessionRetention)
+	if err != nil {
+		return fmt.Errorf("parsing session_retention %q: %w", c.SessionRetention, err)
+	}
+
+	if sessionRetention <= 0 {
+		return errors.New("session_retention must be positive")
+	}
+
+	// Ensure SessionUpdateBatchSize is not zero.
+	if c.SessionUpdateBatchSize == 0 {
+		return errors.New("session_update_batch_size cannot be zero")
+	}
+
+	// Ensure ServiceMaxRestarts is not zero.
+	if c.ServiceMaxRestarts == 0 {
+		return errors.New("service_max_restarts cannot be zero")
+	}
+
+	// Ensure SessionWorkerConcurrency is not zero.
+	if c.SessionWorkerConcurrency == 0 {
+		return errors.New("session_worker_concurrency cannot be zero")
+	}
+
+	// Validate the handling of stale sessions.
+	validStaleSessions := map[string]bool{
+		"delete": true,
+		"keep":   true,
+		"reject": true,
+	}
+	if !validStaleSessions[c.StaleSessions] {
+		return fmt.Errorf("unsupported stale_sessions %q (allowed: delete, keep, reject)", c.StaleSessions)
+	}
+
+	startupJitterMax, err := time.ParseDuration(c.StartupJitterMax)
+	if err != nil {
+		return fmt.Errorf("parsing startup_jitter_max %q: %w", c.StartupJitterMax, err)
+	}
+
+	if startupJitterMax < 0 {
+		return errors.New("startup_jitter_max cannot be negative")
+	}
+
+	// Validate the handling of unreachable remote addresses.
+	validRemoteAddrsProbe := map[string]bool{
+		"abort": true,
+		"off":   true,
+		"warn":  true,
+	}
+	if !validRemoteAddrsProbe[c.RemoteAddrsProbe] {
+		return fmt.Errorf("unsupported remote_addrs_probe %q (allowed: abort, off, warn)", c.RemoteAddrsProbe)
+	}
+
+	// Validate the minimum TLS version of the API server.
+	if _, ok := tlsVersions[c.TLSMinVersion]; !ok {
+		return fmt.Errorf("unsupported tls_min_version %q (allowed: 1.2, 1.3)", c.TLSMinVersion)
+	}
+
+	// Ensure the memo fits the chain limit once the moniker is templated in. A moniker with placeholders is only
+	// known at startup, where ResolveMoniker checks the memo against the resolved moniker instead.
+	if c.MonikerHasHostname() || c.MonikerHasRegion() {
+		return nil
+	}
+
+	if memo := c.GetTxMemo(); len(memo) > MaxTxMemoLen {
+		return fmt.Errorf("tx_memo length %d cannot be greater than %d", len(memo), MaxTxMemoLen)
+	}
+
 	return nil
 }
 
 // SetForFlags adds node configuration flags to the specified FlagSet.
 func (c *NodeConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.APIHandshakePort, "node.api-handshake-port", c.APIHandshakePort, "port serving the handshake and info routes apart from the other routes (empty to serve them on the API port)")
+	f.StringVar(&c.APIListenHost, "node.api-listen-host", c.APIListenHost, "IP address the API server binds to (empty for all interfaces)")
+	f.Int64Var(&c.APIMaxBodyBytes, "node.api-max-body-bytes", c.APIMaxBodyBytes, "maximum size in bytes of the body of an API request")
 	f.StringVar(&c.APIPort, "node.api-port", c.APIPort, "port for API access")
+	f.BoolVar(&c.APITLSEnable, "node.api-tls-enable", c.APITLSEnable, "serve TLS on the API port, or plain HTTP behind a TLS-terminating reverse proxy")
+	f.BoolVar(&c.CheckPriceDenoms, "node.check-price-denoms", c.CheckPriceDenoms, "check the denominations of the prices against the node params at startup")
+	f.StringVar(&c.DenomExponents, "node.denom-exponents", c.DenomExponents, "display exponents of the price denominations (e.g., udvpn:6;uatom:6)")
+	f.BoolVar(&c.DrainOnShutdown, "node.drain-on-shutdown", c.DrainOnShutdown, "drain the connected peers before stopping the node")
+	f.StringVar(&c.EventLogFile, "node.event-log-file", c.EventLogFile, "path of the file the session lifecycle events are appended to (empty to disable)")
+	f.Int64Var(&c.EventLogMaxBytes, "node.event-log-max-bytes", c.EventLogMaxBytes, "size in bytes of the event log file at which it is rotated")
 	f.StringVar(&c.GigabytePrices, "node.gigabyte-prices", c.GigabytePrices, "pricing information for gigabytes")
 	f.StringVar(&c.HourlyPrices, "node.hourly-prices", c.HourlyPrices, "pricing information for hourly usage")
 	f.StringVar(&c.IntervalBestRPCAddr, "node.interval-best-rpc-addr", c.IntervalBestRPCAddr, "interval for checking the best RPC address")
 	f.StringVar(&c.IntervalGeoIPLocation, "node.interval-geoip-location", c.IntervalGeoIPLocation, "interval for checking GeoIP location")
 	f.StringVar(&c.IntervalPricesUpdate, "node.interval-prices-update", c.IntervalPricesUpdate, "interval for updating node prices")
+	f.StringVar(&c.IntervalRemoteAddrsUpdate, "node.interval-remote-addrs-update", c.IntervalRemoteAddrsUpdate, "interval for checking the public IP address of the node")
+	f.StringVar(&c.IntervalServiceHealth, "node.interval-service-health", c.IntervalServiceHealth, "interval for checking that the service is running")
+	f.StringVar(&c.IntervalSessionRetention, "node.interval-session-retention", c.IntervalSessionRetention, "interval for deleting expired sessions and vacuuming the database")
 	f.StringVar(&c.IntervalSessionUsageSyncWithBlockchain, "node.interval-session-usage-sync-with-blockchain", c.IntervalSessionUsageSyncWithBlockchain, "interval for syncing session usage with blockchain")
 	f.StringVar(&c.IntervalSessionUsageSyncWithDatabase, "node.interval-session-usage-sync-with-database", c.IntervalSessionUsageSyncWithDatabase, "interval for syncing session usage with database")
 	f.StringVar(&c.IntervalSessionUsageValidate, "node.interval-session-usage-validate", c.IntervalSessionUsageValidate, "interval for validating session usage")
 	f.StringVar(&c.IntervalSessionValidate, "node.interval-session-validate", c.IntervalSessionValidate, "interval for validating sessions")
 	f.StringVar(&c.IntervalSpeedtest, "node.interval-speedtest", c.IntervalSpeedtest, "interval for performing speed tests")
+	f.StringVar(&c.IntervalStatusCheck, "node.interval-status-check", c.IntervalStatusCheck, "interval for checking the on-chain node status")
 	f.StringVar(&c.IntervalStatusUpdate, "node.interval-status-update", c.IntervalStatusUpdate, "interval for updating node status")
-	f.StringVar(&c.Moniker, "node.moniker", c.Moniker, "moniker (identifier) for the node")
+	f.StringVar(&c.Moniker, "node.moniker", c.Moniker, "moniker (identifier) for the node ({hostname} and {region} are replaced at startup)")
+	f.StringVar(&c.OutboundNetwork, "node.outbound-network", c.OutboundNetwork, "network used to dial outbound HTTP connections (tcp, tcp4 or tcp6)")
+	f.BoolVar(&c.PersistState, "node.persist-state", c.PersistState, "persist the location and speedtest results across restarts")
 	f.StringSliceVar(&c.RemoteAddrs, "node.remote-addrs", c.RemoteAddrs, "list of remote addresses for the node")
+	f.BoolVar(&c.RemoteAddrsAuto, "node.remote-addrs-auto", c.RemoteAddrsAuto, "update the IP remote addresses on-chain when the public IP address changes")
+	f.StringVar(&c.RemoteAddrsProbe, "node.remote-addrs-probe", c.RemoteAddrsProbe, "handling of remote addresses found unreachable before registering the node (abort, off or warn)")
+	f.BoolVar(&c.RemovePeersIfInactive, "node.remove-peers-if-inactive", c.RemovePeersIfInactive, "remove existing peers while the node is inactive on-chain")
+	f.BoolVar(&c.ReregisterIfMissing, "node.reregister-if-missing", c.ReregisterIfMissing, "register the node again once it is no longer found on-chain while running")
+	f.StringVar(&c.RetryBackoffBase, "node.retry-backoff-base", c.RetryBackoffBase, "delay before the first retry of a failed blockchain or GeoIP worker run")
+	f.StringVar(&c.RetryBackoffMax, "node.retry-backoff-max", c.RetryBackoffMax, "maximum delay between retries of a failed blockchain or GeoIP worker run")
+	f.StringVar(&c.RPCFailoverCooldown, "node.rpc-failover-cooldown", c.RPCFailoverCooldown, "duration for which an RPC address is skipped after failing to connect")
+	f.UintVar(&c.ServiceMaxRestarts, "node.service-max-restarts", c.ServiceMaxRestarts, "number of consecutive failed restarts of the service after which the node shuts down")
 	f.StringVar(&c.ServiceType, "node.service-type", c.ServiceType, "service type of the node (e.g., v2ray, wireguard, openvpn)")
+	f.StringVar(&c.SessionRetention, "node.session-retention", c.SessionRetention, "age after which sessions absent on the blockchain are deleted from the database")
+	f.UintVar(&c.SessionUpdateBatchSize, "node.session-update-batch-size", c.SessionUpdateBatchSize, "maximum number of session update messages broadcast in a single transaction")
+	f.UintVar(&c.SessionWorkerConcurrency, "node.session-worker-concurrency", c.SessionWorkerConcurrency, "maximum number of sessions processed concurrently by the session workers")
+	f.StringVar(&c.StaleSessions, "node.stale-sessions", c.StaleSessions, "handling of sessions created against a previous node address (delete, keep or reject)")
+	f.StringVar(&c.StartupJitterMax, "node.startup-jitter-max", c.StartupJitterMax, "maximum random delay before registering the node and before the first run of each scheduler worker")
+	f.StringVar(&c.TLSMinVersion, "node.tls-min-version", c.TLSMinVersion, "minimum TLS version accepted by the API server (1.2 or 1.3)")
+	f.BoolVar(&c.TxFailureLog, "node.tx-failure-log", c.TxFailureLog, "log the full code, codespace, and raw log of failed transactions at error level")
+	f.StringVar(&c.TxMemo, "node.tx-memo", c.TxMemo, "memo attached to every broadcast transaction ({moniker} is replaced by the moniker)")
 }
 
 // DefaultNodeConfig returns a NodeConfig instance with default values.
 func DefaultNodeConfig() *NodeConfig {
 	return &NodeConfig{
+		APIHandshakePort:                       "",
+		APIListenHost:                          "",
+		APIMaxBodyBytes:                        64 * 1024,
 		APIPort:                                strconv.FormatUint(uint64(utils.RandomPort()), 10),
+		APITLSEnable:                           true,
+		CheckPriceDenoms:                       true,
+		DenomExponents:                         "udvpn:6",
+		DrainOnShutdown:                        true,
+		EventLogFile:                           "",
+		EventLogMaxBytes:                       100 << 20,
 		GigabytePrices:                         "udvpn:0.0025,12_500_000",
 		HourlyPrices:                           "udvpn:0.005,25_000_000",
 		IntervalBestRPCAddr:                    (5 * time.Minute).String(),
 		IntervalGeoIPLocation:                  (6 * time.Hour).String(),
 		IntervalPricesUpdate:                   (6 * time.Hour).String(),
+		IntervalRemoteAddrsUpdate:              (15 * time.Minute).String(),
+		IntervalServiceHealth:                  (30 * time.Second).String(),
+		IntervalSessionRetention:               (24 * time.Hour).String(),
 		IntervalSessionUsageSyncWithBlockchain: (2*time.Hour - 5*time.Minute).String(),
 		IntervalSessionUsageSyncWithDatabase:   (2 * time.Second).String(),
 		IntervalSessionUsageValidate:           (5 * time.Second).String(),
 		IntervalSessionValidate:                (5 * time.Minute).String(),
 		IntervalSpeedtest:                      (7 * 24 * time.Hour).String(),
+		IntervalStatusCheck:                    (5 * time.Minute).String(),
 		IntervalStatusUpdate:                   (1*time.Hour - 5*time.Minute).String(),
 		Moniker:                                randMoniker(),
+		OutboundNetwork:                        "tcp",
+		PersistState:                           true,
 		RemoteAddrs:                            []string{"127.0.0.1"},
+		RemoteAddrsAuto:                        false,
+		RemoteAddrsProbe:                       "warn",
+		RemovePeersIfInactive:                  false,
+		ReregisterIfMissing:                    true,
+		RetryBackoffBase:                       (1 * time.Second).String(),
+		RetryBackoffMax:                        (1 * time.Minute).String(),
+		RPCFailoverCooldown:                    (30 * time.Second).String(),
+		ServiceMaxRestarts:                     3,
 		ServiceType:                            randServiceType().String(),
+		SessionRetention:                       (30 * 24 * time.Hour).String(),
+		SessionUpdateBatchSize:                 50,
+		SessionWorkerConcurrency:               2,
+		StaleSessions:                          "delete",
+		StartupJitterMax:                       (0 * time.Second).String(),
+		TLSMinVersion:                          "1.2",
+		TxFailureLog:                           true,
+		TxMemo:                                 "",
 	}
 }
 
@@ -380,3 +930,36 @@ func validateRemoteAddr(addr string) error {
 
 	return fmt.Errorf("unsupported addr %q", addr)
 }
+
+// maxDenomExponent is the largest display exponent accepted for a price denomination.
+const maxDenomExponent = 18
+
+// parseDenomExponents parses a list of <denomination:exponent> entries separated by semicolons.
+func parseDenomExponents(s string) (map[string]uint, error) {
+	items := make(map[string]uint)
+
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		denom, exponent, ok := strings.Cut(entry, ":")
+		if !ok || denom == "" {
+			return nil, fmt.Errorf("invalid entry %q (expected <denom:exponent>)", entry)
+		}
+
+		v, err := strconv.ParseUint(exponent, 10, 8)
+		if err != nil || v > maxDenomExponent {
+			return nil, fmt.Errorf("exponent %q of denom %q must be between 0 and %d", exponent, denom, maxDenomExponent)
+		}
+
+		if _, ok := items[denom]; ok {
+			return nil, fmt.Errorf("duplicate denom %q", denom)
+		}
+
+		items[denom] = uint(v)
+	}
+
+	return items, nil
+}
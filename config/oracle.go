@@ -3,10 +3,31 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"regexp"
 
 	"github.com/spf13/pflag"
 )
 
+// envRefRegexp matches a value referencing an environment variable with the ${ENV_VAR} syntax.
+var envRefRegexp = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// resolveEnvRef returns the value of the environment variable referenced by v with the ${ENV_VAR} syntax, or v
+// itself if it is a literal value. It fails if the referenced environment variable is not set.
+func resolveEnvRef(v string) (string, error) {
+	m := envRefRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return v, nil
+	}
+
+	s, ok := os.LookupEnv(m[1])
+	if !ok {
+		return "", fmt.Errorf("environment variable %q referenced by %q is not set", m[1], v)
+	}
+
+	return s, nil
+}
+
 // CoinGeckoConfig holds settings for the CoinGecko oracle.
 type CoinGeckoConfig struct {
 	APIKey string `mapstructure:"api_key"` // APIKey specifies the API key for CoinGecko.
@@ -17,6 +38,12 @@ func (c *CoinGeckoConfig) GetAPIKey() string {
 	return c.APIKey
 }
 
+// ResolveAPIKey returns the APIKey field, resolving it from the environment if it references an environment
+// variable with the ${ENV_VAR} syntax.
+func (c *CoinGeckoConfig) ResolveAPIKey() (string, error) {
+	return resolveEnvRef(c.APIKey)
+}
+
 // Validate checks the validity of the CoinGeckoConfig configuration.
 func (c *CoinGeckoConfig) Validate() error {
 	return nil
@@ -39,6 +66,12 @@ func (c *OsmosisConfig) GetAPIAddr() string {
 	return c.APIAddr
 }
 
+// ResolveAPIAddr returns the APIAddr field, resolving it from the environment if it references an environment
+// variable with the ${ENV_VAR} syntax.
+func (c *OsmosisConfig) ResolveAPIAddr() (string, error) {
+	return resolveEnvRef(c.APIAddr)
+}
+
 // Validate checks the validity of the OsmosisConfig configuration.
 func (c *OsmosisConfig) Validate() error {
 	if c.APIAddr == "" {
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestResolveEnvRef(t *testing.T) {
+	t.Setenv("DVPNX_TEST_ORACLE_KEY", "secret")
+	t.Setenv("DVPNX_TEST_ORACLE_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"empty literal", "", "", false},
+		{"literal", "plain-key", "plain-key", false},
+		{"literal with dollar", "$DVPNX_TEST_ORACLE_KEY", "$DVPNX_TEST_ORACLE_KEY", false},
+		{"literal with embedded ref", "prefix-${DVPNX_TEST_ORACLE_KEY}", "prefix-${DVPNX_TEST_ORACLE_KEY}", false},
+		{"env ref", "${DVPNX_TEST_ORACLE_KEY}", "secret", false},
+		{"env ref set to empty", "${DVPNX_TEST_ORACLE_EMPTY}", "", false},
+		{"missing env ref", "${DVPNX_TEST_ORACLE_MISSING}", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveEnvRef(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolving %q: %v", tt.value, err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOracleConfigResolve(t *testing.T) {
+	t.Setenv("DVPNX_TEST_OSMOSIS_ADDR", "https://lcd.osmosis.example")
+
+	coinGecko := &CoinGeckoConfig{APIKey: "${DVPNX_TEST_COINGECKO_MISSING}"}
+	if _, err := coinGecko.ResolveAPIKey(); err == nil {
+		t.Fatal("expected an error for a missing coingecko api_key environment variable")
+	}
+
+	osmosis := &OsmosisConfig{APIAddr: "${DVPNX_TEST_OSMOSIS_ADDR}"}
+
+	addr, err := osmosis.ResolveAPIAddr()
+	if err != nil {
+		t.Fatalf("resolving osmosis api_addr: %v", err)
+	}
+
+	if addr != "https://lcd.osmosis.example" {
+		t.Fatalf("expected the environment value, got %q", addr)
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// PingConfig represents the client latency ping configuration.
+type PingConfig struct {
+	Enable        bool `mapstructure:"enable"`         // Enable specifies if the ping endpoint is enabled.
+	RecordLatency bool `mapstructure:"record_latency"` // RecordLatency specifies if client-reported round-trip times are aggregated.
+}
+
+// WithEnable sets the Enable field and returns the updated PingConfig.
+func (c *PingConfig) WithEnable(enable bool) *PingConfig {
+	c.Enable = enable
+
+	return c
+}
+
+// WithRecordLatency sets the RecordLatency field and returns the updated PingConfig.
+func (c *PingConfig) WithRecordLatency(record bool) *PingConfig {
+	c.RecordLatency = record
+
+	return c
+}
+
+// GetEnable returns the Enable field.
+func (c *PingConfig) GetEnable() bool {
+	return c.Enable
+}
+
+// GetRecordLatency returns the RecordLatency field.
+func (c *PingConfig) GetRecordLatency() bool {
+	return c.RecordLatency
+}
+
+// Validate checks the validity of the PingConfig configuration.
+func (c *PingConfig) Validate() error {
+	return nil
+}
+
+// SetForFlags adds ping configuration flags to the specified FlagSet.
+func (c *PingConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.Enable, "ping.enable", c.Enable, "enable or disable the ping endpoint for client latency measurement")
+	f.BoolVar(&c.RecordLatency, "ping.record-latency", c.RecordLatency, "aggregate the round-trip times reported by clients")
+}
+
+// DefaultPingConfig returns a PingConfig instance with default values.
+func DefaultPingConfig() *PingConfig {
+	return &PingConfig{
+		Enable:        true,
+		RecordLatency: false,
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+)
+
+// MaxPlanNameLen is the maximum allowable length for a plan name.
+const MaxPlanNameLen = (1 << 6) - 1
+
+// PlanConfig represents a descriptive pricing tier advertised to clients.
+type PlanConfig struct {
+	Description    string `mapstructure:"description"`     // Description is the human-readable description of the plan.
+	GigabytePrices string `mapstructure:"gigabyte_prices"` // GigabytePrices is the effective pricing information for gigabytes.
+	HourlyPrices   string `mapstructure:"hourly_prices"`   // HourlyPrices is the effective pricing information for hourly usage.
+	Name           string `mapstructure:"name"`            // Name is the unique name of the plan.
+}
+
+// GetDescription returns the Description field.
+func (c *PlanConfig) GetDescription() string {
+	return c.Description
+}
+
+// GetGigabytePrices returns the GigabytePrices field.
+func (c *PlanConfig) GetGigabytePrices() v1.Prices {
+	v, err := v1.NewPricesFromString(c.GigabytePrices)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetHourlyPrices returns the HourlyPrices field.
+func (c *PlanConfig) GetHourlyPrices() v1.Prices {
+	v, err := v1.NewPricesFromString(c.HourlyPrices)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetName returns the Name field.
+func (c *PlanConfig) GetName() string {
+	return c.Name
+}
+
+// Validate checks the validity of the PlanConfig configuration.
+func (c *PlanConfig) Validate() error {
+	// Ensure the Name field is not empty or too long.
+	if c.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	if len(c.Name) > MaxPlanNameLen {
+		return fmt.Errorf("name length cannot be greater than %d", MaxPlanNameLen)
+	}
+
+	// Ensure at least one price is defined.
+	if c.GigabytePrices == "" && c.HourlyPrices == "" {
+		return errors.New("gigabyte_prices and hourly_prices cannot both be empty")
+	}
+
+	// Validate the GigabytePrices field.
+	if _, err := v1.NewPricesFromString(c.GigabytePrices); err != nil {
+		return fmt.Errorf("parsing gigabyte_prices %q: %w", c.GigabytePrices, err)
+	}
+
+	// Validate the HourlyPrices field.
+	if _, err := v1.NewPricesFromString(c.HourlyPrices); err != nil {
+		return fmt.Errorf("parsing hourly_prices %q: %w", c.HourlyPrices, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+)
+
+// pricingWindowLayout is the layout of the start and end times of a pricing window.
+const pricingWindowLayout = "15:04"
+
+// PricingWindowConfig represents a daily time window, in UTC, during which fixed prices are applied on-chain.
+// A window whose end is before its start spans midnight.
+type PricingWindowConfig struct {
+	End            string `mapstructure:"end"`             // End is the time of day, as HH:MM in UTC, at which the window ends.
+	GigabytePrices string `mapstructure:"gigabyte_prices"` // GigabytePrices is the pricing information for gigabytes during the window.
+	HourlyPrices   string `mapstructure:"hourly_prices"`   // HourlyPrices is the pricing information for hourly usage during the window.
+	Start          string `mapstructure:"start"`           // Start is the time of day, as HH:MM in UTC, at which the window starts.
+}
+
+// parseTimeOfDay returns the offset from midnight of the time of day.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse(pricingWindowLayout, s)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// GetEnd returns the End field as an offset from midnight.
+func (c *PricingWindowConfig) GetEnd() time.Duration {
+	v, err := parseTimeOfDay(c.End)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetGigabytePrices returns the GigabytePrices field.
+func (c *PricingWindowConfig) GetGigabytePrices() v1.Prices {
+	v, err := v1.NewPricesFromString(c.GigabytePrices)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetHourlyPrices returns the HourlyPrices field.
+func (c *PricingWindowConfig) GetHourlyPrices() v1.Prices {
+	v, err := v1.NewPricesFromString(c.HourlyPrices)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetStart returns the Start field as an offset from midnight.
+func (c *PricingWindowConfig) GetStart() time.Duration {
+	v, err := parseTimeOfDay(c.Start)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Contains returns whether the time falls within the window.
+func (c *PricingWindowConfig) Contains(t time.Time) bool {
+	t = t.UTC()
+
+	v := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	start, end := c.GetStart(), c.GetEnd()
+
+	if start < end {
+		return v >= start && v < end
+	}
+
+	return v >= start || v < end
+}
+
+// ranges returns the ranges of the day covered by the window, splitting a window spanning midnight in two.
+func (c *PricingWindowConfig) ranges() [][2]time.Duration {
+	start, end := c.GetStart(), c.GetEnd()
+	if start < end {
+		return [][2]time.Duration{{start, end}}
+	}
+
+	return [][2]time.Duration{{start, 24 * time.Hour}, {0, end}}
+}
+
+// Validate checks the validity of the PricingWindowConfig configuration.
+func (c *PricingWindowConfig) Validate() error {
+	start, err := parseTimeOfDay(c.Start)
+	if err != nil {
+		return fmt.Errorf("parsing start %q: %w", c.Start, err)
+	}
+
+	end, err := parseTimeOfDay(c.End)
+	if err != nil {
+		return fmt.Errorf("parsing end %q: %w", c.End, err)
+	}
+
+	if start == end {
+		return errors.New("start and end cannot be equal")
+	}
+
+	// Ensure at least one price is defined.
+	if c.GigabytePrices == "" && c.HourlyPrices == "" {
+		return errors.New("gigabyte_prices and hourly_prices cannot both be empty")
+	}
+
+	// Validate the GigabytePrices field.
+	if _, err := v1.NewPricesFromString(c.GigabytePrices); err != nil {
+		return fmt.Errorf("parsing gigabyte_prices %q: %w", c.GigabytePrices, err)
+	}
+
+	// Validate the HourlyPrices field.
+	if _, err := v1.NewPricesFromString(c.HourlyPrices); err != nil {
+		return fmt.Errorf("parsing hourly_prices %q: %w", c.HourlyPrices, err)
+	}
+
+	return nil
+}
+
+// validatePricingSchedule checks the validity of the pricing windows and that no two of them overlap.
+func validatePricingSchedule(windows []*PricingWindowConfig) error {
+	type item struct {
+		rng    [2]time.Duration
+		window *PricingWindowConfig
+	}
+
+	var items []item
+
+	for _, window := range windows {
+		if err := window.Validate(); err != nil {
+			return fmt.Errorf("validating pricing window %s-%s: %w", window.Start, window.End, err)
+		}
+
+		for _, rng := range window.ranges() {
+			items = append(items, item{rng: rng, window: window})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].rng[0] < items[j].rng[0]
+	})
+
+	for i := 1; i < len(items); i++ {
+		prev, next := items[i-1], items[i]
+		if next.rng[0] < prev.rng[1] {
+			return fmt.Errorf("pricing window %s-%s overlaps pricing window %s-%s",
+				next.window.Start, next.window.End, prev.window.Start, prev.window.End)
+		}
+	}
+
+	return nil
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
+	cosmossdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/pflag"
 )
 
@@ -11,7 +13,35 @@ const MaxQoSMaxPeers = 250 // Maximum allowed value for MaxPeers.
 
 // QoSConfig represents the Quality of Service (QoS) configuration.
 type QoSConfig struct {
-	MaxPeers uint `mapstructure:"max_peers"` // MaxPeers specifies the maximum number of peers.
+	IdleRateThreshold                uint64  `mapstructure:"idle_rate_threshold"`                 // IdleRateThreshold specifies the byte rate in bytes per second below which a session is considered idle.
+	IdleTimeout                      string  `mapstructure:"idle_timeout"`                        // IdleTimeout specifies how long a session must stay idle before its peer is removed.
+	MaxPeers                         uint    `mapstructure:"max_peers"`                           // MaxPeers specifies the maximum number of peers.
+	MaxPeersAuto                     bool    `mapstructure:"max_peers_auto"`                      // MaxPeersAuto specifies if MaxPeers is derived from the measured upload speed.
+	MaxPeersOverflow                 string  `mapstructure:"max_peers_overflow"`                  // MaxPeersOverflow specifies the handling of a MaxPeers exceeding the address capacity of the service.
+	MaxPlausibleThroughputMultiplier float64 `mapstructure:"max_plausible_throughput_multiplier"` // MaxPlausibleThroughputMultiplier specifies the factor of the measured speeds above which the usage of a session is implausible.
+	MaxSessionsPerAccount            uint    `mapstructure:"max_sessions_per_account"`            // MaxSessionsPerAccount specifies the maximum number of concurrent sessions per account.
+	MinDeposit                       string  `mapstructure:"min_deposit"`                         // MinDeposit specifies the minimum session deposit accepted for each denom.
+	MonthlyByteCap                   uint64  `mapstructure:"monthly_byte_cap"`                    // MonthlyByteCap specifies the maximum number of bytes served in a calendar month.
+	PeerBandwidth                    uint64  `mapstructure:"peer_bandwidth"`                      // PeerBandwidth specifies the upload bandwidth budget per peer in bytes per second.
+	PeerRequestReuse                 bool    `mapstructure:"peer_request_reuse"`                  // PeerRequestReuse specifies if the peer request of a removed peer is released for reuse.
+	PerPeerEgressKbps                uint64  `mapstructure:"per_peer_egress_kbps"`                // PerPeerEgressKbps is not supported by any service and is rejected unless zero.
+	PerPeerIngressKbps               uint64  `mapstructure:"per_peer_ingress_kbps"`               // PerPeerIngressKbps is not supported by any service and is rejected unless zero.
+	RequireAllocation                bool    `mapstructure:"require_allocation"`                  // RequireAllocation specifies if handshakes are rejected for sessions with no remaining allocation.
+	UsageAnomalyAction               string  `mapstructure:"usage_anomaly_action"`                // UsageAnomalyAction specifies the handling of a session reporting implausible usage.
+}
+
+// WithIdleRateThreshold sets the IdleRateThreshold field and returns the updated QoSConfig.
+func (c *QoSConfig) WithIdleRateThreshold(threshold uint64) *QoSConfig {
+	c.IdleRateThreshold = threshold
+
+	return c
+}
+
+// WithIdleTimeout sets the IdleTimeout field and returns the updated QoSConfig.
+func (c *QoSConfig) WithIdleTimeout(timeout time.Duration) *QoSConfig {
+	c.IdleTimeout = timeout.String()
+
+	return c
 }
 
 // WithMaxPeers sets the MaxPeers field and returns the updated QoSConfig.
@@ -21,13 +51,163 @@ func (c *QoSConfig) WithMaxPeers(maxPeers uint) *QoSConfig {
 	return c
 }
 
+// WithMaxPeersAuto sets the MaxPeersAuto field and returns the updated QoSConfig.
+func (c *QoSConfig) WithMaxPeersAuto(maxPeersAuto bool) *QoSConfig {
+	c.MaxPeersAuto = maxPeersAuto
+
+	return c
+}
+
+// WithMaxPeersOverflow sets the MaxPeersOverflow field and returns the updated QoSConfig.
+func (c *QoSConfig) WithMaxPeersOverflow(overflow string) *QoSConfig {
+	c.MaxPeersOverflow = overflow
+
+	return c
+}
+
+// WithMaxPlausibleThroughputMultiplier sets the MaxPlausibleThroughputMultiplier field and returns the updated QoSConfig.
+func (c *QoSConfig) WithMaxPlausibleThroughputMultiplier(multiplier float64) *QoSConfig {
+	c.MaxPlausibleThroughputMultiplier = multiplier
+
+	return c
+}
+
+// WithMaxSessionsPerAccount sets the MaxSessionsPerAccount field and returns the updated QoSConfig.
+func (c *QoSConfig) WithMaxSessionsPerAccount(maxSessions uint) *QoSConfig {
+	c.MaxSessionsPerAccount = maxSessions
+
+	return c
+}
+
+// WithMinDeposit sets the MinDeposit field and returns the updated QoSConfig.
+func (c *QoSConfig) WithMinDeposit(coins cosmossdk.Coins) *QoSConfig {
+	c.MinDeposit = coins.String()
+
+	return c
+}
+
+// WithMonthlyByteCap sets the MonthlyByteCap field and returns the updated QoSConfig.
+func (c *QoSConfig) WithMonthlyByteCap(bytes uint64) *QoSConfig {
+	c.MonthlyByteCap = bytes
+
+	return c
+}
+
+// WithPeerBandwidth sets the PeerBandwidth field and returns the updated QoSConfig.
+func (c *QoSConfig) WithPeerBandwidth(bandwidth uint64) *QoSConfig {
+	c.PeerBandwidth = bandwidth
+
+	return c
+}
+
+// WithPeerRequestReuse sets the PeerRequestReuse field and returns the updated QoSConfig.
+func (c *QoSConfig) WithPeerRequestReuse(reuse bool) *QoSConfig {
+	c.PeerRequestReuse = reuse
+
+	return c
+}
+
+// WithRequireAllocation sets the RequireAllocation field and returns the updated QoSConfig.
+func (c *QoSConfig) WithRequireAllocation(require bool) *QoSConfig {
+	c.RequireAllocation = require
+
+	return c
+}
+
+// WithUsageAnomalyAction sets the UsageAnomalyAction field and returns the updated QoSConfig.
+func (c *QoSConfig) WithUsageAnomalyAction(action string) *QoSConfig {
+	c.UsageAnomalyAction = action
+
+	return c
+}
+
+// GetIdleRateThreshold returns the IdleRateThreshold field.
+func (c *QoSConfig) GetIdleRateThreshold() uint64 {
+	return c.IdleRateThreshold
+}
+
+// GetIdleTimeout returns the IdleTimeout field.
+func (c *QoSConfig) GetIdleTimeout() time.Duration {
+	v, err := time.ParseDuration(c.IdleTimeout)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
 // GetMaxPeers returns the MaxPeers field.
 func (c *QoSConfig) GetMaxPeers() uint {
 	return c.MaxPeers
 }
 
+// GetMaxPeersAuto returns the MaxPeersAuto field.
+func (c *QoSConfig) GetMaxPeersAuto() bool {
+	return c.MaxPeersAuto
+}
+
+// GetMaxPeersOverflow returns the MaxPeersOverflow field.
+func (c *QoSConfig) GetMaxPeersOverflow() string {
+	return c.MaxPeersOverflow
+}
+
+// GetMaxPlausibleThroughputMultiplier returns the MaxPlausibleThroughputMultiplier field.
+func (c *QoSConfig) GetMaxPlausibleThroughputMultiplier() float64 {
+	return c.MaxPlausibleThroughputMultiplier
+}
+
+// GetMaxSessionsPerAccount returns the MaxSessionsPerAccount field.
+func (c *QoSConfig) GetMaxSessionsPerAccount() uint {
+	return c.MaxSessionsPerAccount
+}
+
+// GetMinDeposit returns the MinDeposit field.
+func (c *QoSConfig) GetMinDeposit() cosmossdk.Coins {
+	v, err := cosmossdk.ParseCoinsNormalized(c.MinDeposit)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetMonthlyByteCap returns the MonthlyByteCap field.
+func (c *QoSConfig) GetMonthlyByteCap() uint64 {
+	return c.MonthlyByteCap
+}
+
+// GetPeerBandwidth returns the PeerBandwidth field.
+func (c *QoSConfig) GetPeerBandwidth() uint64 {
+	return c.PeerBandwidth
+}
+
+// GetPeerRequestReuse returns the PeerRequestReuse field.
+func (c *QoSConfig) GetPeerRequestReuse() bool {
+	return c.PeerRequestReuse
+}
+
+// GetRequireAllocation returns the RequireAllocation field.
+func (c *QoSConfig) GetRequireAllocation() bool {
+	return c.RequireAllocation
+}
+
+// GetUsageAnomalyAction returns the UsageAnomalyAction field.
+func (c *QoSConfig) GetUsageAnomalyAction() string {
+	return c.UsageAnomalyAction
+}
+
 // Validate checks the validity of the QoS configuration.
 func (c *QoSConfig) Validate() error {
+	// Ensure IdleTimeout is a valid positive duration.
+	idleTimeout, err := time.ParseDuration(c.IdleTimeout)
+	if err != nil {
+		return fmt.Errorf("parsing idle_timeout %q: %w", c.IdleTimeout, err)
+	}
+
+	if idleTimeout <= 0 {
+		return errors.New("idle_timeout must be positive")
+	}
+
 	// Ensure MaxPeers is not zero.
 	if c.MaxPeers == 0 {
 		return errors.New("max_peers cannot be zero")
@@ -38,17 +218,88 @@ func (c *QoSConfig) Validate() error {
 		return fmt.Errorf("max_peers cannot be greater than %d", MaxQoSMaxPeers)
 	}
 
+	// Ensure the per-account session limit can be reached within MaxPeers, unless MaxPeers is derived automatically.
+	if !c.MaxPeersAuto && c.MaxSessionsPerAccount > c.MaxPeers {
+		return fmt.Errorf("max_sessions_per_account cannot be greater than max_peers %d", c.MaxPeers)
+	}
+
+	// Validate the handling of a MaxPeers exceeding the address capacity of the service.
+	validMaxPeersOverflows := map[string]bool{
+		"clamp": true,
+		"error": true,
+	}
+	if !validMaxPeersOverflows[c.MaxPeersOverflow] {
+		return fmt.Errorf("unsupported max_peers_overflow %q (allowed: clamp, error)", c.MaxPeersOverflow)
+	}
+
+	// Ensure MinDeposit is a valid list of coins.
+	if _, err := cosmossdk.ParseCoinsNormalized(c.MinDeposit); err != nil {
+		return fmt.Errorf("parsing min_deposit %q: %w", c.MinDeposit, err)
+	}
+
+	// Ensure the throughput multiplier is not negative; zero disables the usage anomaly check.
+	if c.MaxPlausibleThroughputMultiplier < 0 {
+		return errors.New("max_plausible_throughput_multiplier cannot be negative")
+	}
+
+	// Validate the handling of a session reporting implausible usage.
+	validUsageAnomalyActions := map[string]bool{
+		"remove": true,
+		"skip":   true,
+	}
+	if !validUsageAnomalyActions[c.UsageAnomalyAction] {
+		return fmt.Errorf("unsupported usage_anomaly_action %q (allowed: remove, skip)", c.UsageAnomalyAction)
+	}
+
+	// Ensure a per-peer bandwidth budget is set when MaxPeers is derived automatically.
+	if c.MaxPeersAuto && c.PeerBandwidth == 0 {
+		return errors.New("peer_bandwidth cannot be zero when max_peers_auto is enabled")
+	}
+
+	// Reject per-peer throughput limits, since none of the WireGuard, V2Ray, or OpenVPN services can enforce them.
+	if c.PerPeerEgressKbps != 0 {
+		return errors.New("per_peer_egress_kbps is not supported and must be zero")
+	}
+
+	if c.PerPeerIngressKbps != 0 {
+		return errors.New("per_peer_ingress_kbps is not supported and must be zero")
+	}
+
 	return nil
 }
 
 // SetForFlags adds qos configuration flags to the specified FlagSet.
 func (c *QoSConfig) SetForFlags(f *pflag.FlagSet) {
+	f.Uint64Var(&c.IdleRateThreshold, "qos.idle-rate-threshold", c.IdleRateThreshold, "byte rate in bytes per second below which a session is idle (0 to disable idle eviction)")
+	f.StringVar(&c.IdleTimeout, "qos.idle-timeout", c.IdleTimeout, "duration a session must stay idle before its peer is removed")
 	f.UintVar(&c.MaxPeers, "qos.max-peers", c.MaxPeers, "maximum number of peers for service")
+	f.BoolVar(&c.MaxPeersAuto, "qos.max-peers-auto", c.MaxPeersAuto, "derive maximum number of peers from the measured upload speed")
+	f.StringVar(&c.MaxPeersOverflow, "qos.max-peers-overflow", c.MaxPeersOverflow, "handling of max peers exceeding the address capacity of the service (clamp or error)")
+	f.Float64Var(&c.MaxPlausibleThroughputMultiplier, "qos.max-plausible-throughput-multiplier", c.MaxPlausibleThroughputMultiplier, "factor of the measured speeds above which session usage is implausible (0 to disable)")
+	f.UintVar(&c.MaxSessionsPerAccount, "qos.max-sessions-per-account", c.MaxSessionsPerAccount, "maximum number of concurrent sessions per account (0 for unlimited)")
+	f.StringVar(&c.MinDeposit, "qos.min-deposit", c.MinDeposit, "minimum session deposit accepted for each denom (empty to disable)")
+	f.Uint64Var(&c.MonthlyByteCap, "qos.monthly-byte-cap", c.MonthlyByteCap, "maximum number of bytes served in a calendar month (0 for unlimited)")
+	f.Uint64Var(&c.PeerBandwidth, "qos.peer-bandwidth", c.PeerBandwidth, "upload bandwidth budget per peer in bytes per second")
+	f.BoolVar(&c.PeerRequestReuse, "qos.peer-request-reuse", c.PeerRequestReuse, "release the peer request of a removed peer so returning clients can reuse it")
+	f.BoolVar(&c.RequireAllocation, "qos.require-allocation", c.RequireAllocation, "reject handshakes for sessions with no remaining bytes or duration on-chain")
+	f.StringVar(&c.UsageAnomalyAction, "qos.usage-anomaly-action", c.UsageAnomalyAction, "handling of a session reporting implausible usage (remove or skip)")
 }
 
 // DefaultQoSConfig returns a QoSConfig instance with default values.
 func DefaultQoSConfig() *QoSConfig {
 	return &QoSConfig{
-		MaxPeers: MaxQoSMaxPeers,
+		IdleRateThreshold:                0,
+		IdleTimeout:                      (10 * time.Minute).String(),
+		MaxPeers:                         MaxQoSMaxPeers,
+		MaxPeersAuto:                     false,
+		MaxPeersOverflow:                 "error",
+		MaxPlausibleThroughputMultiplier: 10,
+		MaxSessionsPerAccount:            0,
+		MinDeposit:                       "",
+		MonthlyByteCap:                   0,
+		PeerBandwidth:                    1_250_000,
+		PeerRequestReuse:                 false,
+		RequireAllocation:                false,
+		UsageAnomalyAction:               "skip",
 	}
 }
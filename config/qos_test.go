@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestQoSConfigValidatePerPeerLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		egressKbps  uint64
+		ingressKbps uint64
+		wantErr     bool
+	}{
+		{"unset", 0, 0, false},
+		{"egress set", 50000, 0, true},
+		{"ingress set", 0, 50000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultQoSConfig()
+			c.PerPeerEgressKbps = tt.egressKbps
+			c.PerPeerIngressKbps = tt.ingressKbps
+
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// RateLimitConfig represents the configuration of the backoff from rate-limited RPC endpoints.
+type RateLimitConfig struct {
+	DefaultBackoff string `mapstructure:"default_backoff"` // DefaultBackoff is the backoff applied to a rate-limited RPC endpoint without a Retry-After header.
+	Enable         bool   `mapstructure:"enable"`          // Enable specifies if rate-limited RPC endpoints are backed off.
+	MaxBackoff     string `mapstructure:"max_backoff"`     // MaxBackoff is the upper bound of the backoff requested by a Retry-After header.
+}
+
+// WithDefaultBackoff sets the DefaultBackoff field and returns the updated RateLimitConfig.
+func (c *RateLimitConfig) WithDefaultBackoff(backoff time.Duration) *RateLimitConfig {
+	c.DefaultBackoff = backoff.String()
+
+	return c
+}
+
+// WithEnable sets the Enable field and returns the updated RateLimitConfig.
+func (c *RateLimitConfig) WithEnable(enable bool) *RateLimitConfig {
+	c.Enable = enable
+
+	return c
+}
+
+// WithMaxBackoff sets the MaxBackoff field and returns the updated RateLimitConfig.
+func (c *RateLimitConfig) WithMaxBackoff(backoff time.Duration) *RateLimitConfig {
+	c.MaxBackoff = backoff.String()
+
+	return c
+}
+
+// GetDefaultBackoff returns the DefaultBackoff field.
+func (c *RateLimitConfig) GetDefaultBackoff() time.Duration {
+	v, err := time.ParseDuration(c.DefaultBackoff)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetEnable returns the Enable field.
+func (c *RateLimitConfig) GetEnable() bool {
+	return c.Enable
+}
+
+// GetMaxBackoff returns the MaxBackoff field.
+func (c *RateLimitConfig) GetMaxBackoff() time.Duration {
+	v, err := time.ParseDuration(c.MaxBackoff)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Validate checks the validity of the RateLimitConfig configuration.
+func (c *RateLimitConfig) Validate() error {
+	defaultBackoff, err := time.ParseDuration(c.DefaultBackoff)
+	if err != nil {
+		return fmt.Errorf("parsing default_backoff %q: %w", c.DefaultBackoff, err)
+	}
+
+	// Ensure DefaultBackoff is positive.
+	if defaultBackoff <= 0 {
+		return errors.New("default_backoff must be positive")
+	}
+
+	maxBackoff, err := time.ParseDuration(c.MaxBackoff)
+	if err != nil {
+		return fmt.Errorf("parsing max_backoff %q: %w", c.MaxBackoff, err)
+	}
+
+	// Ensure MaxBackoff is not below DefaultBackoff.
+	if maxBackoff < defaultBackoff {
+		return errors.New("max_backoff cannot be less than default_backoff")
+	}
+
+	return nil
+}
+
+// SetForFlags adds rate limit configuration flags to the specified FlagSet.
+func (c *RateLimitConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.DefaultBackoff, "rate-limit.default-backoff", c.DefaultBackoff, "backoff applied to a rate-limited RPC endpoint without a Retry-After header")
+	f.BoolVar(&c.Enable, "rate-limit.enable", c.Enable, "enable or disable the backoff from rate-limited RPC endpoints")
+	f.StringVar(&c.MaxBackoff, "rate-limit.max-backoff", c.MaxBackoff, "upper bound of the backoff requested by a Retry-After header")
+}
+
+// DefaultRateLimitConfig returns a RateLimitConfig instance with default values.
+func DefaultRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		DefaultBackoff: (30 * time.Second).String(),
+		Enable:         true,
+		MaxBackoff:     (10 * time.Minute).String(),
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// ReconcileConfig represents the configuration of the periodic check between database sessions and service peers.
+type ReconcileConfig struct {
+	Enable              bool   `mapstructure:"enable"`                // Enable specifies if the periodic reconciliation check is enabled.
+	Interval            string `mapstructure:"interval"`              // Interval is the duration between reconciliation checks.
+	RemoveOrphanedPeers bool   `mapstructure:"remove_orphaned_peers"` // RemoveOrphanedPeers specifies if peers without a session record are removed from the service.
+	Tolerance           uint   `mapstructure:"tolerance"`             // Tolerance is the divergence between sessions and peers tolerated without a warning.
+}
+
+// WithEnable sets the Enable field and returns the updated ReconcileConfig.
+func (c *ReconcileConfig) WithEnable(enable bool) *ReconcileConfig {
+	c.Enable = enable
+
+	return c
+}
+
+// WithInterval sets the Interval field and returns the updated ReconcileConfig.
+func (c *ReconcileConfig) WithInterval(interval time.Duration) *ReconcileConfig {
+	c.Interval = interval.String()
+
+	return c
+}
+
+// WithRemoveOrphanedPeers sets the RemoveOrphanedPeers field and returns the updated ReconcileConfig.
+func (c *ReconcileConfig) WithRemoveOrphanedPeers(remove bool) *ReconcileConfig {
+	c.RemoveOrphanedPeers = remove
+
+	return c
+}
+
+// WithTolerance sets the Tolerance field and returns the updated ReconcileConfig.
+func (c *ReconcileConfig) WithTolerance(tolerance uint) *ReconcileConfig {
+	c.Tolerance = tolerance
+
+	return c
+}
+
+// GetEnable returns the Enable field.
+func (c *ReconcileConfig) GetEnable() bool {
+	return c.Enable
+}
+
+// GetInterval returns the Interval field.
+func (c *ReconcileConfig) GetInterval() time.Duration {
+	v, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetRemoveOrphanedPeers returns the RemoveOrphanedPeers field.
+func (c *ReconcileConfig) GetRemoveOrphanedPeers() bool {
+	return c.RemoveOrphanedPeers
+}
+
+// GetTolerance returns the Tolerance field.
+func (c *ReconcileConfig) GetTolerance() uint {
+	return c.Tolerance
+}
+
+// Validate checks the validity of the ReconcileConfig configuration.
+func (c *ReconcileConfig) Validate() error {
+	v, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return fmt.Errorf("parsing interval %q: %w", c.Interval, err)
+	}
+
+	// Ensure Interval is positive.
+	if v <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	return nil
+}
+
+// SetForFlags adds reconcile configuration flags to the specified FlagSet.
+func (c *ReconcileConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.Enable, "reconcile.enable", c.Enable, "enable or disable the periodic check between database sessions and service peers")
+	f.StringVar(&c.Interval, "reconcile.interval", c.Interval, "interval for checking database sessions against service peers")
+	f.BoolVar(&c.RemoveOrphanedPeers, "reconcile.remove-orphaned-peers", c.RemoveOrphanedPeers, "remove peers without a session record from the service")
+	f.UintVar(&c.Tolerance, "reconcile.tolerance", c.Tolerance, "divergence between sessions and peers tolerated without a warning")
+}
+
+// DefaultReconcileConfig returns a ReconcileConfig instance with default values.
+func DefaultReconcileConfig() *ReconcileConfig {
+	return &ReconcileConfig{
+		Enable:              true,
+		Interval:            (5 * time.Minute).String(),
+		RemoveOrphanedPeers: false,
+		Tolerance:           10,
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// ReplayConfig represents the configuration of the protection against replayed handshake requests.
+type ReplayConfig struct {
+	Enable           bool   `mapstructure:"enable"`            // Enable specifies if replayed handshake requests are rejected.
+	RequireTimestamp bool   `mapstructure:"require_timestamp"` // RequireTimestamp specifies if handshake requests without an embedded timestamp are rejected.
+	Window           string `mapstructure:"window"`            // Window is the maximum age of a handshake request and the duration for which its hash is remembered.
+}
+
+// WithEnable sets the Enable field and returns the updated ReplayConfig.
+func (c *ReplayConfig) WithEnable(enable bool) *ReplayConfig {
+	c.Enable = enable
+
+	return c
+}
+
+// WithRequireTimestamp sets the RequireTimestamp field and returns the updated ReplayConfig.
+func (c *ReplayConfig) WithRequireTimestamp(require bool) *ReplayConfig {
+	c.RequireTimestamp = require
+
+	return c
+}
+
+// WithWindow sets the Window field and returns the updated ReplayConfig.
+func (c *ReplayConfig) WithWindow(window time.Duration) *ReplayConfig {
+	c.Window = window.String()
+
+	return c
+}
+
+// GetEnable returns the Enable field.
+func (c *ReplayConfig) GetEnable() bool {
+	return c.Enable
+}
+
+// GetRequireTimestamp returns the RequireTimestamp field.
+func (c *ReplayConfig) GetRequireTimestamp() bool {
+	return c.RequireTimestamp
+}
+
+// GetWindow returns the Window field.
+func (c *ReplayConfig) GetWindow() time.Duration {
+	v, err := time.ParseDuration(c.Window)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Validate checks the validity of the ReplayConfig configuration.
+func (c *ReplayConfig) Validate() error {
+	window, err := time.ParseDuration(c.Window)
+	if err != nil {
+		return fmt.Errorf("parsing window %q: %w", c.Window, err)
+	}
+
+	if window <= 0 {
+		return errors.New("window must be positive")
+	}
+
+	return nil
+}
+
+// SetForFlags adds replay protection configuration flags to the specified FlagSet.
+func (c *ReplayConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.Enable, "replay.enable", c.Enable, "reject replayed handshake requests")
+	f.BoolVar(&c.RequireTimestamp, "replay.require-timestamp", c.RequireTimestamp, "reject handshake requests without an embedded timestamp")
+	f.StringVar(&c.Window, "replay.window", c.Window, "maximum age of a handshake request and duration for which its hash is remembered")
+}
+
+// DefaultReplayConfig returns a ReplayConfig instance with default values.
+func DefaultReplayConfig() *ReplayConfig {
+	return &ReplayConfig{
+		Enable:           true,
+		RequireTimestamp: false,
+		Window:           (5 * time.Minute).String(),
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// RPCTLSConfig represents the TLS configuration of the connections to the RPC addresses.
+type RPCTLSConfig struct {
+	CACertFile         string `mapstructure:"ca_cert_file"`         // CACertFile is the path of a PEM bundle of CA certificates trusted for the RPC addresses.
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // InsecureSkipVerify specifies if the TLS certificates of the RPC addresses are not verified.
+}
+
+// WithCACertFile sets the CACertFile field and returns the updated RPCTLSConfig.
+func (c *RPCTLSConfig) WithCACertFile(file string) *RPCTLSConfig {
+	c.CACertFile = file
+
+	return c
+}
+
+// WithInsecureSkipVerify sets the InsecureSkipVerify field and returns the updated RPCTLSConfig.
+func (c *RPCTLSConfig) WithInsecureSkipVerify(skip bool) *RPCTLSConfig {
+	c.InsecureSkipVerify = skip
+
+	return c
+}
+
+// GetCACertFile returns the CACertFile field.
+func (c *RPCTLSConfig) GetCACertFile() string {
+	return c.CACertFile
+}
+
+// GetInsecureSkipVerify returns the InsecureSkipVerify field.
+func (c *RPCTLSConfig) GetInsecureSkipVerify() bool {
+	return c.InsecureSkipVerify
+}
+
+// CertPool returns the system certificate pool extended with the certificates of the CA bundle, or nil if no CA
+// bundle is set.
+func (c *RPCTLSConfig) CertPool() (*x509.CertPool, error) {
+	if c.CACertFile == "" {
+		return nil, nil
+	}
+
+	buf, err := os.ReadFile(c.CACertFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading ca_cert_file %q: %w", c.CACertFile, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(buf) {
+		return nil, fmt.Errorf("ca_cert_file %q contains no valid PEM certificates", c.CACertFile)
+	}
+
+	return pool, nil
+}
+
+// Validate checks the validity of the RPCTLSConfig configuration.
+func (c *RPCTLSConfig) Validate() error {
+	if _, err := c.CertPool(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetForFlags adds RPC TLS configuration flags to the specified FlagSet.
+func (c *RPCTLSConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.CACertFile, "rpc-tls.ca-cert-file", c.CACertFile, "path of a PEM bundle of CA certificates trusted for the RPC addresses")
+	f.BoolVar(&c.InsecureSkipVerify, "rpc-tls.insecure-skip-verify", c.InsecureSkipVerify, "skip verifying the TLS certificates of the RPC addresses (testing only)")
+}
+
+// DefaultRPCTLSConfig returns an RPCTLSConfig instance with default values.
+func DefaultRPCTLSConfig() *RPCTLSConfig {
+	return &RPCTLSConfig{
+		CACertFile:         "",
+		InsecureSkipVerify: false,
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// SchedulerConfig represents the scheduler configuration.
+type SchedulerConfig struct {
+	ExposeSchedule bool `mapstructure:"expose_schedule"` // ExposeSchedule specifies if the worker schedule is served by the API.
+}
+
+// WithExposeSchedule sets the ExposeSchedule field and returns the updated SchedulerConfig.
+func (c *SchedulerConfig) WithExposeSchedule(expose bool) *SchedulerConfig {
+	c.ExposeSchedule = expose
+
+	return c
+}
+
+// GetExposeSchedule returns the ExposeSchedule field.
+func (c *SchedulerConfig) GetExposeSchedule() bool {
+	return c.ExposeSchedule
+}
+
+// Validate checks the validity of the SchedulerConfig configuration.
+func (c *SchedulerConfig) Validate() error {
+	return nil
+}
+
+// SetForFlags adds scheduler configuration flags to the specified FlagSet.
+func (c *SchedulerConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.ExposeSchedule, "scheduler.expose-schedule", c.ExposeSchedule, "serve the last and next run times of the scheduler workers at /workers/schedule")
+}
+
+// DefaultSchedulerConfig returns a SchedulerConfig instance with default values.
+func DefaultSchedulerConfig() *SchedulerConfig {
+	return &SchedulerConfig{
+		ExposeSchedule: false,
+	}
+}
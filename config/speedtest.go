@@ -0,0 +1,69 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// SpeedtestConfig represents the speed test configuration.
+type SpeedtestConfig struct {
+	Enable        bool   `mapstructure:"enable"`          // Enable specifies if the periodic speed test is enabled.
+	StaticDLSpeed uint64 `mapstructure:"static_dl_speed"` // StaticDLSpeed specifies the download speed reported when no measurement is available.
+	StaticULSpeed uint64 `mapstructure:"static_ul_speed"` // StaticULSpeed specifies the upload speed reported when no measurement is available.
+}
+
+// WithEnable sets the Enable field and returns the updated SpeedtestConfig.
+func (c *SpeedtestConfig) WithEnable(enable bool) *SpeedtestConfig {
+	c.Enable = enable
+
+	return c
+}
+
+// WithStaticDLSpeed sets the StaticDLSpeed field and returns the updated SpeedtestConfig.
+func (c *SpeedtestConfig) WithStaticDLSpeed(speed uint64) *SpeedtestConfig {
+	c.StaticDLSpeed = speed
+
+	return c
+}
+
+// WithStaticULSpeed sets the StaticULSpeed field and returns the updated SpeedtestConfig.
+func (c *SpeedtestConfig) WithStaticULSpeed(speed uint64) *SpeedtestConfig {
+	c.StaticULSpeed = speed
+
+	return c
+}
+
+// GetEnable returns the Enable field.
+func (c *SpeedtestConfig) GetEnable() bool {
+	return c.Enable
+}
+
+// GetStaticDLSpeed returns the StaticDLSpeed field.
+func (c *SpeedtestConfig) GetStaticDLSpeed() uint64 {
+	return c.StaticDLSpeed
+}
+
+// GetStaticULSpeed returns the StaticULSpeed field.
+func (c *SpeedtestConfig) GetStaticULSpeed() uint64 {
+	return c.StaticULSpeed
+}
+
+// Validate checks the validity of the SpeedtestConfig configuration.
+func (c *SpeedtestConfig) Validate() error {
+	return nil
+}
+
+// SetForFlags adds speedtest configuration flags to the specified FlagSet.
+func (c *SpeedtestConfig) SetForFlags(f *pflag.FlagSet) {
+	f.BoolVar(&c.Enable, "speedtest.enable", c.Enable, "enable or disable the periodic speed test")
+	f.Uint64Var(&c.StaticDLSpeed, "speedtest.static-dl-speed", c.StaticDLSpeed, "download speed in bytes per second reported when no measurement is available")
+	f.Uint64Var(&c.StaticULSpeed, "speedtest.static-ul-speed", c.StaticULSpeed, "upload speed in bytes per second reported when no measurement is available")
+}
+
+// DefaultSpeedtestConfig returns a SpeedtestConfig instance with default values.
+func DefaultSpeedtestConfig() *SpeedtestConfig {
+	return &SpeedtestConfig{
+		Enable:        true,
+		StaticDLSpeed: 0,
+		StaticULSpeed: 0,
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+	"github.com/spf13/pflag"
+)
+
+// Ranges of the tunnel settings surfaced to clients; zero leaves a setting to the client.
+const (
+	minTunnelMTU       = 576
+	maxTunnelMTU       = 1500
+	maxTunnelKeepalive = 65535
+)
+
+// TunnelConfig represents the per-protocol tunnel settings surfaced to clients in the handshake response.
+type TunnelConfig struct {
+	OpenVPNKeepalive   uint `mapstructure:"openvpn_keepalive"`   // OpenVPNKeepalive is the keepalive interval in seconds for OpenVPN clients.
+	OpenVPNMTU         uint `mapstructure:"openvpn_mtu"`         // OpenVPNMTU is the tunnel MTU for OpenVPN clients.
+	WireGuardKeepalive uint `mapstructure:"wireguard_keepalive"` // WireGuardKeepalive is the persistent keepalive interval in seconds for WireGuard clients.
+	WireGuardMTU       uint `mapstructure:"wireguard_mtu"`       // WireGuardMTU is the tunnel MTU for WireGuard clients.
+}
+
+// WithOpenVPNKeepalive sets the OpenVPNKeepalive field and returns the updated TunnelConfig.
+func (c *TunnelConfig) WithOpenVPNKeepalive(keepalive uint) *TunnelConfig {
+	c.OpenVPNKeepalive = keepalive
+
+	return c
+}
+
+// WithOpenVPNMTU sets the OpenVPNMTU field and returns the updated TunnelConfig.
+func (c *TunnelConfig) WithOpenVPNMTU(mtu uint) *TunnelConfig {
+	c.OpenVPNMTU = mtu
+
+	return c
+}
+
+// WithWireGuardKeepalive sets the WireGuardKeepalive field and returns the updated TunnelConfig.
+func (c *TunnelConfig) WithWireGuardKeepalive(keepalive uint) *TunnelConfig {
+	c.WireGuardKeepalive = keepalive
+
+	return c
+}
+
+// WithWireGuardMTU sets the WireGuardMTU field and returns the updated TunnelConfig.
+func (c *TunnelConfig) WithWireGuardMTU(mtu uint) *TunnelConfig {
+	c.WireGuardMTU = mtu
+
+	return c
+}
+
+// GetOpenVPNKeepalive returns the OpenVPNKeepalive field.
+func (c *TunnelConfig) GetOpenVPNKeepalive() uint {
+	return c.OpenVPNKeepalive
+}
+
+// GetOpenVPNMTU returns the OpenVPNMTU field.
+func (c *TunnelConfig) GetOpenVPNMTU() uint {
+	return c.OpenVPNMTU
+}
+
+// GetWireGuardKeepalive returns the WireGuardKeepalive field.
+func (c *TunnelConfig) GetWireGuardKeepalive() uint {
+	return c.WireGuardKeepalive
+}
+
+// GetWireGuardMTU returns the WireGuardMTU field.
+func (c *TunnelConfig) GetWireGuardMTU() uint {
+	return c.WireGuardMTU
+}
+
+// Settings returns the MTU and keepalive surfaced to clients of the given service type.
+// Service types without tunnel settings return zero values.
+func (c *TunnelConfig) Settings(t types.ServiceType) (mtu, keepalive uint) {
+	switch t {
+	case types.ServiceTypeOpenVPN:
+		return c.OpenVPNMTU, c.OpenVPNKeepalive
+	case types.ServiceTypeWireGuard:
+		return c.WireGuardMTU, c.WireGuardKeepalive
+	default:
+		return 0, 0
+	}
+}
+
+// validateTunnelMTU checks that a non-zero MTU is within the allowed range.
+func validateTunnelMTU(name string, mtu uint) error {
+	if mtu != 0 && (mtu < minTunnelMTU || mtu > maxTunnelMTU) {
+		return fmt.Errorf("%s must be 0 or between %d and %d", name, minTunnelMTU, maxTunnelMTU)
+	}
+
+	return nil
+}
+
+// Validate checks the validity of the TunnelConfig configuration.
+func (c *TunnelConfig) Validate() error {
+	if c.OpenVPNKeepalive > maxTunnelKeepalive {
+		return fmt.Errorf("openvpn_keepalive must be between 0 and %d", maxTunnelKeepalive)
+	}
+
+	if err := validateTunnelMTU("openvpn_mtu", c.OpenVPNMTU); err != nil {
+		return err
+	}
+
+	if c.WireGuardKeepalive > maxTunnelKeepalive {
+		return fmt.Errorf("wireguard_keepalive must be between 0 and %d", maxTunnelKeepalive)
+	}
+
+	if err := validateTunnelMTU("wireguard_mtu", c.WireGuardMTU); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetForFlags adds tunnel configuration flags to the specified FlagSet.
+func (c *TunnelConfig) SetForFlags(f *pflag.FlagSet) {
+	f.UintVar(&c.OpenVPNKeepalive, "tunnel.openvpn-keepalive", c.OpenVPNKeepalive, "keepalive interval in seconds surfaced to OpenVPN clients (0 leaves it to the client)")
+	f.UintVar(&c.OpenVPNMTU, "tunnel.openvpn-mtu", c.OpenVPNMTU, "tunnel MTU surfaced to OpenVPN clients (0 leaves it to the client)")
+	f.UintVar(&c.WireGuardKeepalive, "tunnel.wireguard-keepalive", c.WireGuardKeepalive, "persistent keepalive interval in seconds surfaced to WireGuard clients (0 leaves it to the client)")
+	f.UintVar(&c.WireGuardMTU, "tunnel.wireguard-mtu", c.WireGuardMTU, "tunnel MTU surfaced to WireGuard clients (0 leaves it to the client)")
+}
+
+// DefaultTunnelConfig returns a TunnelConfig instance with default values.
+func DefaultTunnelConfig() *TunnelConfig {
+	return &TunnelConfig{
+		OpenVPNKeepalive:   0,
+		OpenVPNMTU:         0,
+		WireGuardKeepalive: 0,
+		WireGuardMTU:       0,
+	}
+}
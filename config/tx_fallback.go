@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/spf13/pflag"
+)
+
+// TxFallbackConfig represents the configuration of the fee settings retried when a transaction is rejected for
+// insufficient fees with the gas prices of the tx configuration, or times out waiting to be included in a block.
+type TxFallbackConfig struct {
+	GasPriceCeiling string   `mapstructure:"gas_price_ceiling"` // GasPriceCeiling is the highest gas price of each denomination reached by the adjustments.
+	GasPriceFactor  float64  `mapstructure:"gas_price_factor"`  // GasPriceFactor is the factor the gas prices of the next transaction are multiplied by after a transaction times out, or zero to disable.
+	GasPrices       []string `mapstructure:"gas_prices"`        // GasPrices is the list of gas prices tried in order after the primary gas prices.
+}
+
+// WithGasPriceCeiling sets the GasPriceCeiling field and returns the updated TxFallbackConfig.
+func (c *TxFallbackConfig) WithGasPriceCeiling(ceiling string) *TxFallbackConfig {
+	c.GasPriceCeiling = ceiling
+
+	return c
+}
+
+// WithGasPriceFactor sets the GasPriceFactor field and returns the updated TxFallbackConfig.
+func (c *TxFallbackConfig) WithGasPriceFactor(factor float64) *TxFallbackConfig {
+	c.GasPriceFactor = factor
+
+	return c
+}
+
+// WithGasPrices sets the GasPrices field and returns the updated TxFallbackConfig.
+func (c *TxFallbackConfig) WithGasPrices(prices []string) *TxFallbackConfig {
+	c.GasPrices = prices
+
+	return c
+}
+
+// GetGasPriceCeiling returns the GasPriceCeiling field parsed as DecCoins.
+func (c *TxFallbackConfig) GetGasPriceCeiling() types.DecCoins {
+	v, err := types.ParseDecCoins(c.GasPriceCeiling)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetGasPriceFactor returns the GasPriceFactor field.
+func (c *TxFallbackConfig) GetGasPriceFactor() float64 {
+	return c.GasPriceFactor
+}
+
+// GetGasPrices returns the GasPrices field parsed as DecCoins.
+func (c *TxFallbackConfig) GetGasPrices() []types.DecCoins {
+	items := make([]types.DecCoins, 0, len(c.GasPrices))
+
+	for _, s := range c.GasPrices {
+		v, err := types.ParseDecCoins(s)
+		if err != nil {
+			panic(err)
+		}
+
+		items = append(items, v)
+	}
+
+	return items
+}
+
+// Validate checks the validity of the TxFallbackConfig configuration.
+func (c *TxFallbackConfig) Validate() error {
+	v, err := types.ParseDecCoins(c.GasPriceCeiling)
+	if err != nil {
+		return fmt.Errorf("parsing gas_price_ceiling %q: %w", c.GasPriceCeiling, err)
+	}
+
+	if c.GasPriceFactor < 0 {
+		return errors.New("gas_price_factor cannot be negative")
+	}
+
+	if c.GasPriceFactor != 0 {
+		if c.GasPriceFactor <= 1 {
+			return errors.New("gas_price_factor must be greater than 1 or zero")
+		}
+
+		if v.IsZero() {
+			return errors.New("gas_price_ceiling cannot be empty if gas_price_factor is set")
+		}
+	}
+
+	for _, s := range c.GasPrices {
+		v, err := types.ParseDecCoins(s)
+		if err != nil {
+			return fmt.Errorf("parsing gas_prices entry %q: %w", s, err)
+		}
+
+		if v.IsZero() {
+			return fmt.Errorf("gas_prices entry %q cannot be zero", s)
+		}
+	}
+
+	return nil
+}
+
+// SetForFlags adds tx fallback configuration flags to the specified FlagSet.
+func (c *TxFallbackConfig) SetForFlags(f *pflag.FlagSet) {
+	f.StringVar(&c.GasPriceCeiling, "tx-fallback.gas-price-ceiling", c.GasPriceCeiling, "highest gas price of each denomination reached by the gas price adjustments")
+	f.Float64Var(&c.GasPriceFactor, "tx-fallback.gas-price-factor", c.GasPriceFactor, "factor the gas prices of the next transaction are multiplied by after a transaction times out (0 to disable)")
+	f.StringSliceVar(&c.GasPrices, "tx-fallback.gas-prices", c.GasPrices, "gas prices tried in order when a transaction is rejected for insufficient fees")
+}
+
+// DefaultTxFallbackConfig returns a TxFallbackConfig instance with default values.
+func DefaultTxFallbackConfig() *TxFallbackConfig {
+	return &TxFallbackConfig{
+		GasPriceCeiling: "",
+		GasPriceFactor:  0,
+		GasPrices:       []string{},
+	}
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// WebhookConfig represents the webhook event delivery configuration.
+type WebhookConfig struct {
+	BatchSize      uint   `mapstructure:"batch_size"`      // BatchSize is the maximum number of events delivered in a single request.
+	Enabled        bool   `mapstructure:"enabled"`         // Enabled specifies if the events are delivered to the URL.
+	FlushTimeout   string `mapstructure:"flush_timeout"`   // FlushTimeout is the maximum duration for delivering the pending events at shutdown.
+	PersistPending bool   `mapstructure:"persist_pending"` // PersistPending specifies if the events left undelivered at shutdown are saved for the next start.
+	QueueSize      uint   `mapstructure:"queue_size"`      // QueueSize is the maximum number of events buffered for delivery.
+	RetryAttempts  uint   `mapstructure:"retry_attempts"`  // RetryAttempts is the number of attempts for delivering a batch.
+	RetryDelay     string `mapstructure:"retry_delay"`     // RetryDelay is the base duration between delivery retries, doubled on each attempt.
+	Secret         string `mapstructure:"secret"`          // Secret is the key of the HMAC-SHA256 signature of the request bodies, empty to disable signing.
+	SendInterval   string `mapstructure:"send_interval"`   // SendInterval is the minimum duration between consecutive deliveries.
+	Timeout        string `mapstructure:"timeout"`         // Timeout is the maximum duration of a single delivery request.
+	URL            string `mapstructure:"url"`             // URL is the endpoint receiving the events, empty to disable delivery.
+}
+
+// WithBatchSize sets the BatchSize field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithBatchSize(size uint) *WebhookConfig {
+	c.BatchSize = size
+
+	return c
+}
+
+// WithEnabled sets the Enabled field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithEnabled(enabled bool) *WebhookConfig {
+	c.Enabled = enabled
+
+	return c
+}
+
+// WithFlushTimeout sets the FlushTimeout field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithFlushTimeout(timeout time.Duration) *WebhookConfig {
+	c.FlushTimeout = timeout.String()
+
+	return c
+}
+
+// WithPersistPending sets the PersistPending field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithPersistPending(persist bool) *WebhookConfig {
+	c.PersistPending = persist
+
+	return c
+}
+
+// WithQueueSize sets the QueueSize field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithQueueSize(size uint) *WebhookConfig {
+	c.QueueSize = size
+
+	return c
+}
+
+// WithRetryAttempts sets the RetryAttempts field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithRetryAttempts(attempts uint) *WebhookConfig {
+	c.RetryAttempts = attempts
+
+	return c
+}
+
+// WithRetryDelay sets the RetryDelay field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithRetryDelay(delay time.Duration) *WebhookConfig {
+	c.RetryDelay = delay.String()
+
+	return c
+}
+
+// WithSecret sets the Secret field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithSecret(secret string) *WebhookConfig {
+	c.Secret = secret
+
+	return c
+}
+
+// WithSendInterval sets the SendInterval field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithSendInterval(interval time.Duration) *WebhookConfig {
+	c.SendInterval = interval.String()
+
+	return c
+}
+
+// WithTimeout sets the Timeout field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithTimeout(timeout time.Duration) *WebhookConfig {
+	c.Timeout = timeout.String()
+
+	return c
+}
+
+// WithURL sets the URL field and returns the updated WebhookConfig.
+func (c *WebhookConfig) WithURL(url string) *WebhookConfig {
+	c.URL = url
+
+	return c
+}
+
+// GetBatchSize returns the BatchSize field.
+func (c *WebhookConfig) GetBatchSize() uint {
+	return c.BatchSize
+}
+
+// GetEnabled returns the Enabled field.
+func (c *WebhookConfig) GetEnabled() bool {
+	return c.Enabled
+}
+
+// GetFlushTimeout returns the FlushTimeout field.
+func (c *WebhookConfig) GetFlushTimeout() time.Duration {
+	v, err := time.ParseDuration(c.FlushTimeout)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetPersistPending returns the PersistPending field.
+func (c *WebhookConfig) GetPersistPending() bool {
+	return c.PersistPending
+}
+
+// GetQueueSize returns the QueueSize field.
+func (c *WebhookConfig) GetQueueSize() uint {
+	return c.QueueSize
+}
+
+// GetRetryAttempts returns the RetryAttempts field.
+func (c *WebhookConfig) GetRetryAttempts() uint {
+	return c.RetryAttempts
+}
+
+// GetRetryDelay returns the RetryDelay field.
+func (c *WebhookConfig) GetRetryDelay() time.Duration {
+	v, err := time.ParseDuration(c.RetryDelay)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetSecret returns the Secret field.
+func (c *WebhookConfig) GetSecret() string {
+	return c.Secret
+}
+
+// GetSendInterval returns the SendInterval field.
+func (c *WebhookConfig) GetSendInterval() time.Duration {
+	v, err := time.ParseDuration(c.SendInterval)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetTimeout returns the Timeout field.
+func (c *WebhookConfig) GetTimeout() time.Duration {
+	v, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// GetURL returns the URL field.
+func (c *WebhookConfig) GetURL() string {
+	return c.URL
+}
+
+// Validate checks the validity of the WebhookConfig configuration.
+func (c *WebhookConfig) Validate() error {
+	// Ensure the batch, queue and retry settings are not zero.
+	if c.BatchSize == 0 {
+		return errors.New("batch_size cannot be zero")
+	}
+
+	if c.QueueSize == 0 {
+		return errors.New("queue_size cannot be zero")
+	}
+
+	if c.RetryAttempts == 0 {
+		return errors.New("retry_attempts cannot be zero")
+	}
+
+	// Validate duration fields.
+	if _, err := time.ParseDuration(c.FlushTimeout); err != nil {
+		return fmt.Errorf("parsing flush_timeout %q: %w", c.FlushTimeout, err)
+	}
+
+	if _, err := time.ParseDuration(c.RetryDelay); err != nil {
+		return fmt.Errorf("parsing retry_delay %q: %w", c.RetryDelay, err)
+	}
+
+	if _, err := time.ParseDuration(c.SendInterval); err != nil {
+		return fmt.Errorf("parsing send_interval %q: %w", c.SendInterval, err)
+	}
+
+	if _, err := time.ParseDuration(c.Timeout); err != nil {
+		return fmt.Errorf("parsing timeout %q: %w", c.Timeout, err)
+	}
+
+	// Validate the URL field if set.
+	if c.URL != "" {
+		v, err := url.ParseRequestURI(c.URL)
+		if err != nil {
+			return fmt.Errorf("parsing url %q: %w", c.URL, err)
+		}
+
+		if v.Scheme != "http" && v.Scheme != "https" {
+			return fmt.Errorf("unsupported url scheme %q (allowed: http, https)", v.Scheme)
+		}
+	}
+
+	return nil
+}
+
+// SetForFlags adds webhook configuration flags to the specified FlagSet.
+func (c *WebhookConfig) SetForFlags(f *pflag.FlagSet) {
+	f.UintVar(&c.BatchSize, "webhook.batch-size", c.BatchSize, "maximum number of events delivered in a single request")
+	f.BoolVar(&c.Enabled, "webhook.enabled", c.Enabled, "deliver the events to the webhook url")
+	f.StringVar(&c.FlushTimeout, "webhook.flush-timeout", c.FlushTimeout, "maximum duration for delivering the pending events at shutdown")
+	f.BoolVar(&c.PersistPending, "webhook.persist-pending", c.PersistPending, "save the events left undelivered at shutdown for delivery on the next start")
+	f.UintVar(&c.QueueSize, "webhook.queue-size", c.QueueSize, "maximum number of events buffered for delivery")
+	f.UintVar(&c.RetryAttempts, "webhook.retry-attempts", c.RetryAttempts, "number of attempts for delivering a batch of events")
+	f.StringVar(&c.RetryDelay, "webhook.retry-delay", c.RetryDelay, "base delay between delivery retries")
+	f.StringVar(&c.Secret, "webhook.secret", c.Secret, "key of the HMAC-SHA256 signature of the request bodies (empty to disable signing)")
+	f.StringVar(&c.SendInterval, "webhook.send-interval", c.SendInterval, "minimum interval between consecutive deliveries")
+	f.StringVar(&c.Timeout, "webhook.timeout", c.Timeout, "timeout of a single delivery request")
+	f.StringVar(&c.URL, "webhook.url", c.URL, "endpoint receiving the events (empty to disable)")
+}
+
+// DefaultWebhookConfig returns a WebhookConfig instance with default values.
+func DefaultWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		BatchSize:      50,
+		Enabled:        true,
+		FlushTimeout:   (5 * time.Second).String(),
+		PersistPending: true,
+		QueueSize:      1000,
+		RetryAttempts:  5,
+		RetryDelay:     (1 * time.Second).String(),
+		Secret:         "",
+		SendInterval:   (1 * time.Second).String(),
+		Timeout:        (10 * time.Second).String(),
+		URL:            "",
+	}
+}
@@ -0,0 +1,26 @@
+//go:build darwin || linux
+
+package core
+
+import (
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+)
+
+// serviceBinaries maps each service type to the executables it requires and an install hint.
+var serviceBinaries = map[types.ServiceType]struct {
+	names []string
+	hint  string
+}{
+	types.ServiceTypeOpenVPN: {
+		names: []string{"openvpn"},
+		hint:  "install the openvpn package (e.g., apt install openvpn)",
+	},
+	types.ServiceTypeV2Ray: {
+		names: []string{"v2ray"},
+		hint:  "install V2Ray from https://github.com/v2fly/v2ray-core/releases",
+	},
+	types.ServiceTypeWireGuard: {
+		names: []string{"wg", "wg-quick"},
+		hint:  "install the wireguard-tools package (e.g., apt install wireguard-tools)",
+	},
+}
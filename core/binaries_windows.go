@@ -0,0 +1,24 @@
+package core
+
+import (
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+)
+
+// serviceBinaries maps each service type to the executables it requires and an install hint.
+var serviceBinaries = map[types.ServiceType]struct {
+	names []string
+	hint  string
+}{
+	types.ServiceTypeOpenVPN: {
+		names: []string{`.\OpenVPN\openvpn.exe`},
+		hint:  `place the OpenVPN binaries in the "OpenVPN" directory next to the node executable`,
+	},
+	types.ServiceTypeV2Ray: {
+		names: []string{`.\V2Ray\v2ray.exe`},
+		hint:  `place the V2Ray binaries in the "V2Ray" directory next to the node executable`,
+	},
+	types.ServiceTypeWireGuard: {
+		names: []string{`.\WireGuard\wireguard.exe`, `.\WireGuard\wg.exe`},
+		hint:  `place the WireGuard binaries in the "WireGuard" directory next to the node executable`,
+	},
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// maxRemoteBlocklistSize is the maximum size in bytes of a remote blocklist response.
+const maxRemoteBlocklistSize = 1 << 22
+
+// Blocklist holds the account addresses whose handshakes are refused, merged from the local list and the list
+// fetched from a remote source shared by a trust group.
+type Blocklist struct {
+	client    *http.Client
+	publicKey ed25519.PublicKey
+	url       string
+
+	mu     sync.RWMutex
+	local  map[string]bool
+	remote map[string]bool
+}
+
+// NewBlocklist creates a Blocklist with the given local account addresses.
+func NewBlocklist(accounts []string) *Blocklist {
+	local := make(map[string]bool, len(accounts))
+	for _, addr := range accounts {
+		local[addr] = true
+	}
+
+	return &Blocklist{
+		client: &http.Client{},
+		local:  local,
+		remote: make(map[string]bool),
+	}
+}
+
+// WithSource sets the URL serving the remote blocklist and the key verifying its signature, and returns the
+// updated Blocklist.
+func (b *Blocklist) WithSource(url string, publicKey ed25519.PublicKey) *Blocklist {
+	b.publicKey = publicKey
+	b.url = url
+
+	return b
+}
+
+// Contains returns whether the account address is blocked locally or by the remote blocklist.
+func (b *Blocklist) Contains(addr string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.local[addr] || b.remote[addr]
+}
+
+// fetch retrieves the remote blocklist and verifies the Ed25519 signature of the response body carried in the
+// X-Signature header.
+func (b *Blocklist) fetch(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating blocklist request: %w", err)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending blocklist request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected blocklist response status %d", resp.StatusCode)
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteBlocklistSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading blocklist response: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(resp.Header.Get("X-Signature"))
+	if err != nil {
+		return nil, fmt.Errorf("decoding blocklist signature: %w", err)
+	}
+
+	if !ed25519.Verify(b.publicKey, buf, sig) {
+		return nil, errors.New("invalid blocklist signature")
+	}
+
+	var accounts []string
+	if err := json.Unmarshal(buf, &accounts); err != nil {
+		return nil, fmt.Errorf("decoding blocklist: %w", err)
+	}
+
+	for _, addr := range accounts {
+		if _, _, err := bech32.DecodeAndConvert(addr); err != nil {
+			return nil, fmt.Errorf("parsing blocklist account %q: %w", addr, err)
+		}
+	}
+
+	return accounts, nil
+}
+
+// Refresh fetches and verifies the remote blocklist, then replaces the remote account addresses atomically.
+// The previous remote addresses are kept if the list cannot be fetched or verified.
+func (b *Blocklist) Refresh(ctx context.Context) (int, error) {
+	accounts, err := b.fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	remote := make(map[string]bool, len(accounts))
+	for _, addr := range accounts {
+		remote[addr] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remote = remote
+
+	return len(remote), nil
+}
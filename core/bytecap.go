@@ -0,0 +1,45 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+)
+
+// ErrMonthlyByteCapReached is returned when the bytes served in the current calendar month reached the monthly byte cap.
+var ErrMonthlyByteCapReached = errors.New("monthly byte cap reached")
+
+// MonthlyBytes returns the total bytes served in the calendar month of the time, as recorded by the usage sync
+// with the database. Every byte of a session leaves the node once, towards either the client or the internet, so
+// the total counts against an egress budget. The bytes of deleted sessions and of sessions started in an earlier
+// month are counted in the month they were served.
+func (c *Context) MonthlyBytes(t time.Time) (math.Int, error) {
+	total, err := c.SessionStore().SumUsageBytes(t)
+	if err != nil {
+		return math.ZeroInt(), fmt.Errorf("summing usage bytes from database: %w", err)
+	}
+
+	return math.NewInt(total), nil
+}
+
+// CheckMonthlyByteCap returns an error wrapping ErrMonthlyByteCapReached if the bytes served in the calendar month of
+// the time reached the monthly byte cap. It never fails when the cap is disabled.
+func (c *Context) CheckMonthlyByteCap(t time.Time) error {
+	byteCap := c.MonthlyByteCap()
+	if byteCap == 0 {
+		return nil
+	}
+
+	total, err := c.MonthlyBytes(t)
+	if err != nil {
+		return err
+	}
+
+	if total.GTE(math.NewIntFromUint64(byteCap)) {
+		return fmt.Errorf("%s bytes served of %d: %w", total, byteCap, ErrMonthlyByteCapReached)
+	}
+
+	return nil
+}
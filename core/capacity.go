@@ -0,0 +1,103 @@
+package core
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+	"github.com/sentinel-official/sentinel-go-sdk/openvpn"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+	"github.com/sentinel-official/sentinel-go-sdk/wireguard"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+)
+
+// maxAddrPoolHostBits bounds the host bits counted for an address pool, far above any allowed maximum peers.
+const maxAddrPoolHostBits = 16
+
+// addrPoolCapacity returns the number of addresses an address pool of the CIDR can allocate to peers.
+// The network and server addresses, and the broadcast address for IPv4, are reserved.
+func addrPoolCapacity(cidr string) (uint, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return 0, fmt.Errorf("parsing CIDR %q: %w", cidr, err)
+	}
+
+	hostBits := min(prefix.Addr().BitLen()-prefix.Bits(), maxAddrPoolHostBits)
+	size := uint(1) << hostBits
+
+	reserved := uint(1)
+	if prefix.Addr() != prefix.Masked().Addr() {
+		reserved++
+	}
+
+	if prefix.Addr().Is4() {
+		reserved++
+	}
+
+	if size <= reserved {
+		return 0, fmt.Errorf("CIDR %q has no addresses for peers", cidr)
+	}
+
+	return size - reserved, nil
+}
+
+// ServiceCapacity returns the number of peers the address pools of the service can allocate.
+// Services that allocate no addresses to peers return zero for an unlimited capacity.
+func ServiceCapacity(cfg *config.Config) (uint, error) {
+	var cidrs []string
+
+	switch cfg.Node.GetServiceType() {
+	case types.ServiceTypeOpenVPN:
+		v := cfg.Services[types.ServiceTypeOpenVPN].(*openvpn.ServerConfig)
+		cidrs = []string{v.IPv4Addr, v.IPv6Addr}
+	case types.ServiceTypeWireGuard:
+		v := cfg.Services[types.ServiceTypeWireGuard].(*wireguard.ServerConfig)
+		cidrs = []string{v.IPv4Addr, v.IPv6Addr}
+	default:
+		return 0, nil
+	}
+
+	// Each peer acquires an address from every pool, so the smallest pool bounds the capacity.
+	var capacity uint
+
+	for _, cidr := range cidrs {
+		if cidr == "" {
+			continue
+		}
+
+		v, err := addrPoolCapacity(cidr)
+		if err != nil {
+			return 0, err
+		}
+
+		if capacity == 0 || v < capacity {
+			capacity = v
+		}
+	}
+
+	return capacity, nil
+}
+
+// CheckMaxPeers returns the maximum peers that fit in the address capacity of the service, along with the capacity.
+// A maximum peers exceeding the capacity is an error, or is clamped to the capacity with a warning if configured.
+func CheckMaxPeers(cfg *config.Config) (maxPeers, capacity uint, err error) {
+	maxPeers = cfg.QoS.GetMaxPeers()
+
+	capacity, err = ServiceCapacity(cfg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("getting service capacity: %w", err)
+	}
+
+	if capacity == 0 || maxPeers <= capacity {
+		return maxPeers, capacity, nil
+	}
+
+	if cfg.QoS.GetMaxPeersOverflow() != "clamp" {
+		return 0, 0, fmt.Errorf("max_peers %d exceeds the %d addresses the service can allocate to peers", maxPeers, capacity)
+	}
+
+	log.Warn("Clamping max peers to service capacity", "max_peers", maxPeers, "capacity", capacity)
+
+	return capacity, capacity, nil
+}
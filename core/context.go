@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"cosmossdk.io/math"
 	cosmossdk "github.com/cosmos/cosmos-sdk/types"
@@ -17,47 +19,123 @@ import (
 	sentinelhub "github.com/sentinel-official/sentinelhub/v12/types"
 	"github.com/sentinel-official/sentinelhub/v12/types/v1"
 	"gorm.io/gorm"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/database"
+	"github.com/sentinel-official/sentinel-dvpnx/events"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
 )
 
 // Context defines the application context, holding configurations and shared components.
 type Context struct {
-	accAddr        cosmossdk.AccAddress
-	apiAddrs       []string
-	apiListenAddr  string
-	client         *core.Client
-	database       *gorm.DB
-	dlSpeed        math.Int
-	geoIPClient    geoip.Client
-	gigabytePrices v1.Prices
-	homeDir        string
-	hourlyPrices   v1.Prices
-	input          io.Reader
-	location       *geoip.Location
-	maxPeers       uint
-	moniker        string
-	oracleClient   oracle.Client
-	remoteAddrs    []string
-	rpcAddrs       []string
-	service        sentinelsdk.ServerService
-	ulSpeed        math.Int
-
-	sealed bool
-
-	fm  sync.RWMutex
-	txm sync.Mutex
+	accAddr                cosmossdk.AccAddress
+	adminToken             string
+	apiAddrs               []string
+	apiHandshakeListenAddr string
+	apiListenAddr          string
+	apiTLSEnable           bool
+	blocklist              *Blocklist
+	capSign                bool
+	capTTL                 time.Duration
+	client                 *core.Client
+	database               *gorm.DB
+	dbRetryAttempts        uint
+	dbRetryDelay           time.Duration
+	dlSpeed                math.Int
+	drainDelay             time.Duration
+	drainOnShutdown        bool
+	drainSize              uint
+	drainStrategy          string
+	drainSync              bool
+	drainTimeout           time.Duration
+	eventLog               *events.Writer
+	exposeStartup          bool
+	fallbackClients        []*core.Client
+	gasPriceClients        []*core.Client
+	geoIPClient            geoip.Client
+	gigabytePrices         v1.Prices
+	homeDir                string
+	hourlyPrices           v1.Prices
+	humanBytes             bool
+	idleRate               uint64
+	idleTimeout            time.Duration
+	inactive               bool
+	infoCacheTTL           time.Duration
+	infoVersion            uint64
+	input                  io.Reader
+	location               *geoip.Location
+	maxPeers               uint
+	maxSessions            uint
+	minDeposit             cosmossdk.Coins
+	moniker                string
+	monthlyByteCap         uint64
+	oracleClient           oracle.Client
+	peerBandwidth          math.Int
+	peerCapacity           uint
+	peerReuse              bool
+	persistState           bool
+	ping                   bool
+	plans                  []*config.PlanConfig
+	pricingSchedule        []*config.PricingWindowConfig
+	protocols              []string
+	recordLatency          bool
+	remoteAddrs            []string
+	remoteProbe            string
+	replayGuard            *ReplayGuard
+	removePeers            bool
+	requireAlloc           bool
+	rpcAddrs               []string
+	rpcBackoff             *RPCBackoff
+	rpcFallback            *RPCFallback
+	service                sentinelsdk.ServerService
+	sessionBatch           uint
+	sessionStore           database.SessionStore
+	sessionWorkers         uint
+	speedtestStore         database.SpeedtestStore
+	staleSessions          string
+	startupJitterMax       time.Duration
+	startupTimings         *StartupTimings
+	staticDLSpeed          math.Int
+	staticULSpeed          math.Int
+	tunnelKeep             uint
+	tunnelMTU              uint
+	txFailureLog           bool
+	ulSpeed                math.Int
+	usageAction            string
+	usageFactor            float64
+	webhook                *webhook.Dispatcher
+	workerSchedule         *WorkerSchedule
+
+	sealed   bool
+	sealedAt time.Time
+
+	fm     sync.RWMutex
+	statem sync.Mutex // Serializes the writes of the state file.
+	txm    sync.Mutex
+
+	gasPriceLevel int // Number of gas price adjustments applied to the next transaction, guarded by txm.
 }
 
 // NewContext creates a new Context instance with default values.
 func NewContext() *Context {
 	return &Context{
-		dlSpeed: math.ZeroInt(),
-		ulSpeed: math.ZeroInt(),
+		dlSpeed:        math.ZeroInt(),
+		peerBandwidth:  math.ZeroInt(),
+		startupTimings: NewStartupTimings(),
+		staticDLSpeed:  math.ZeroInt(),
+		staticULSpeed:  math.ZeroInt(),
+		ulSpeed:        math.ZeroInt(),
 	}
 }
 
 // Seal marks the context as sealed, preventing further modifications.
+// The time of sealing is recorded as the start of the node's uptime.
 func (c *Context) Seal() *Context {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
 	c.sealed = true
+	c.sealedAt = time.Now()
 
 	return c
 }
@@ -70,6 +148,14 @@ func (c *Context) AccAddr() cosmossdk.AccAddress {
 	return c.accAddr.Bytes()
 }
 
+// AdminToken returns the bearer token required by the admin API.
+func (c *Context) AdminToken() string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.adminToken
+}
+
 // APIAddrs returns the api addresses set in the context.
 func (c *Context) APIAddrs() []string {
 	c.fm.RLock()
@@ -78,6 +164,39 @@ func (c *Context) APIAddrs() []string {
 	return c.apiAddrs
 }
 
+// APIAddrsFor returns the api addresses derived from the remote addresses, keeping the port of the current api
+// addresses, without changing the context.
+func (c *Context) APIAddrsFor(remoteAddrs []string) []string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.apiAddrsFor(remoteAddrs)
+}
+
+// apiAddrsFor returns the api addresses derived from the remote addresses; the caller must hold the lock.
+func (c *Context) apiAddrsFor(remoteAddrs []string) []string {
+	port := ""
+	if len(c.apiAddrs) > 0 {
+		_, port, _ = net.SplitHostPort(c.apiAddrs[0])
+	}
+
+	apiAddrs := make([]string, len(remoteAddrs))
+	for i, addr := range remoteAddrs {
+		apiAddrs[i] = net.JoinHostPort(addr, port)
+	}
+
+	return apiAddrs
+}
+
+// APIHandshakeListenAddr returns the listen address of the handshake route, or empty if it is served on the API
+// listen address.
+func (c *Context) APIHandshakeListenAddr() string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.apiHandshakeListenAddr
+}
+
 // APIListenAddr returns the listen address of the node API.
 func (c *Context) APIListenAddr() string {
 	c.fm.RLock()
@@ -86,6 +205,30 @@ func (c *Context) APIListenAddr() string {
 	return c.apiListenAddr
 }
 
+// APITLSEnable returns whether the API server serves TLS rather than plain HTTP behind a reverse proxy.
+func (c *Context) APITLSEnable() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.apiTLSEnable
+}
+
+// Blocklist returns the account addresses whose handshakes are refused.
+func (c *Context) Blocklist() *Blocklist {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.blocklist
+}
+
+// Capabilities returns whether the capabilities document is signed and the duration for which it is cached.
+func (c *Context) Capabilities() (sign bool, cacheTTL time.Duration) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.capSign, c.capTTL
+}
+
 // Client returns the client instance set in the context.
 func (c *Context) Client() *core.Client {
 	c.fm.RLock()
@@ -104,6 +247,14 @@ func (c *Context) Database() *gorm.DB {
 	return c.database
 }
 
+// DatabaseBusyRetry returns the attempts and base delay for retrying database writes failing with a busy error.
+func (c *Context) DatabaseBusyRetry() (attempts uint, delay time.Duration) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.dbRetryAttempts, c.dbRetryDelay
+}
+
 // DatabaseFile returns the database path of the node.
 func (c *Context) DatabaseFile() string {
 	c.fm.RLock()
@@ -112,6 +263,78 @@ func (c *Context) DatabaseFile() string {
 	return filepath.Join(c.HomeDir(), "data.db")
 }
 
+// Drain returns the strategy, batch size, and batch delay for draining peers at shutdown.
+func (c *Context) Drain() (strategy string, batchSize uint, batchDelay time.Duration) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.drainStrategy, c.drainSize, c.drainDelay
+}
+
+// DrainOnShutdown returns whether the connected peers are drained before the node is stopped.
+func (c *Context) DrainOnShutdown() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.drainOnShutdown
+}
+
+// DrainSyncUsage returns whether the final session usage is synced with the blockchain before peers are removed.
+func (c *Context) DrainSyncUsage() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.drainSync
+}
+
+// DrainTimeout returns the maximum duration of the drain at shutdown, zero for no limit.
+func (c *Context) DrainTimeout() time.Duration {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.drainTimeout
+}
+
+// EventLog returns the session lifecycle event log writer set in the context, or nil if the event log is disabled.
+func (c *Context) EventLog() *events.Writer {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.eventLog
+}
+
+// ExposeStartupTimings returns whether the durations of the startup phases are included in the info response.
+func (c *Context) ExposeStartupTimings() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.exposeStartup
+}
+
+// FallbackClients returns the clients broadcasting with the fallback gas prices, in the order they are tried.
+func (c *Context) FallbackClients() []*core.Client {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	for _, v := range c.fallbackClients {
+		v.SetRPCAddr(c.RPCAddr())
+	}
+
+	return c.fallbackClients
+}
+
+// GasPriceClients returns the clients broadcasting with the adjusted gas prices, in the order they are tried.
+func (c *Context) GasPriceClients() []*core.Client {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	for _, v := range c.gasPriceClients {
+		v.SetRPCAddr(c.RPCAddr())
+	}
+
+	return c.gasPriceClients
+}
+
 // GeoIPClient returns the GeoIP client set in the context.
 func (c *Context) GeoIPClient() geoip.Client {
 	c.fm.RLock()
@@ -144,6 +367,39 @@ func (c *Context) HourlyPrices() v1.Prices {
 	return c.hourlyPrices
 }
 
+// HumanReadableBytes returns whether byte counts are also displayed in human-readable units.
+func (c *Context) HumanReadableBytes() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.humanBytes
+}
+
+// Inactive returns whether the node is inactive on the blockchain.
+func (c *Context) Inactive() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.inactive
+}
+
+// InfoCacheTTL returns the duration for which the info response is cached, where zero disables caching.
+func (c *Context) InfoCacheTTL() time.Duration {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.infoCacheTTL
+}
+
+// InfoVersion returns a counter that is incremented whenever the location or the speedtest results change, so that
+// cached node information can be invalidated.
+func (c *Context) InfoVersion() uint64 {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.infoVersion
+}
+
 // Input returns the keyring input set in the context.
 func (c *Context) Input() io.Reader {
 	c.fm.RLock()
@@ -168,6 +424,22 @@ func (c *Context) MaxPeers() uint {
 	return c.maxPeers
 }
 
+// MaxSessionsPerAccount returns the maximum concurrent sessions per account, where zero means unlimited.
+func (c *Context) MaxSessionsPerAccount() uint {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.maxSessions
+}
+
+// MinDeposit returns the minimum session deposit accepted at handshake for each denom.
+func (c *Context) MinDeposit() cosmossdk.Coins {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.minDeposit
+}
+
 // Moniker returns the name or identifier for the node.
 func (c *Context) Moniker() string {
 	c.fm.RLock()
@@ -176,6 +448,15 @@ func (c *Context) Moniker() string {
 	return c.moniker
 }
 
+// MonthlyByteCap returns the maximum number of bytes served by the sessions of a calendar month, where zero
+// disables the cap.
+func (c *Context) MonthlyByteCap() uint64 {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.monthlyByteCap
+}
+
 func (c *Context) NodeAddr() sentinelhub.NodeAddress {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
@@ -191,179 +472,639 @@ func (c *Context) OracleClient() oracle.Client {
 	return c.oracleClient
 }
 
-// RemoteAddrs returns the remote addresses set in the context.
-func (c *Context) RemoteAddrs() []string {
+// PeerBandwidth returns the per-peer upload bandwidth budget used to derive the maximum peers.
+// A zero value means the maximum peers is not derived automatically.
+func (c *Context) PeerBandwidth() math.Int {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
 
-	return c.remoteAddrs
+	return c.peerBandwidth
 }
 
-// RPCAddr returns the first RPC address from the list or an empty string if no addresses are available.
-func (c *Context) RPCAddr() string {
+// PeerCapacity returns the number of peers the address pools of the service can allocate, or zero if unlimited.
+func (c *Context) PeerCapacity() uint {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
 
-	addrs := c.RPCAddrs()
-	if len(addrs) == 0 {
-		panic(errors.New("rpc_addrs is empty"))
-	}
-
-	return addrs[0]
+	return c.peerCapacity
 }
 
-// RPCAddrs returns the RPC addresses used for queries in the context.
-func (c *Context) RPCAddrs() []string {
+// PeerRequestReuse returns whether the peer request of a removed peer is released for reuse.
+func (c *Context) PeerRequestReuse() bool {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
 
-	return c.rpcAddrs
+	return c.peerReuse
 }
 
-// Service returns the server service instance set in the context.
-func (c *Context) Service() sentinelsdk.ServerService {
+// PersistState returns whether the location and speedtest results are persisted across restarts.
+func (c *Context) PersistState() bool {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
 
-	return c.service
+	return c.persistState
 }
 
-// SpeedtestResults returns the download and upload speeds set in the context.
-func (c *Context) SpeedtestResults() (dlSpeed, ulSpeed math.Int) {
+// PingEnabled returns whether the ping endpoint for client latency measurement is enabled.
+func (c *Context) PingEnabled() bool {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
 
-	return c.dlSpeed, c.ulSpeed
+	return c.ping
 }
 
-// TLSCertFile returns the TLS certificate path of the node API server.
-func (c *Context) TLSCertFile() string {
+// Plans returns the pricing tiers advertised to clients.
+func (c *Context) Plans() []*config.PlanConfig {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
 
-	return filepath.Join(c.HomeDir(), "tls.crt")
+	return c.plans
 }
 
-// TLSKeyFile returns the TLS key path of the node API server.
-func (c *Context) TLSKeyFile() string {
+// PricingSchedule returns the daily windows of fixed prices applied on-chain.
+func (c *Context) PricingSchedule() []*config.PricingWindowConfig {
 	c.fm.RLock()
 	defer c.fm.RUnlock()
 
-	return filepath.Join(c.HomeDir(), "tls.key")
+	return c.pricingSchedule
 }
 
-// SanitizedGigabytePrices returns gigabyte prices filtered to include only valid denominations.
-func (c *Context) SanitizedGigabytePrices(ctx context.Context) (v1.Prices, error) {
-	params, err := c.Client().NodeParams(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("getting node params: %w", err)
-	}
-
-	prices := c.sanitizePrices(
-		c.GigabytePrices(),
-		params.GetMinGigabytePrices(),
-	)
+// Protocols returns the protocols supported by the service.
+func (c *Context) Protocols() []string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	return prices, nil
+	return c.protocols
 }
 
-// SanitizedHourlyPrices returns hourly prices filtered to include only valid denominations.
-func (c *Context) SanitizedHourlyPrices(ctx context.Context) (v1.Prices, error) {
-	params, err := c.Client().NodeParams(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("getting node params: %w", err)
+// PublicLocation returns a copy of the geolocation data set in the context without the IP address, for responses
+// served to clients. The zero location is returned while the location is not resolved yet.
+func (c *Context) PublicLocation() *geoip.Location {
+	loc := c.Location()
+	if loc == nil {
+		return &geoip.Location{}
 	}
 
-	prices := c.sanitizePrices(
-		c.HourlyPrices(),
-		params.GetMinHourlyPrices(),
-	)
-
-	return prices, nil
+	return &geoip.Location{
+		City:        loc.City,
+		Country:     loc.Country,
+		CountryCode: loc.CountryCode,
+		Latitude:    loc.Latitude,
+		Longitude:   loc.Longitude,
+	}
 }
 
-// SetLocation sets the geolocation data in the context.
-func (c *Context) SetLocation(location *geoip.Location) {
-	c.fm.Lock()
-	defer c.fm.Unlock()
+// RecordClientLatency returns whether client-reported round-trip times are aggregated.
+func (c *Context) RecordClientLatency() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	c.location = location
+	return c.recordLatency
 }
 
-// SetRPCAddrs sets the RPC addresses in the context and allows for thread-safe updates.
-func (c *Context) SetRPCAddrs(addrs []string) {
-	c.fm.Lock()
-	defer c.fm.Unlock()
+// RemoteAddrs returns the remote addresses set in the context.
+func (c *Context) RemoteAddrs() []string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	c.rpcAddrs = addrs
+	return c.remoteAddrs
 }
 
-// SetSpeedtestResults sets the download and upload speeds in the context.
-func (c *Context) SetSpeedtestResults(dlSpeed, ulSpeed math.Int) {
-	c.fm.Lock()
-	defer c.fm.Unlock()
+// RemoteAddrsProbe returns the handling of remote addresses found unreachable before registering the node.
+func (c *Context) RemoteAddrsProbe() string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	c.dlSpeed = dlSpeed
-	c.ulSpeed = ulSpeed
+	return c.remoteProbe
 }
 
-// WithAccAddr sets the transaction sender address in the context and returns the updated context.
-func (c *Context) WithAccAddr(addr cosmossdk.AccAddress) *Context {
-	c.checkSealed()
-	c.accAddr = addr
+// RemovePeersIfInactive returns whether existing peers are removed while the node is inactive.
+func (c *Context) RemovePeersIfInactive() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	return c
+	return c.removePeers
 }
 
-// WithAPIAddrs sets the api addresses in the context and returns the updated context.
-func (c *Context) WithAPIAddrs(addrs []string) *Context {
-	c.checkSealed()
-	c.apiAddrs = addrs
+// ReplayGuard returns the guard against replayed handshake requests, or nil if replay protection is disabled.
+func (c *Context) ReplayGuard() *ReplayGuard {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	return c
+	return c.replayGuard
 }
 
-// WithAPIListenAddr sets the listen address for the node API and returns the updated context.
-func (c *Context) WithAPIListenAddr(addr string) *Context {
-	c.checkSealed()
-	c.apiListenAddr = addr
+// RequireAllocation returns whether handshakes are rejected for sessions with no remaining allocation.
+func (c *Context) RequireAllocation() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	return c
+	return c.requireAlloc
 }
 
-// WithClient sets the core client in the context and returns the updated context.
-func (c *Context) WithClient(client *core.Client) *Context {
-	c.checkSealed()
-	c.client = client
+// RPCAddr returns the first RPC address from the list that is not cooling down after failing to connect, or the
+// first RPC address if all of them are. It panics if no addresses are available.
+func (c *Context) RPCAddr() string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	return c
-}
+	addrs := c.RPCAddrs()
+	if len(addrs) == 0 {
+		panic(errors.New("rpc_addrs is empty"))
+	}
 
-// WithDatabase sets the database connection in the context and returns the updated context.
-func (c *Context) WithDatabase(database *gorm.DB) *Context {
-	c.checkSealed()
-	c.database = database
+	if f := c.RPCFallback(); f != nil {
+		for _, addr := range addrs {
+			if !f.IsCoolingDown(addr) {
+				return addr
+			}
+		}
+	}
 
-	return c
+	return addrs[0]
 }
 
-// WithGeoIPClient sets the GeoIP client in the context and returns the updated context.
-func (c *Context) WithGeoIPClient(client geoip.Client) *Context {
-	c.checkSealed()
-	c.geoIPClient = client
+// RPCAddrs returns the RPC addresses used for queries in the context.
+func (c *Context) RPCAddrs() []string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	return c
+	return c.rpcAddrs
 }
 
-// WithGigabytePrices sets the gigabyte prices for nodes and returns the updated context.
-func (c *Context) WithGigabytePrices(prices v1.Prices) *Context {
-	c.checkSealed()
-	c.gigabytePrices = prices
+// RPCBackoff returns the backoff of rate-limited RPC endpoints, or nil if the backoff is disabled.
+func (c *Context) RPCBackoff() *RPCBackoff {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
 
-	return c
+	return c.rpcBackoff
 }
 
-// WithHomeDir sets the home directory in the context and returns the updated context.
+// RPCFallback returns the fallback across the RPC addresses, or nil if the fallback is not set up.
+func (c *Context) RPCFallback() *RPCFallback {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.rpcFallback
+}
+
+// Service returns the server service instance set in the context.
+func (c *Context) Service() sentinelsdk.ServerService {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.service
+}
+
+// SessionIdle returns the byte rate threshold in bytes per second below which a session is idle,
+// and the idle duration after which its peer is removed. A zero threshold disables idle eviction.
+func (c *Context) SessionIdle() (threshold uint64, timeout time.Duration) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.idleRate, c.idleTimeout
+}
+
+// SessionStore returns the session storage backend set in the context.
+func (c *Context) SessionStore() database.SessionStore {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.sessionStore
+}
+
+// SessionUpdateBatchSize returns the maximum number of session update messages broadcast in a single transaction.
+func (c *Context) SessionUpdateBatchSize() uint {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.sessionBatch
+}
+
+// SessionWorkerConcurrency returns the maximum number of sessions processed concurrently by the session workers.
+func (c *Context) SessionWorkerConcurrency() uint {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.sessionWorkers
+}
+
+// SpeedtestResults returns the download and upload speeds set in the context.
+func (c *Context) SpeedtestResults() (dlSpeed, ulSpeed math.Int) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.dlSpeed, c.ulSpeed
+}
+
+// SpeedtestStore returns the speed test storage backend set in the context.
+func (c *Context) SpeedtestStore() database.SpeedtestStore {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.speedtestStore
+}
+
+// StaleSessions returns the handling of database sessions created against a previous node address.
+func (c *Context) StaleSessions() string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.staleSessions
+}
+
+// StartupJitterMax returns the maximum random delay before the node registers at startup.
+func (c *Context) StartupJitterMax() time.Duration {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.startupJitterMax
+}
+
+// StartupTimings returns the durations of the startup phases recorded in the context.
+func (c *Context) StartupTimings() *StartupTimings {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.startupTimings
+}
+
+// StaticSpeedtestResults returns the configured download and upload speeds used when no measurement is available.
+func (c *Context) StaticSpeedtestResults() (dlSpeed, ulSpeed math.Int) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.staticDLSpeed, c.staticULSpeed
+}
+
+// TLSCertFile returns the TLS certificate path of the node API server.
+func (c *Context) TLSCertFile() string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return filepath.Join(c.HomeDir(), "tls.crt")
+}
+
+// TLSKeyFile returns the TLS key path of the node API server.
+func (c *Context) TLSKeyFile() string {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return filepath.Join(c.HomeDir(), "tls.key")
+}
+
+// Tunnel returns the MTU and keepalive surfaced to clients in the handshake response.
+func (c *Context) Tunnel() (mtu, keepalive uint) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.tunnelMTU, c.tunnelKeep
+}
+
+// TxFailureLog returns whether the full raw log of failed transactions is logged at error level.
+func (c *Context) TxFailureLog() bool {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.txFailureLog
+}
+
+// Uptime returns the time elapsed since the context was sealed, or zero if it is not sealed yet.
+func (c *Context) Uptime() time.Duration {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	if c.sealedAt.IsZero() {
+		return 0
+	}
+
+	return time.Since(c.sealedAt)
+}
+
+// UsageAnomaly returns the factor of the measured speeds above which the usage of a session is implausible, where
+// zero disables the check, and the handling of sessions reporting implausible usage.
+func (c *Context) UsageAnomaly() (multiplier float64, action string) {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.usageFactor, c.usageAction
+}
+
+// Webhook returns the webhook dispatcher set in the context, or nil if webhook delivery is disabled.
+func (c *Context) Webhook() *webhook.Dispatcher {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.webhook
+}
+
+// WorkerSchedule returns the worker schedule set in the context, or nil if the schedule is not exposed.
+func (c *Context) WorkerSchedule() *WorkerSchedule {
+	c.fm.RLock()
+	defer c.fm.RUnlock()
+
+	return c.workerSchedule
+}
+
+// EmitEvent queues an event for webhook delivery if webhook delivery is enabled.
+func (c *Context) EmitEvent(eventType string, data interface{}) {
+	if d := c.Webhook(); d != nil {
+		d.Enqueue(webhook.NewEvent(eventType, data))
+	}
+}
+
+// SanitizedGigabytePrices returns gigabyte prices filtered to include only valid denominations. It fails if prices
+// are configured but none of them is in a denomination accepted by the node params.
+func (c *Context) SanitizedGigabytePrices(ctx context.Context) (v1.Prices, error) {
+	params, err := c.Client().NodeParams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting node params: %w", err)
+	}
+
+	configured := c.GigabytePrices()
+	prices := c.sanitizePrices(
+		configured,
+		params.GetMinGigabytePrices(),
+	)
+
+	if err := errNoAcceptedDenoms("gigabyte_prices", configured, prices); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// SanitizedHourlyPrices returns hourly prices filtered to include only valid denominations. It fails if prices
+// are configured but none of them is in a denomination accepted by the node params.
+func (c *Context) SanitizedHourlyPrices(ctx context.Context) (v1.Prices, error) {
+	params, err := c.Client().NodeParams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting node params: %w", err)
+	}
+
+	configured := c.HourlyPrices()
+	prices := c.sanitizePrices(
+		configured,
+		params.GetMinHourlyPrices(),
+	)
+
+	if err := errNoAcceptedDenoms("hourly_prices", configured, prices); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// SetGigabytePrices sets the gigabyte prices for nodes in the context.
+func (c *Context) SetGigabytePrices(prices v1.Prices) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.gigabytePrices = prices
+}
+
+// SetHourlyPrices sets the hourly prices for nodes in the context.
+func (c *Context) SetHourlyPrices(prices v1.Prices) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.hourlyPrices = prices
+}
+
+// SetInactive sets whether the node is inactive on the blockchain in the context.
+func (c *Context) SetInactive(inactive bool) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.inactive = inactive
+}
+
+// SetLocation sets the geolocation data in the context.
+func (c *Context) SetLocation(location *geoip.Location) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.location = location
+	c.infoVersion++
+}
+
+// SetMaxPeers sets the maximum peers for the service in the context.
+func (c *Context) SetMaxPeers(maxPeers uint) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.maxPeers = maxPeers
+}
+
+// SetRemoteAddrs sets the remote addresses in the context and derives the api addresses from them,
+// keeping the port of the current api addresses.
+func (c *Context) SetRemoteAddrs(addrs []string) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.apiAddrs = c.apiAddrsFor(addrs)
+	c.remoteAddrs = addrs
+}
+
+// SetRPCAddrs sets the RPC addresses in the context and allows for thread-safe updates.
+func (c *Context) SetRPCAddrs(addrs []string) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.rpcAddrs = addrs
+}
+
+// SetService replaces the server service in the context, such as after restarting a stopped service.
+func (c *Context) SetService(service sentinelsdk.ServerService) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.service = service
+}
+
+// SetSpeedtestResults sets the download and upload speeds in the context.
+func (c *Context) SetSpeedtestResults(dlSpeed, ulSpeed math.Int) {
+	c.fm.Lock()
+	defer c.fm.Unlock()
+
+	c.dlSpeed = dlSpeed
+	c.ulSpeed = ulSpeed
+	c.infoVersion++
+}
+
+// WithAccAddr sets the transaction sender address in the context and returns the updated context.
+func (c *Context) WithAccAddr(addr cosmossdk.AccAddress) *Context {
+	c.checkSealed()
+	c.accAddr = addr
+
+	return c
+}
+
+// WithAdminToken sets the bearer token required by the admin API and returns the updated context.
+func (c *Context) WithAdminToken(token string) *Context {
+	c.checkSealed()
+	c.adminToken = token
+
+	return c
+}
+
+// WithAPIAddrs sets the api addresses in the context and returns the updated context.
+func (c *Context) WithAPIAddrs(addrs []string) *Context {
+	c.checkSealed()
+	c.apiAddrs = addrs
+
+	return c
+}
+
+// WithAPIHandshakeListenAddr sets the listen address of the handshake route and returns the updated context.
+func (c *Context) WithAPIHandshakeListenAddr(addr string) *Context {
+	c.checkSealed()
+	c.apiHandshakeListenAddr = addr
+
+	return c
+}
+
+// WithAPIListenAddr sets the listen address for the node API and returns the updated context.
+func (c *Context) WithAPIListenAddr(addr string) *Context {
+	c.checkSealed()
+	c.apiListenAddr = addr
+
+	return c
+}
+
+// WithAPITLSEnable sets whether the API server serves TLS and returns the updated context.
+func (c *Context) WithAPITLSEnable(enable bool) *Context {
+	c.checkSealed()
+	c.apiTLSEnable = enable
+
+	return c
+}
+
+// WithBlocklist sets the account addresses whose handshakes are refused and returns the updated context.
+func (c *Context) WithBlocklist(blocklist *Blocklist) *Context {
+	c.checkSealed()
+	c.blocklist = blocklist
+
+	return c
+}
+
+// WithCapabilities sets whether the capabilities document is signed and the duration for which it is cached, and returns the updated context.
+func (c *Context) WithCapabilities(sign bool, cacheTTL time.Duration) *Context {
+	c.checkSealed()
+	c.capSign = sign
+	c.capTTL = cacheTTL
+
+	return c
+}
+
+// WithClient sets the core client in the context and returns the updated context.
+func (c *Context) WithClient(client *core.Client) *Context {
+	c.checkSealed()
+	c.client = client
+
+	return c
+}
+
+// WithDatabase sets the database connection in the context and returns the updated context.
+func (c *Context) WithDatabase(database *gorm.DB) *Context {
+	c.checkSealed()
+	c.database = database
+
+	return c
+}
+
+// WithDatabaseBusyRetry sets the attempts and base delay for retrying busy database writes and returns the updated context.
+func (c *Context) WithDatabaseBusyRetry(attempts uint, delay time.Duration) *Context {
+	c.checkSealed()
+	c.dbRetryAttempts = attempts
+	c.dbRetryDelay = delay
+
+	return c
+}
+
+// WithDrain sets the strategy, batch size, and batch delay for draining peers at shutdown and returns the updated context.
+func (c *Context) WithDrain(strategy string, batchSize uint, batchDelay time.Duration) *Context {
+	c.checkSealed()
+	c.drainStrategy = strategy
+	c.drainSize = batchSize
+	c.drainDelay = batchDelay
+
+	return c
+}
+
+// WithDrainOnShutdown sets whether the connected peers are drained before the node is stopped and returns the updated context.
+func (c *Context) WithDrainOnShutdown(drain bool) *Context {
+	c.checkSealed()
+	c.drainOnShutdown = drain
+
+	return c
+}
+
+// WithDrainSyncUsage sets whether the final session usage is synced with the blockchain before peers are removed and returns the updated context.
+func (c *Context) WithDrainSyncUsage(sync bool) *Context {
+	c.checkSealed()
+	c.drainSync = sync
+
+	return c
+}
+
+// WithDrainTimeout sets the maximum duration of the drain at shutdown and returns the updated context.
+func (c *Context) WithDrainTimeout(timeout time.Duration) *Context {
+	c.checkSealed()
+	c.drainTimeout = timeout
+
+	return c
+}
+
+// WithEventLog sets the session lifecycle event log writer in the context and returns the updated context.
+func (c *Context) WithEventLog(w *events.Writer) *Context {
+	c.checkSealed()
+	c.eventLog = w
+
+	return c
+}
+
+// WithExposeStartupTimings sets whether the durations of the startup phases are included in the info response and returns the updated context.
+func (c *Context) WithExposeStartupTimings(expose bool) *Context {
+	c.checkSealed()
+	c.exposeStartup = expose
+
+	return c
+}
+
+// WithFallbackClients sets the clients broadcasting with the fallback gas prices and returns the updated context.
+func (c *Context) WithFallbackClients(clients []*core.Client) *Context {
+	c.checkSealed()
+	c.fallbackClients = clients
+
+	return c
+}
+
+// WithGasPriceClients sets the clients broadcasting with the adjusted gas prices and returns the updated context.
+func (c *Context) WithGasPriceClients(clients []*core.Client) *Context {
+	c.checkSealed()
+	c.gasPriceClients = clients
+
+	return c
+}
+
+// WithGeoIPClient sets the GeoIP client in the context and returns the updated context.
+func (c *Context) WithGeoIPClient(client geoip.Client) *Context {
+	c.checkSealed()
+	c.geoIPClient = client
+
+	return c
+}
+
+// WithGigabytePrices sets the gigabyte prices for nodes and returns the updated context.
+func (c *Context) WithGigabytePrices(prices v1.Prices) *Context {
+	c.checkSealed()
+	c.gigabytePrices = prices
+
+	return c
+}
+
+// WithHomeDir sets the home directory in the context and returns the updated context.
 func (c *Context) WithHomeDir(dir string) *Context {
 	c.checkSealed()
 	c.homeDir = dir
@@ -379,6 +1120,22 @@ func (c *Context) WithHourlyPrices(prices v1.Prices) *Context {
 	return c
 }
 
+// WithHumanReadableBytes sets whether byte counts are also displayed in human-readable units and returns the updated context.
+func (c *Context) WithHumanReadableBytes(humanReadable bool) *Context {
+	c.checkSealed()
+	c.humanBytes = humanReadable
+
+	return c
+}
+
+// WithInfoCacheTTL sets the duration for which the info response is cached and returns the updated context.
+func (c *Context) WithInfoCacheTTL(ttl time.Duration) *Context {
+	c.checkSealed()
+	c.infoCacheTTL = ttl
+
+	return c
+}
+
 // WithInput sets the keyring input in the context and returns the updated context.
 func (c *Context) WithInput(input io.Reader) *Context {
 	c.checkSealed()
@@ -395,6 +1152,22 @@ func (c *Context) WithMaxPeers(maxPeers uint) *Context {
 	return c
 }
 
+// WithMaxSessionsPerAccount sets the maximum concurrent sessions per account and returns the updated context.
+func (c *Context) WithMaxSessionsPerAccount(maxSessions uint) *Context {
+	c.checkSealed()
+	c.maxSessions = maxSessions
+
+	return c
+}
+
+// WithMinDeposit sets the minimum session deposit accepted at handshake and returns the updated context.
+func (c *Context) WithMinDeposit(coins cosmossdk.Coins) *Context {
+	c.checkSealed()
+	c.minDeposit = coins
+
+	return c
+}
+
 // WithMoniker sets the name or identifier for the node and returns the updated context.
 func (c *Context) WithMoniker(moniker string) *Context {
 	c.checkSealed()
@@ -403,6 +1176,15 @@ func (c *Context) WithMoniker(moniker string) *Context {
 	return c
 }
 
+// WithMonthlyByteCap sets the maximum number of bytes served by the sessions of a calendar month and returns the
+// updated context.
+func (c *Context) WithMonthlyByteCap(bytes uint64) *Context {
+	c.checkSealed()
+	c.monthlyByteCap = bytes
+
+	return c
+}
+
 // WithOracleClient sets the oracle client in the context and returns the updated context.
 func (c *Context) WithOracleClient(client oracle.Client) *Context {
 	c.checkSealed()
@@ -411,6 +1193,71 @@ func (c *Context) WithOracleClient(client oracle.Client) *Context {
 	return c
 }
 
+// WithPeerBandwidth sets the per-peer upload bandwidth budget and returns the updated context.
+func (c *Context) WithPeerBandwidth(bandwidth math.Int) *Context {
+	c.checkSealed()
+	c.peerBandwidth = bandwidth
+
+	return c
+}
+
+// WithPeerCapacity sets the number of peers the address pools of the service can allocate and returns the updated context.
+func (c *Context) WithPeerCapacity(capacity uint) *Context {
+	c.checkSealed()
+	c.peerCapacity = capacity
+
+	return c
+}
+
+// WithPeerRequestReuse sets whether the peer request of a removed peer is released for reuse and returns the updated context.
+func (c *Context) WithPeerRequestReuse(reuse bool) *Context {
+	c.checkSealed()
+	c.peerReuse = reuse
+
+	return c
+}
+
+// WithPersistState sets whether the location and speedtest results are persisted across restarts and returns the updated context.
+func (c *Context) WithPersistState(persist bool) *Context {
+	c.checkSealed()
+	c.persistState = persist
+
+	return c
+}
+
+// WithPing sets whether the ping endpoint is enabled and client-reported round-trip times are aggregated, and returns the updated context.
+func (c *Context) WithPing(enable, recordLatency bool) *Context {
+	c.checkSealed()
+	c.ping = enable
+	c.recordLatency = recordLatency
+
+	return c
+}
+
+// WithPlans sets the pricing tiers advertised to clients and returns the updated context.
+func (c *Context) WithPlans(plans []*config.PlanConfig) *Context {
+	c.checkSealed()
+	c.plans = plans
+
+	return c
+}
+
+// WithPricingSchedule sets the daily windows of fixed prices applied on-chain and returns the updated context.
+func (c *Context) WithPricingSchedule(windows []*config.PricingWindowConfig) *Context {
+	c.checkSealed()
+	c.pricingSchedule = windows
+
+	return c
+}
+
+// WithProtocols sets the protocols supported by the service and returns the updated context.
+func (c *Context) WithProtocols(protocols []string) *Context {
+	c.checkSealed()
+	c.protocols = protocols
+
+	return c
+}
+
 // WithRemoteAddrs sets the remote addresses in the context and returns the updated context.
 func (c *Context) WithRemoteAddrs(addrs []string) *Context {
 	c.checkSealed()
@@ -419,6 +1266,38 @@ func (c *Context) WithRemoteAddrs(addrs []string) *Context {
 	return c
 }
 
+// WithRemoteAddrsProbe sets the handling of remote addresses found unreachable before registering the node and returns the updated context.
+func (c *Context) WithRemoteAddrsProbe(v string) *Context {
+	c.checkSealed()
+	c.remoteProbe = v
+
+	return c
+}
+
+// WithRemovePeersIfInactive sets whether existing peers are removed while the node is inactive and returns the updated context.
+func (c *Context) WithRemovePeersIfInactive(remove bool) *Context {
+	c.checkSealed()
+	c.removePeers = remove
+
+	return c
+}
+
+// WithReplayGuard sets the guard against replayed handshake requests and returns the updated context.
+func (c *Context) WithReplayGuard(guard *ReplayGuard) *Context {
+	c.checkSealed()
+	c.replayGuard = guard
+
+	return c
+}
+
+// WithRequireAllocation sets whether handshakes are rejected for sessions with no remaining allocation and returns the updated context.
+func (c *Context) WithRequireAllocation(require bool) *Context {
+	c.checkSealed()
+	c.requireAlloc = require
+
+	return c
+}
+
 // WithRPCAddrs sets the RPC addresses for queries in the context and returns the updated context.
 func (c *Context) WithRPCAddrs(addrs []string) *Context {
 	c.checkSealed()
@@ -427,6 +1306,22 @@ func (c *Context) WithRPCAddrs(addrs []string) *Context {
 	return c
 }
 
+// WithRPCBackoff sets the backoff of rate-limited RPC endpoints and returns the updated context.
+func (c *Context) WithRPCBackoff(backoff *RPCBackoff) *Context {
+	c.checkSealed()
+	c.rpcBackoff = backoff
+
+	return c
+}
+
+// WithRPCFallback sets the fallback across the RPC addresses and returns the updated context.
+func (c *Context) WithRPCFallback(fallback *RPCFallback) *Context {
+	c.checkSealed()
+	c.rpcFallback = fallback
+
+	return c
+}
+
 // WithService sets the server service in the context and returns the updated context.
 func (c *Context) WithService(service sentinelsdk.ServerService) *Context {
 	c.checkSealed()
@@ -435,6 +1330,116 @@ func (c *Context) WithService(service sentinelsdk.ServerService) *Context {
 	return c
 }
 
+// WithSessionIdle sets the idle byte rate threshold and timeout of sessions and returns the updated context.
+func (c *Context) WithSessionIdle(threshold uint64, timeout time.Duration) *Context {
+	c.checkSealed()
+	c.idleRate = threshold
+	c.idleTimeout = timeout
+
+	return c
+}
+
+// WithSessionStore sets the session storage backend in the context and returns the updated context.
+func (c *Context) WithSessionStore(store database.SessionStore) *Context {
+	c.checkSealed()
+	c.sessionStore = store
+
+	return c
+}
+
+// WithSessionUpdateBatchSize sets the maximum number of session update messages broadcast in a single transaction
+// and returns the updated context.
+func (c *Context) WithSessionUpdateBatchSize(size uint) *Context {
+	c.checkSealed()
+	c.sessionBatch = size
+
+	return c
+}
+
+// WithSessionWorkerConcurrency sets the maximum number of sessions processed concurrently by the session workers and returns the updated context.
+func (c *Context) WithSessionWorkerConcurrency(concurrency uint) *Context {
+	c.checkSealed()
+	c.sessionWorkers = concurrency
+
+	return c
+}
+
+// WithSpeedtestStore sets the speed test storage backend in the context and returns the updated context.
+func (c *Context) WithSpeedtestStore(store database.SpeedtestStore) *Context {
+	c.checkSealed()
+	c.speedtestStore = store
+
+	return c
+}
+
+// WithStaleSessions sets the handling of database sessions created against a previous node address and returns the updated context.
+func (c *Context) WithStaleSessions(v string) *Context {
+	c.checkSealed()
+	c.staleSessions = v
+
+	return c
+}
+
+// WithStartupJitterMax sets the maximum random delay before the node registers at startup and returns the updated context.
+func (c *Context) WithStartupJitterMax(v time.Duration) *Context {
+	c.checkSealed()
+	c.startupJitterMax = v
+
+	return c
+}
+
+// WithStaticSpeedtestResults sets the static download and upload speeds and returns the updated context.
+func (c *Context) WithStaticSpeedtestResults(dlSpeed, ulSpeed math.Int) *Context {
+	c.checkSealed()
+	c.staticDLSpeed = dlSpeed
+	c.staticULSpeed = ulSpeed
+
+	return c
+}
+
+// WithTunnel sets the MTU and keepalive surfaced to clients in the handshake response and returns the updated context.
+func (c *Context) WithTunnel(mtu, keepalive uint) *Context {
+	c.checkSealed()
+	c.tunnelKeep = keepalive
+	c.tunnelMTU = mtu
+
+	return c
+}
+
+// WithTxFailureLog sets whether the full raw log of failed transactions is logged and returns the updated context.
+func (c *Context) WithTxFailureLog(enable bool) *Context {
+	c.checkSealed()
+	c.txFailureLog = enable
+
+	return c
+}
+
+// WithUsageAnomaly sets the factor of the measured speeds above which the usage of a session is implausible and the
+// handling of sessions reporting implausible usage, and returns the updated context.
+func (c *Context) WithUsageAnomaly(multiplier float64, action string) *Context {
+	c.checkSealed()
+	c.usageAction = action
+	c.usageFactor = multiplier
+
+	return c
+}
+
+// WithWebhook sets the webhook dispatcher in the context and returns the updated context.
+func (c *Context) WithWebhook(d *webhook.Dispatcher) *Context {
+	c.checkSealed()
+	c.webhook = d
+
+	return c
+}
+
+// WithWorkerSchedule sets the worker schedule in the context and returns the updated context.
+func (c *Context) WithWorkerSchedule(schedule *WorkerSchedule) *Context {
+	c.checkSealed()
+	c.workerSchedule = schedule
+
+	return c
+}
+
 // checkSealed verifies if the context is sealed to prevent modification.
 func (c *Context) checkSealed() {
 	if c.sealed {
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"time"
+
+	"cosmossdk.io/math"
+	sentinelhub "github.com/sentinel-official/sentinelhub/v12/types"
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// Billing modes of a session.
+const (
+	BillingModeGigabyte = "gigabyte" // The session is limited by bytes and priced per gigabyte.
+	BillingModeHourly   = "hourly"   // The session is limited by duration and priced per hour.
+)
+
+// EstimateSessionEarnings returns the billing mode of the session and its estimated earnings for each denom.
+// Sessions limited by bytes are priced per gigabyte and sessions limited by duration are priced per hour.
+func EstimateSessionEarnings(item *models.Session, gigabytePrices, hourlyPrices v1.Prices) (string, map[string]math.LegacyDec) {
+	var (
+		mode     = BillingModeGigabyte
+		prices   = gigabytePrices
+		quantity math.LegacyDec
+	)
+
+	if item.GetMaxBytes().IsZero() {
+		mode = BillingModeHourly
+		prices = hourlyPrices
+		quantity = math.LegacyNewDec(item.GetDuration().Nanoseconds()).QuoInt64(time.Hour.Nanoseconds())
+	} else {
+		quantity = math.LegacyNewDecFromInt(item.GetTotalBytes()).QuoInt(sentinelhub.Gigabyte)
+	}
+
+	earnings := make(map[string]math.LegacyDec)
+	for _, price := range prices {
+		earnings[price.Denom] = quantity.MulInt(price.QuoteValue)
+	}
+
+	return mode, earnings
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+	"github.com/sentinel-official/sentinel-dvpnx/events"
+)
+
+// SetupEventLog opens the session lifecycle event log and assigns it to the context, if an event log file is set.
+func (c *Context) SetupEventLog(cfg *config.Config) error {
+	file := cfg.Node.GetEventLogFile()
+	if file == "" {
+		return nil
+	}
+
+	log.Info("Initializing event log", "file", file, "max_bytes", cfg.Node.GetEventLogMaxBytes())
+
+	v, err := events.NewWriter(file, cfg.Node.GetEventLogMaxBytes())
+	if err != nil {
+		return fmt.Errorf("creating event log writer: %w", err)
+	}
+
+	// Assign the event log writer to the context.
+	c.WithEventLog(v)
+
+	return nil
+}
+
+// RecordSessionEvent appends a lifecycle event of the session to the event log, if the event log is enabled.
+// Failures are logged rather than returned so that auditing never interrupts the session handling.
+func (c *Context) RecordSessionEvent(eventType string, item *models.Session) {
+	w := c.EventLog()
+	if w == nil {
+		return
+	}
+
+	event := &events.Event{
+		AccAddr:   item.AccAddr,
+		EventType: eventType,
+		PeerID:    item.GetPeerID(),
+		SessionID: item.GetID(),
+	}
+
+	if err := w.Write(event); err != nil {
+		log.Error("Failed to record session event", "id", item.GetID(), "event_type", eventType, "cause", err)
+	}
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// rpcFallbackDisabledKey is the context key of requests that must not fall back to another RPC address.
+type rpcFallbackDisabledKey struct{}
+
+// WithoutRPCFallback returns a context whose requests are sent only to their own RPC address, such as the
+// latency probes of an RPC address.
+func WithoutRPCFallback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rpcFallbackDisabledKey{}, true)
+}
+
+// RPCFallback tracks the RPC addresses that failed to connect and the time until which they are skipped.
+// Addresses are identified by their host, and tried in the order of the addresses returned by addrs.
+type RPCFallback struct {
+	addrs    func() []string
+	cooldown time.Duration
+
+	mu    sync.RWMutex
+	until map[string]time.Time
+}
+
+// NewRPCFallback creates an RPCFallback falling back across the RPC addresses returned by addrs.
+func NewRPCFallback(addrs func() []string, cooldown time.Duration) *RPCFallback {
+	return &RPCFallback{
+		addrs:    addrs,
+		cooldown: cooldown,
+		until:    make(map[string]time.Time),
+	}
+}
+
+// fail records that the host failed to connect and skips it for the cooldown.
+func (f *RPCFallback) fail(host string) {
+	if f.cooldown <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.until[host] = time.Now().Add(f.cooldown)
+}
+
+// isHostCoolingDown returns whether the host is skipped after failing to connect.
+func (f *RPCFallback) isHostCoolingDown(host string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	until, ok := f.until[host]
+
+	return ok && time.Now().Before(until)
+}
+
+// IsCoolingDown returns whether the RPC address is skipped after failing to connect.
+func (f *RPCFallback) IsCoolingDown(addr string) bool {
+	v, err := url.Parse(addr)
+	if err != nil {
+		return false
+	}
+
+	return f.isHostCoolingDown(v.Host)
+}
+
+// candidates returns the parsed RPC address of the host followed by the other RPC addresses that are not cooling
+// down, or nil if the host is not one of the RPC addresses.
+func (f *RPCFallback) candidates(host string) []*url.URL {
+	var (
+		origin *url.URL
+		others []*url.URL
+	)
+
+	for _, addr := range f.addrs() {
+		v, err := url.Parse(addr)
+		if err != nil || v.Host == "" {
+			continue
+		}
+
+		if v.Host == host {
+			if origin == nil {
+				origin = v
+			}
+
+			continue
+		}
+
+		if !f.isHostCoolingDown(v.Host) {
+			others = append(others, v)
+		}
+	}
+
+	if origin == nil {
+		return nil
+	}
+
+	return append([]*url.URL{origin}, others...)
+}
+
+// Transport wraps the round tripper to retry the requests to an RPC address that fails to connect against the
+// next RPC address, recording the failing address for the cooldown. Requests to other hosts are passed through
+// unchanged.
+func (f *RPCFallback) Transport(rt http.RoundTripper) http.RoundTripper {
+	return &rpcFallbackTransport{
+		RoundTripper: rt,
+		fallback:     f,
+	}
+}
+
+// rpcFallbackTransport is an http.RoundTripper falling back across the RPC addresses on connection errors.
+type rpcFallbackTransport struct {
+	http.RoundTripper
+
+	fallback *RPCFallback
+}
+
+// RoundTrip executes the request against its RPC address, and against the next RPC addresses while the previous
+// ones fail to connect. The error of the last attempt is returned if all of them fail.
+func (t *rpcFallbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if disabled, _ := req.Context().Value(rpcFallbackDisabledKey{}).(bool); disabled {
+		return t.RoundTripper.RoundTrip(req) //nolint:wrapcheck
+	}
+
+	items := t.fallback.candidates(req.URL.Host)
+	if len(items) == 0 {
+		return t.RoundTripper.RoundTrip(req) //nolint:wrapcheck
+	}
+
+	origin := items[0]
+
+	var err error
+
+	for i, item := range items {
+		r := req
+		if i > 0 {
+			// Requests with a body that cannot be replayed are not retried.
+			v, rerr := t.rewrite(req, origin, item)
+			if rerr != nil {
+				break
+			}
+
+			r = v
+		}
+
+		var resp *http.Response
+
+		resp, err = t.RoundTripper.RoundTrip(r)
+		if err == nil {
+			return resp, nil
+		}
+
+		// Errors caused by the caller giving up are not connection errors of the RPC address.
+		if req.Context().Err() != nil {
+			break
+		}
+
+		t.fallback.fail(item.Host)
+
+		if i+1 < len(items) {
+			log.Warn("Falling back to the next RPC address", "host", item.Host, "next", items[i+1].Host, "cause", err)
+		}
+	}
+
+	return nil, err //nolint:wrapcheck
+}
+
+// rewrite returns a copy of the request to the origin RPC address directed at the target RPC address, with a
+// fresh copy of the body.
+func (t *rpcFallbackTransport) rewrite(req *http.Request, origin, target *url.URL) (*http.Request, error) {
+	r := req.Clone(req.Context())
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, errors.New("request body cannot be replayed")
+		}
+
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		r.Body = body
+	}
+
+	// Keep the path of the request relative to the path of the RPC address.
+	path := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(origin.Path, "/"))
+
+	u := *req.URL
+	u.Scheme = target.Scheme
+	u.Host = target.Host
+	u.User = target.User
+	u.Path = strings.TrimSuffix(target.Path, "/") + "/" + strings.TrimPrefix(path, "/")
+	u.RawPath = ""
+
+	r.URL = &u
+	r.Host = ""
+
+	return r, nil
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"fmt"
+
+	"cosmossdk.io/math"
+)
+
+// byteUnits lists the decimal units used to format byte counts, in increasing order of size.
+var byteUnits = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+
+// FormatBytes formats the byte count in the largest decimal unit not exceeding it, e.g. "1.50 GB".
+func FormatBytes(v math.Int) string {
+	if v.IsNegative() {
+		return "-" + FormatBytes(v.Neg())
+	}
+
+	unit := 0
+	base := math.OneInt()
+	thousand := math.NewInt(1000)
+
+	for unit < len(byteUnits)-1 && v.GTE(base.Mul(thousand)) {
+		base = base.Mul(thousand)
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%s %s", v, byteUnits[unit])
+	}
+
+	d := math.LegacyNewDecFromInt(v).QuoInt(base)
+
+	return fmt.Sprintf("%.2f %s", d.MustFloat64(), byteUnits[unit])
+}
+
+// DisplayBytes returns the byte count for display in logs, followed by its human-readable form if enabled.
+func (c *Context) DisplayBytes(v math.Int) string {
+	if !c.HumanReadableBytes() {
+		return v.String()
+	}
+
+	return fmt.Sprintf("%s (%s)", v, FormatBytes(v))
+}
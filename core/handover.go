@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// importedPeerPrefix prefixes the placeholder peer ID of an imported session whose peer has not been re-added yet.
+const importedPeerPrefix = "imported:"
+
+// HandoverSession represents an active session exported from one node instance to be imported by another.
+type HandoverSession struct {
+	AccAddr         string        `json:"acc_addr"`
+	CreatedAt       time.Time     `json:"created_at"`
+	ID              uint64        `json:"id"`
+	LastSyncedBytes string        `json:"last_synced_bytes"`
+	MaxBytes        string        `json:"max_bytes"`
+	MaxDuration     time.Duration `json:"max_duration"`
+	NodeAddr        string        `json:"node_addr"`
+	PeerRequest     string        `json:"peer_request"`
+	RxBytes         string        `json:"rx_bytes"`
+	ServiceType     string        `json:"service_type"`
+	TxBytes         string        `json:"tx_bytes"`
+}
+
+// NewHandoverSession creates a HandoverSession from the session record.
+func NewHandoverSession(v *models.Session) *HandoverSession {
+	return &HandoverSession{
+		AccAddr:         v.AccAddr,
+		CreatedAt:       v.CreatedAt,
+		ID:              v.GetID(),
+		LastSyncedBytes: v.GetLastSyncedBytes().String(),
+		MaxBytes:        v.GetMaxBytes().String(),
+		MaxDuration:     v.GetMaxDuration(),
+		NodeAddr:        v.NodeAddr,
+		PeerRequest:     v.PeerRequest,
+		RxBytes:         v.GetRxBytes().String(),
+		ServiceType:     v.ServiceType,
+		TxBytes:         v.GetTxBytes().String(),
+	}
+}
+
+// Validate checks that the byte totals of the HandoverSession are valid integers.
+func (s *HandoverSession) Validate() error {
+	for name, v := range map[string]string{
+		"last_synced_bytes": s.LastSyncedBytes,
+		"max_bytes":         s.MaxBytes,
+		"rx_bytes":          s.RxBytes,
+		"tx_bytes":          s.TxBytes,
+	} {
+		if _, ok := math.NewIntFromString(v); !ok {
+			return fmt.Errorf("parsing %s %q of session %d", name, v, s.ID)
+		}
+	}
+
+	return nil
+}
+
+// Session converts the HandoverSession into a session record awaiting its peer to be re-added.
+// The exported byte totals become the base that the usage of the re-added peer is added to.
+func (s *HandoverSession) Session() *models.Session {
+	rxBytes, _ := math.NewIntFromString(s.RxBytes)
+	txBytes, _ := math.NewIntFromString(s.TxBytes)
+
+	return &models.Session{
+		AccAddr:         s.AccAddr,
+		CreatedAt:       s.CreatedAt,
+		ID:              s.ID,
+		LastSyncedBytes: s.LastSyncedBytes,
+		MaxBytes:        s.MaxBytes,
+		MaxDuration:     s.MaxDuration,
+		NodeAddr:        s.NodeAddr,
+		PeerID:          fmt.Sprintf("%s%d", importedPeerPrefix, s.ID),
+		PeerMetadata:    "",
+		PeerRequest:     s.PeerRequest,
+		RxBytes:         rxBytes.String(),
+		RxBytesBase:     rxBytes.String(),
+		ServiceType:     s.ServiceType,
+		TxBytes:         txBytes.String(),
+		TxBytesBase:     txBytes.String(),
+	}
+}
+
+// restoreImportedPeer records the peer re-added for an imported session in the database.
+func (c *Context) restoreImportedPeer(item *models.Session, id string, data interface{}) error {
+	metadata, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding add-peer service response: %w", err)
+	}
+
+	query := map[string]interface{}{
+		"id": item.GetID(),
+	}
+	updates := map[string]interface{}{
+		"peer_id":       id,
+		"peer_metadata": base64.StdEncoding.EncodeToString(metadata),
+	}
+
+	if _, err := c.SessionStore().FindOneAndUpdate(query, updates); err != nil {
+		return fmt.Errorf("updating peer of session %d in database: %w", item.GetID(), err)
+	}
+
+	return nil
+}
+
+// RestoreImportedPeers re-adds the peers of the imported sessions to the service using their stored peer requests.
+// Sessions whose peer cannot be re-added are logged and keep their placeholder peer ID.
+func (c *Context) RestoreImportedPeers(ctx context.Context) error {
+	items, err := c.SessionStore().FindByPeerIDPrefix(importedPeerPrefix)
+	if err != nil {
+		return fmt.Errorf("retrieving imported sessions from database: %w", err)
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	log.Info("Restoring peers of imported sessions", "count", len(items))
+
+	for i := range items {
+		item := &items[i]
+
+		id, data, err := c.Service().AddPeer(ctx, item.GetPeerRequest())
+		if err != nil {
+			log.Error("Failed to restore peer of imported session", "id", item.GetID(), "cause", err)
+
+			continue
+		}
+
+		if err := c.restoreImportedPeer(item, id, data); err != nil {
+			log.Error("Failed to restore peer of imported session", "id", item.GetID(), "cause", err)
+
+			if err := c.Service().RemovePeer(ctx, id); err != nil {
+				log.Error("Failed to remove restored peer from service", "id", item.GetID(), "peer_id", id, "cause", err)
+			}
+
+			continue
+		}
+
+		log.Info("Peer of imported session has been restored", "id", item.GetID(), "peer_id", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+
+	"github.com/oschwald/maxminddb-golang/v2"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/geoip"
+)
+
+// Ensure MaxMindClient implements the geoip.Client interface.
+var _ geoip.Client = (*MaxMindClient)(nil)
+
+// maxMindRecord holds the fields of a MaxMind city or country database record used for the location.
+type maxMindRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// MaxMindClient resolves locations from a local MaxMind database file without making outbound calls.
+// Lookups of the empty IP address resolve the first of the default addresses that resolves, since the public IP
+// address of the node cannot be discovered locally. Default addresses that are host names are resolved through DNS.
+type MaxMindClient struct {
+	defaultAddrs []string
+	reader       *maxminddb.Reader
+}
+
+// NewMaxMindClient reads the MaxMind database file into memory and returns a client resolving locations from it.
+func NewMaxMindClient(file string, defaultAddrs []string) (*MaxMindClient, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading maxmind database %q: %w", file, err)
+	}
+
+	reader, err := maxminddb.OpenBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("opening maxmind database %q: %w", file, err)
+	}
+
+	return &MaxMindClient{
+		defaultAddrs: defaultAddrs,
+		reader:       reader,
+	}, nil
+}
+
+// Get resolves the location of the IP address, or of the default addresses if ipAddr is empty.
+func (c *MaxMindClient) Get(ctx context.Context, ipAddr string) (*geoip.Location, error) {
+	if ipAddr != "" {
+		ip, err := netip.ParseAddr(ipAddr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ip address %q: %w", ipAddr, err)
+		}
+
+		return c.lookup(ip)
+	}
+
+	if len(c.defaultAddrs) == 0 {
+		return nil, errors.New("no ip address to resolve in the local maxmind database")
+	}
+
+	var errs []error
+
+	for _, addr := range c.defaultAddrs {
+		ips, err := resolveAddr(ctx, addr)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		for _, ip := range ips {
+			loc, err := c.lookup(ip)
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			return loc, nil
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// lookup resolves the location of the IP address from the database.
+func (c *MaxMindClient) lookup(ip netip.Addr) (*geoip.Location, error) {
+	ip = ip.Unmap()
+
+	result := c.reader.Lookup(ip)
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("looking up ip address %q: %w", ip, err)
+	}
+
+	if !result.Found() {
+		return nil, fmt.Errorf("ip address %q not found in maxmind database", ip)
+	}
+
+	var v maxMindRecord
+	if err := result.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding record of ip address %q: %w", ip, err)
+	}
+
+	return &geoip.Location{
+		City:        v.City.Names["en"],
+		Country:     v.Country.Names["en"],
+		CountryCode: v.Country.ISOCode,
+		IP:          ip.String(),
+		Latitude:    v.Location.Latitude,
+		Longitude:   v.Location.Longitude,
+	}, nil
+}
+
+// resolveAddr returns the address as an IP address, or the IP addresses of the address resolved as a host name.
+func resolveAddr(ctx context.Context, addr string) ([]netip.Addr, error) {
+	if ip, err := netip.ParseAddr(addr); err == nil {
+		return []netip.Addr{ip}, nil
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", addr)
+	if err != nil {
+		return nil, fmt.Errorf("resolving host %q: %w", addr, err)
+	}
+
+	return ips, nil
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// mmdbString encodes the value as an MMDB data section UTF-8 string.
+func mmdbString(v string) []byte {
+	return append([]byte{0x40 | byte(len(v))}, v...)
+}
+
+// mmdbDouble encodes the value as an MMDB data section double.
+func mmdbDouble(v float64) []byte {
+	buf := []byte{0x68, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(buf[1:], math.Float64bits(v))
+
+	return buf
+}
+
+// mmdbUint16 encodes the value as an MMDB data section unsigned 16-bit integer.
+func mmdbUint16(v uint16) []byte {
+	return []byte{0xA2, byte(v >> 8), byte(v)}
+}
+
+// mmdbUint32 encodes the value as an MMDB data section unsigned 32-bit integer.
+func mmdbUint32(v uint32) []byte {
+	return []byte{0xC4, byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+// mmdbUint64 encodes the value as an MMDB data section unsigned 64-bit integer.
+func mmdbUint64(v uint64) []byte {
+	buf := []byte{0x08, 0x02, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(buf[2:], v)
+
+	return buf
+}
+
+// mmdbArray encodes the values as an MMDB data section array.
+func mmdbArray(values ...[]byte) []byte {
+	return append([]byte{byte(len(values)), 0x04}, bytes.Join(values, nil)...)
+}
+
+// mmdbMap encodes the key and value pairs as an MMDB data section map.
+func mmdbMap(pairs ...[]byte) []byte {
+	return append([]byte{0xE0 | byte(len(pairs)/2)}, bytes.Join(pairs, nil)...)
+}
+
+// writeTestMaxMindDB writes an IPv4 MaxMind database with a 24-bit record size in which every address of the
+// 81.0.0.0/8 network resolves to a London record, and returns the path of the file.
+func writeTestMaxMindDB(t *testing.T) string {
+	t.Helper()
+
+	const (
+		prefix    = 81
+		prefixLen = 8
+		nodeCount = prefixLen
+	)
+
+	record := mmdbMap(
+		mmdbString("city"), mmdbMap(
+			mmdbString("names"), mmdbMap(mmdbString("en"), mmdbString("London")),
+		),
+		mmdbString("country"), mmdbMap(
+			mmdbString("iso_code"), mmdbString("GB"),
+			mmdbString("names"), mmdbMap(mmdbString("en"), mmdbString("United Kingdom")),
+		),
+		mmdbString("location"), mmdbMap(
+			mmdbString("latitude"), mmdbDouble(51.5142),
+			mmdbString("longitude"), mmdbDouble(-0.0931),
+		),
+	)
+
+	putRecord := func(buf []byte, v uint32) {
+		buf[0], buf[1], buf[2] = byte(v>>16), byte(v>>8), byte(v)
+	}
+
+	// Each node follows one bit of the prefix, the other branch leads to the empty record equal to nodeCount.
+	var tree []byte
+
+	for i := 0; i < prefixLen; i++ {
+		next := uint32(i + 1)
+		if i == prefixLen-1 {
+			next = nodeCount + 16 // Pointer to the record at offset zero of the data section
+		}
+
+		node := make([]byte, 6)
+		putRecord(node[0:3], nodeCount)
+		putRecord(node[3:6], nodeCount)
+
+		if (prefix>>(prefixLen-1-i))&1 == 1 {
+			putRecord(node[3:6], next)
+		} else {
+			putRecord(node[0:3], next)
+		}
+
+		tree = append(tree, node...)
+	}
+
+	metadata := mmdbMap(
+		mmdbString("binary_format_major_version"), mmdbUint16(2),
+		mmdbString("binary_format_minor_version"), mmdbUint16(0),
+		mmdbString("build_epoch"), mmdbUint64(1700000000),
+		mmdbString("database_type"), mmdbString("Test-City"),
+		mmdbString("description"), mmdbMap(mmdbString("en"), mmdbString("Test database")),
+		mmdbString("ip_version"), mmdbUint16(4),
+		mmdbString("languages"), mmdbArray(mmdbString("en")),
+		mmdbString("node_count"), mmdbUint32(nodeCount),
+		mmdbString("record_size"), mmdbUint16(24),
+	)
+
+	var buf bytes.Buffer
+
+	buf.Write(tree)
+	buf.Write(make([]byte, 16))
+	buf.Write(record)
+	buf.WriteString("\xab\xcd\xefMaxMind.com")
+	buf.Write(metadata)
+
+	file := filepath.Join(t.TempDir(), "test.mmdb")
+	if err := os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("writing maxmind database: %v", err)
+	}
+
+	return file
+}
+
+func TestMaxMindClientGet(t *testing.T) {
+	file := writeTestMaxMindDB(t)
+
+	tests := []struct {
+		name         string
+		defaultAddrs []string
+		ipAddr       string
+		wantIP       string
+		wantErr      bool
+	}{
+		{"ip found", nil, "81.2.69.160", "81.2.69.160", false},
+		{"ipv4 mapped ip found", nil, "::ffff:81.2.69.160", "81.2.69.160", false},
+		{"ip not found", nil, "1.1.1.1", "", true},
+		{"invalid ip", nil, "not-an-ip", "", true},
+		{"default ip", []string{"81.2.69.160"}, "", "81.2.69.160", false},
+		{"default ip after host not found", []string{"localhost", "81.2.69.160"}, "", "81.2.69.160", false},
+		{"default ip not found", []string{"1.1.1.1"}, "", "", true},
+		{"no default addrs", nil, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewMaxMindClient(file, tt.defaultAddrs)
+			if err != nil {
+				t.Fatalf("creating maxmind client: %v", err)
+			}
+
+			loc, err := client.Get(context.Background(), tt.ipAddr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got location %+v", loc)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("getting location: %v", err)
+			}
+
+			if loc.IP != tt.wantIP {
+				t.Fatalf("expected ip %q, got %q", tt.wantIP, loc.IP)
+			}
+
+			if loc.City != "London" || loc.Country != "United Kingdom" || loc.CountryCode != "GB" {
+				t.Fatalf("unexpected location %+v", loc)
+			}
+
+			if loc.Latitude != 51.5142 || loc.Longitude != -0.0931 {
+				t.Fatalf("unexpected coordinates %f, %f", loc.Latitude, loc.Longitude)
+			}
+		})
+	}
+}
+
+func TestNewMaxMindClientInvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "invalid.mmdb")
+	if err := os.WriteFile(file, []byte("not a database"), 0o600); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	if _, err := NewMaxMindClient(file, nil); err == nil {
+		t.Fatal("expected an error for an invalid database")
+	}
+
+	if _, err := NewMaxMindClient(filepath.Join(t.TempDir(), "missing.mmdb"), nil); err == nil {
+		t.Fatal("expected an error for a missing database")
+	}
+}
@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+	"github.com/sentinel-official/sentinelhub/v12/x/node/types/v3"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+)
+
+// Bounds of a plausible quote value, in whole tokens of the denomination, per gigabyte or hour.
+var (
+	minPlausibleQuote = math.LegacyNewDecWithPrec(1, 4)
+	maxPlausibleQuote = math.LegacyNewDec(1_000_000)
+)
+
+// checkPriceExponents warns about the prices whose quote value is implausible for the exponent of their denomination.
+// Prices in a denomination without a configured exponent are not checked.
+func checkPriceExponents(name string, prices v1.Prices, exponents map[string]uint) {
+	for _, price := range prices {
+		exponent, ok := exponents[price.Denom]
+		if !ok || price.QuoteValue.IsNil() || price.QuoteValue.IsZero() {
+			continue
+		}
+
+		tokens := math.LegacyNewDecFromInt(price.QuoteValue).Quo(math.LegacyNewDec(10).Power(uint64(exponent)))
+
+		if tokens.LT(minPlausibleQuote) {
+			log.Warn("Configured price is implausibly low for the exponent of its denom",
+				"prices", name, "denom", price.Denom, "exponent", exponent, "quote_value", price.QuoteValue, "tokens", tokens)
+		}
+
+		if tokens.GT(maxPlausibleQuote) {
+			log.Warn("Configured price is implausibly high for the exponent of its denom",
+				"prices", name, "denom", price.Denom, "exponent", exponent, "quote_value", price.QuoteValue, "tokens", tokens)
+		}
+	}
+}
+
+// CheckPriceExponents warns about the configured prices that are implausibly high or low for the exponents of
+// their denominations, which usually means the quote value was given in the wrong unit.
+func CheckPriceExponents(cfg *config.Config) {
+	exponents := cfg.Node.GetDenomExponents()
+
+	checkPriceExponents("gigabyte_prices", cfg.Node.GetGigabytePrices(), exponents)
+	checkPriceExponents("hourly_prices", cfg.Node.GetHourlyPrices(), exponents)
+}
+
+// CheckGasPriceDenoms warns about the denominations of the tx gas prices in which no price of the node is
+// configured. Fees may be paid in another token, but a mismatch usually means the prices or the gas prices were
+// left at the defaults of another chain.
+func CheckGasPriceDenoms(cfg *config.Config) {
+	denoms := make(map[string]bool)
+	for _, price := range cfg.Node.GetGigabytePrices() {
+		denoms[price.Denom] = true
+	}
+
+	for _, price := range cfg.Node.GetHourlyPrices() {
+		denoms[price.Denom] = true
+	}
+
+	if len(denoms) == 0 {
+		return
+	}
+
+	for _, coin := range cfg.Tx.GetGasPrices() {
+		if !denoms[coin.Denom] {
+			log.Warn("Configured gas price is in a denom without any configured price", "denom", coin.Denom)
+		}
+	}
+}
+
+// errNoAcceptedDenoms returns an error if prices are configured but none of them is accepted by the node params,
+// so that the node is not registered or updated with empty prices.
+func errNoAcceptedDenoms(name string, prices, sanitized v1.Prices) error {
+	if len(prices) == 0 || len(sanitized) > 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s contain no denom accepted by the node params: %v", name, unsupportedDenoms(prices, sanitized))
+}
+
+// unsupportedDenoms returns the denominations of the prices that are missing from the sanitized prices.
+func unsupportedDenoms(prices, sanitized v1.Prices) (denoms []string) {
+	m := sanitized.Map()
+	for _, price := range prices {
+		if _, ok := m[price.Denom]; !ok {
+			denoms = append(denoms, price.Denom)
+		}
+	}
+
+	return denoms
+}
+
+// checkPriceDenoms warns about each price in a denomination not accepted by the node params, and returns an error
+// if all the prices would be dropped.
+func checkPriceDenoms(name string, prices, sanitized v1.Prices) error {
+	denoms := unsupportedDenoms(prices, sanitized)
+	for _, denom := range denoms {
+		log.Warn("Dropping configured price in a denom not accepted by the node params", "prices", name, "denom", denom)
+	}
+
+	return errNoAcceptedDenoms(name, prices, sanitized)
+}
+
+// CheckPriceDenoms compares the denominations of the configured prices against the minimum prices of the node
+// params, which would otherwise silently drop the unsupported ones when the prices are sanitized.
+func (c *Context) CheckPriceDenoms(ctx context.Context) error {
+	params, err := c.Client().NodeParams(ctx)
+	if err != nil {
+		return fmt.Errorf("getting node params: %w", err)
+	}
+
+	gigabytePrices := c.GigabytePrices()
+	if err := checkPriceDenoms("gigabyte_prices", gigabytePrices, c.sanitizePrices(gigabytePrices, params.GetMinGigabytePrices())); err != nil {
+		return err
+	}
+
+	hourlyPrices := c.HourlyPrices()
+	if err := checkPriceDenoms("hourly_prices", hourlyPrices, c.sanitizePrices(hourlyPrices, params.GetMinHourlyPrices())); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NextPricingWindowChange returns the duration from the time until the next start or end of a window of the pricing
+// schedule, or zero if there is no schedule.
+func (c *Context) NextPricingWindowChange(t time.Time) time.Duration {
+	t = t.UTC()
+	v := t.Sub(t.Truncate(24 * time.Hour))
+
+	var next time.Duration
+	for _, window := range c.PricingSchedule() {
+		for _, boundary := range []time.Duration{window.GetStart(), window.GetEnd()} {
+			d := boundary - v
+			if d <= 0 {
+				d += 24 * time.Hour
+			}
+
+			if next == 0 || d < next {
+				next = d
+			}
+		}
+	}
+
+	return next
+}
+
+// PricingWindow returns the window of the pricing schedule containing the time, or nil if there is none.
+func (c *Context) PricingWindow(t time.Time) *config.PricingWindowConfig {
+	for _, window := range c.PricingSchedule() {
+		if window.Contains(t) {
+			return window
+		}
+	}
+
+	return nil
+}
+
+// ScheduledPrices returns the gigabyte and hourly prices to apply on-chain at the time, filtered to include only
+// valid denominations. These are the prices of the pricing window containing the time, or the prices of the
+// context outside every window.
+func (c *Context) ScheduledPrices(ctx context.Context, t time.Time) (gigabytePrices, hourlyPrices v1.Prices, err error) {
+	window := c.PricingWindow(t)
+	if window == nil {
+		if gigabytePrices, err = c.SanitizedGigabytePrices(ctx); err != nil {
+			return nil, nil, fmt.Errorf("sanitizing gigabyte prices: %w", err)
+		}
+
+		if hourlyPrices, err = c.SanitizedHourlyPrices(ctx); err != nil {
+			return nil, nil, fmt.Errorf("sanitizing hourly prices: %w", err)
+		}
+
+		return gigabytePrices, hourlyPrices, nil
+	}
+
+	params, err := c.Client().NodeParams(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting node params: %w", err)
+	}
+
+	gigabytePrices = c.sanitizePrices(window.GetGigabytePrices(), params.GetMinGigabytePrices())
+	if err := errNoAcceptedDenoms("gigabyte_prices of the pricing window", window.GetGigabytePrices(), gigabytePrices); err != nil {
+		return nil, nil, err
+	}
+
+	hourlyPrices = c.sanitizePrices(window.GetHourlyPrices(), params.GetMinHourlyPrices())
+	if err := errNoAcceptedDenoms("hourly_prices of the pricing window", window.GetHourlyPrices(), hourlyPrices); err != nil {
+		return nil, nil, err
+	}
+
+	return gigabytePrices, hourlyPrices, nil
+}
+
+// ReloadPrices validates the prices against the minimum prices of the node params, broadcasts them in a
+// MsgUpdateNodeDetailsRequest, and updates the prices of the context once the transaction succeeds.
+// Prices in a denomination not accepted by the node params are rejected rather than dropped.
+func (c *Context) ReloadPrices(ctx context.Context, gigabytePrices, hourlyPrices v1.Prices) error {
+	params, err := c.Client().NodeParams(ctx)
+	if err != nil {
+		return fmt.Errorf("getting node params: %w", err)
+	}
+
+	sanitizedGigabytePrices := c.sanitizePrices(gigabytePrices, params.GetMinGigabytePrices())
+	if denoms := unsupportedDenoms(gigabytePrices, sanitizedGigabytePrices); len(denoms) > 0 {
+		return fmt.Errorf("gigabyte_prices contain unsupported denoms %v", denoms)
+	}
+
+	sanitizedHourlyPrices := c.sanitizePrices(hourlyPrices, params.GetMinHourlyPrices())
+	if denoms := unsupportedDenoms(hourlyPrices, sanitizedHourlyPrices); len(denoms) > 0 {
+		return fmt.Errorf("hourly_prices contain unsupported denoms %v", denoms)
+	}
+
+	log.Info("Reloading node prices", "gigabyte_prices", sanitizedGigabytePrices, "hourly_prices", sanitizedHourlyPrices)
+
+	msg := v3.NewMsgUpdateNodeDetailsRequest(
+		c.NodeAddr(),
+		sanitizedGigabytePrices,
+		sanitizedHourlyPrices,
+		nil,
+	)
+
+	if err := c.BroadcastTx(ctx, msg); err != nil {
+		return fmt.Errorf("broadcasting tx with update_node_details msg: %w", err)
+	}
+
+	c.SetGigabytePrices(gigabytePrices)
+	c.SetHourlyPrices(hourlyPrices)
+
+	return nil
+}
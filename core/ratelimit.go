@@ -0,0 +1,139 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// ErrRPCRateLimited is returned for requests to an RPC endpoint that is backed off after rate limiting the node.
+var ErrRPCRateLimited = errors.New("rpc endpoint is rate limited")
+
+// RPCBackoff tracks the RPC endpoints that rate limited the node and the time until which they are backed off.
+// Endpoints are identified by the host of their address.
+type RPCBackoff struct {
+	defaultBackoff time.Duration
+	maxBackoff     time.Duration
+
+	mu    sync.RWMutex
+	hosts map[string]bool
+	until map[string]time.Time
+}
+
+// NewRPCBackoff creates an RPCBackoff tracking the hosts of the given RPC addresses.
+func NewRPCBackoff(addrs []string, defaultBackoff, maxBackoff time.Duration) *RPCBackoff {
+	hosts := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		if v, err := url.Parse(addr); err == nil && v.Host != "" {
+			hosts[v.Host] = true
+		}
+	}
+
+	return &RPCBackoff{
+		defaultBackoff: defaultBackoff,
+		maxBackoff:     maxBackoff,
+		hosts:          hosts,
+		until:          make(map[string]time.Time),
+	}
+}
+
+// retryAfter returns the backoff requested by the Retry-After header of the response, bounded by the maximum
+// backoff, or the default backoff if the header is missing or invalid.
+func (b *RPCBackoff) retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return b.defaultBackoff
+	}
+
+	var d time.Duration
+	if secs, err := strconv.ParseUint(v, 10, 32); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	}
+
+	if d <= 0 {
+		return b.defaultBackoff
+	}
+
+	return min(d, b.maxBackoff)
+}
+
+// backoff records that the host rate limited the node for the given duration.
+func (b *RPCBackoff) backoff(host string, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.until[host] = time.Now().Add(d)
+}
+
+// hostUntil returns the time until which the host is backed off, or the zero time if it is not.
+func (b *RPCBackoff) hostUntil(host string) time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	until, ok := b.until[host]
+	if !ok || time.Now().After(until) {
+		return time.Time{}
+	}
+
+	return until
+}
+
+// IsBackedOff returns whether the RPC address is backed off after rate limiting the node.
+func (b *RPCBackoff) IsBackedOff(addr string) bool {
+	v, err := url.Parse(addr)
+	if err != nil {
+		return false
+	}
+
+	return !b.hostUntil(v.Host).IsZero()
+}
+
+// Transport wraps the round tripper to fail fast on requests to backed off RPC endpoints and to back off the
+// RPC endpoints that answer with HTTP 429. Requests to other hosts are passed through unchanged.
+func (b *RPCBackoff) Transport(rt http.RoundTripper) http.RoundTripper {
+	return &rpcBackoffTransport{
+		RoundTripper: rt,
+		backoff:      b,
+	}
+}
+
+// rpcBackoffTransport is an http.RoundTripper applying the backoff of rate-limited RPC endpoints.
+type rpcBackoffTransport struct {
+	http.RoundTripper
+
+	backoff *RPCBackoff
+}
+
+// RoundTrip executes the request unless its RPC endpoint is backed off, and records rate-limited responses.
+func (t *rpcBackoffTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if !t.backoff.hosts[host] {
+		return t.RoundTripper.RoundTrip(req) //nolint:wrapcheck
+	}
+
+	if until := t.backoff.hostUntil(host); !until.IsZero() {
+		return nil, fmt.Errorf("requesting %q until %s: %w", host, until.Format(time.RFC3339), ErrRPCRateLimited)
+	}
+
+	resp, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		d := t.backoff.retryAfter(resp)
+		t.backoff.backoff(host, d)
+
+		log.Warn("Backing off rate-limited RPC endpoint", "host", host, "duration", d)
+	}
+
+	return resp, nil
+}
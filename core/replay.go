@@ -0,0 +1,150 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrHandshakeReplayed is returned for a handshake request identical to one seen within the replay window.
+	ErrHandshakeReplayed = errors.New("handshake request replayed")
+
+	// ErrHandshakeStale is returned for a handshake request whose embedded timestamp is outside the replay window.
+	ErrHandshakeStale = errors.New("handshake request timestamp outside replay window")
+)
+
+// ReplayGuard rejects handshake requests that are replayed or carry a timestamp outside the window. The hashes of
+// the checked requests are remembered for the window, whether they are accepted or rejected later, and expired
+// hashes are swept lazily on later checks.
+type ReplayGuard struct {
+	requireTimestamp bool
+	window           time.Duration
+
+	mu      sync.Mutex
+	seen    map[string]time.Time
+	sweptAt time.Time
+}
+
+// NewReplayGuard creates a ReplayGuard remembering handshake requests for the window.
+func NewReplayGuard(window time.Duration, requireTimestamp bool) *ReplayGuard {
+	return &ReplayGuard{
+		requireTimestamp: requireTimestamp,
+		window:           window,
+		seen:             make(map[string]time.Time),
+	}
+}
+
+// replayHash returns the hash identifying the handshake request.
+func replayHash(id uint64, data []byte, pubKey, signature string) string {
+	h := sha256.New()
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id)
+	h.Write(buf[:])
+
+	// Prefix the variable-length fields with their length so that their boundaries are unambiguous.
+	for _, v := range [][]byte{data, []byte(pubKey), []byte(signature)} {
+		binary.BigEndian.PutUint64(buf[:], uint64(len(v)))
+		h.Write(buf[:])
+		h.Write(v)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestTimestamp returns the timestamp embedded in the signed data of the handshake request, as unix seconds or
+// an RFC 3339 string under the "timestamp" key. The zero time is returned if the data has no timestamp.
+func requestTimestamp(data []byte) (time.Time, error) {
+	var v struct {
+		Timestamp json.RawMessage `json:"timestamp"`
+	}
+
+	// Data that is not a JSON object carries no timestamp.
+	if json.Unmarshal(data, &v) != nil || len(v.Timestamp) == 0 || string(v.Timestamp) == "null" {
+		return time.Time{}, nil
+	}
+
+	var secs int64
+	if err := json.Unmarshal(v.Timestamp, &secs); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(v.Timestamp, &s); err != nil {
+		return time.Time{}, fmt.Errorf("decoding timestamp %s: %w", v.Timestamp, err)
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing timestamp %q: %w", s, err)
+	}
+
+	return t, nil
+}
+
+// sweep removes the hashes seen before the window, at most once per window. The caller must hold the lock.
+func (g *ReplayGuard) sweep(now time.Time) {
+	if now.Sub(g.sweptAt) < g.window {
+		return
+	}
+
+	for k, v := range g.seen {
+		if now.Sub(v) >= g.window {
+			delete(g.seen, k)
+		}
+	}
+
+	g.sweptAt = now
+}
+
+// Check returns an error if the handshake request carries a timestamp outside the window, lacks one while it is
+// required, or is identical to a request remembered within the window. Otherwise the request is remembered in the
+// same critical section, so that concurrent identical requests pass the check only once.
+func (g *ReplayGuard) Check(id uint64, data []byte, pubKey, signature string) error {
+	now := time.Now()
+
+	t, err := requestTimestamp(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrHandshakeStale, err)
+	}
+
+	if t.IsZero() {
+		if g.requireTimestamp {
+			return fmt.Errorf("%w: missing timestamp", ErrHandshakeStale)
+		}
+	} else if d := now.Sub(t); d > g.window || -d > g.window {
+		return fmt.Errorf("%w: timestamp %s is %s from now", ErrHandshakeStale, t.UTC().Format(time.RFC3339), d.Round(time.Second))
+	}
+
+	key := replayHash(id, data, pubKey, signature)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.sweep(now)
+
+	if at, ok := g.seen[key]; ok && now.Sub(at) < g.window {
+		return fmt.Errorf("%w: first seen at %s", ErrHandshakeReplayed, at.UTC().Format(time.RFC3339))
+	}
+
+	g.seen[key] = now
+
+	return nil
+}
+
+// Forget removes the handshake request remembered by Check, so that the client can retry a request that failed for
+// a transient reason on the side of the node.
+func (g *ReplayGuard) Forget(id uint64, data []byte, pubKey, signature string) {
+	key := replayHash(id, data, pubKey, signature)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.seen, key)
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+)
+
+// NewRPCTLSTransport returns a round tripper sending the requests to the hosts of the RPC addresses through a copy
+// of the transport using the TLS config, and the other requests through the transport unchanged. This keeps a
+// private CA or a disabled verification from applying to connections other than the RPC ones.
+func NewRPCTLSTransport(transport *http.Transport, addrs []string, config *tls.Config) http.RoundTripper {
+	hosts := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		if v, err := url.Parse(addr); err == nil && v.Host != "" {
+			hosts[v.Host] = true
+		}
+	}
+
+	rpc := transport.Clone()
+	rpc.TLSClientConfig = config
+
+	return &rpcTLSTransport{
+		RoundTripper: transport,
+		hosts:        hosts,
+		rpc:          rpc,
+	}
+}
+
+// rpcTLSTransport is an http.RoundTripper applying the TLS config of the RPC connections.
+type rpcTLSTransport struct {
+	http.RoundTripper
+
+	hosts map[string]bool
+	rpc   http.RoundTripper
+}
+
+// RoundTrip executes the request through the RPC transport if it is sent to an RPC address.
+func (t *rpcTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.hosts[req.URL.Host] {
+		return t.rpc.RoundTrip(req) //nolint:wrapcheck
+	}
+
+	return t.RoundTripper.RoundTrip(req) //nolint:wrapcheck
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkerTiming holds the timing state of a scheduler worker.
+type WorkerTiming struct {
+	Interval time.Duration // Interval between the end of a run and the start of the next one.
+	LastRun  time.Time     // LastRun is the time the last run finished, zero if the worker has not run yet.
+	Name     string        // Name of the worker.
+	Running  bool          // Running specifies if the worker is currently running.
+}
+
+// NextRun returns the time of the next run, or the zero time if the worker has not run yet or is running.
+func (t WorkerTiming) NextRun() time.Time {
+	if t.Running || t.LastRun.IsZero() {
+		return time.Time{}
+	}
+
+	return t.LastRun.Add(t.Interval)
+}
+
+// WorkerSchedule records the timing state of the scheduler workers.
+type WorkerSchedule struct {
+	mu      sync.RWMutex
+	timings map[string]*WorkerTiming
+}
+
+// NewWorkerSchedule creates a new, empty WorkerSchedule.
+func NewWorkerSchedule() *WorkerSchedule {
+	return &WorkerSchedule{
+		timings: make(map[string]*WorkerTiming),
+	}
+}
+
+// Register adds a worker with the given interval to the schedule.
+func (s *WorkerSchedule) Register(name string, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timings[name] = &WorkerTiming{Interval: interval, Name: name}
+}
+
+// RecordStart marks the worker as running.
+func (s *WorkerSchedule) RecordStart(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timings[name]; ok {
+		t.Running = true
+	}
+}
+
+// RecordEnd marks the worker as finished at the given time.
+func (s *WorkerSchedule) RecordEnd(name string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timings[name]; ok {
+		t.LastRun = at
+		t.Running = false
+	}
+}
+
+// Timings returns a copy of the timing state of all workers, sorted by name.
+func (s *WorkerSchedule) Timings() []WorkerTiming {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]WorkerTiming, 0, len(s.timings))
+	for _, t := range s.timings {
+		items = append(items, *t)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
@@ -2,11 +2,78 @@ package core
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+	"github.com/sentinel-official/sentinel-go-sdk/openvpn"
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+	"github.com/sentinel-official/sentinel-go-sdk/v2ray"
+	"github.com/sentinel-official/sentinel-go-sdk/wireguard"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+	"github.com/sentinel-official/sentinel-dvpnx/database/operations"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
 )
 
+// releasedPeerPrefix prefixes the placeholder peer ID and peer request of a session whose peer request was released.
+const releasedPeerPrefix = "released:"
+
+// IsReleasedPeerID returns whether the peer ID is a placeholder of a session whose peer request was released.
+func IsReleasedPeerID(id string) bool {
+	return strings.HasPrefix(id, releasedPeerPrefix)
+}
+
+// PeerIDFromRequest decodes the peer request of the service type and returns the ID the service assigns to the
+// peer: the UUID of OpenVPN and V2Ray clients, which is also the common name of the issued OpenVPN certificate and
+// the email of the V2Ray user, and the public key of WireGuard clients. Requests without an identity are rejected,
+// since the peer IDs of the sessions must be unique.
+func PeerIDFromRequest(t types.ServiceType, data []byte) (string, error) {
+	var zero openvpn.PeerRequest
+
+	switch t {
+	case types.ServiceTypeOpenVPN:
+		var req openvpn.PeerRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", fmt.Errorf("decoding openvpn peer request: %w", err)
+		}
+
+		if req.UUID == zero.UUID {
+			return "", errors.New("uuid is zero")
+		}
+
+		return req.ID(), nil
+	case types.ServiceTypeV2Ray:
+		var req v2ray.PeerRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", fmt.Errorf("decoding v2ray peer request: %w", err)
+		}
+
+		if req.UUID == zero.UUID {
+			return "", errors.New("uuid is zero")
+		}
+
+		return req.ID(), nil
+	case types.ServiceTypeWireGuard:
+		var req wireguard.PeerRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", fmt.Errorf("decoding wireguard peer request: %w", err)
+		}
+
+		if err := req.Validate(); err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		return req.ID(), nil
+	default:
+		return "", fmt.Errorf("unsupported service type %q", t)
+	}
+}
+
 // RemovePeerIfExists checks if a peer exists, and removes it if found.
 func (c *Context) RemovePeerIfExists(ctx context.Context, id string) error {
 	// Check if the peer exists.
@@ -25,6 +92,174 @@ func (c *Context) RemovePeerIfExists(ctx context.Context, id string) error {
 	}
 
 	log.Info("Peer has been removed from service", "peer_id", id)
+	c.EmitEvent(webhook.EventTypePeerRemoved, map[string]interface{}{"peer_id": id})
+
+	// Record the disconnect event for the associated session.
+	query := map[string]interface{}{
+		"peer_id": id,
+	}
+
+	session, err := c.SessionStore().FindOne(query)
+	if err != nil {
+		return fmt.Errorf("retrieving session for peer %q from database: %w", id, err)
+	}
+
+	if session == nil {
+		return nil
+	}
+
+	event := models.NewSessionEventFromSession(session, models.SessionEventTypeDisconnect)
+	if err := operations.SessionEventInsertOne(c.Database(), event); err != nil {
+		return fmt.Errorf("inserting disconnect event for session %d into database: %w", session.GetID(), err)
+	}
+
+	// Free the peer request so the returning client is not blocked until the session record is deleted.
+	if c.PeerRequestReuse() {
+		if err := c.ReleasePeerRequest(session); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReleasePeerRequest frees the peer ID and peer request of a session whose peer has been removed, so that a
+// returning client can perform a new handshake with the same key material. The session record is kept for usage
+// syncing, and the unique index entries are replaced with placeholders derived from the session ID.
+func (c *Context) ReleasePeerRequest(session *models.Session) error {
+	released := fmt.Sprintf("%s%d", releasedPeerPrefix, session.GetID())
+
+	query := map[string]interface{}{
+		"id": session.GetID(),
+	}
+	updates := map[string]interface{}{
+		"peer_id":      released,
+		"peer_request": released,
+	}
+
+	if _, err := c.SessionStore().FindOneAndUpdate(query, updates); err != nil {
+		return fmt.Errorf("releasing peer request of session %d in database: %w", session.GetID(), err)
+	}
+
+	log.Debug("Peer request has been released", "id", session.GetID(), "peer_id", session.GetPeerID())
+
+	return nil
+}
+
+// RollbackPeer undoes a partially completed handshake by removing the peer from the service and deleting the
+// session record inserted for it, so that a failed handshake leaves no partial state behind. A node runs a single
+// service, so every session is routed to that service and the rollback only has to undo it there.
+// Failures are logged rather than returned since the caller is already handling the original error.
+func (c *Context) RollbackPeer(ctx context.Context, id string, sessionID uint64) {
+	// Keep rolling back even if the request that triggered it has been canceled.
+	ctx = context.WithoutCancel(ctx)
+
+	if err := c.Service().RemovePeer(ctx, id); err != nil {
+		log.Error("Failed to roll back peer from service", "peer_id", id, "session_id", sessionID, "cause", err)
+	}
+
+	// Match on the peer ID as well to avoid deleting a record inserted by a concurrent handshake.
+	query := map[string]interface{}{
+		"id":      sessionID,
+		"peer_id": id,
+	}
+
+	if err := c.SessionStore().DeleteMany(query); err != nil {
+		log.Error("Failed to roll back session from database", "peer_id", id, "session_id", sessionID, "cause", err)
+	}
+
+	log.Info("Peer has been rolled back", "peer_id", id, "session_id", sessionID)
+}
+
+// RestartService replaces a service that stopped unexpectedly with a new instance of the configured service type,
+// since a stopped service cannot be started again, and re-adds the peers of the sessions in the database to it.
+// The new service runs until the node stops it, independently of the given context. It returns the context of the
+// new service, which is passed to its Wait.
+func (c *Context) RestartService(ctx context.Context, cfg *config.Config) (context.Context, error) {
+	// Bring down and clean up what is left of the stopped service, such as its config and PID files and network
+	// interface; it may fail since its process is gone.
+	if err := c.Service().Stop(); err != nil {
+		log.Warn("Failed to stop service", "cause", err)
+	}
+
+	if err := c.Service().Cleanup(); err != nil {
+		log.Warn("Failed to clean up service", "cause", err)
+	}
+
+	service, err := NewService(c.HomeDir(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.Setup(ctx); err != nil {
+		return nil, fmt.Errorf("setting up service: %w", err)
+	}
+
+	serviceCtx, err := service.Start(context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("starting service: %w", err)
+	}
+
+	c.SetService(service)
+
+	if err := c.restoreSessionPeers(ctx); err != nil {
+		return serviceCtx, fmt.Errorf("restoring session peers: %w", err)
+	}
+
+	return serviceCtx, nil
+}
+
+// restoreSessionPeers re-adds the peers of the sessions in the database to a restarted service using their stored
+// peer requests. The byte totals of each session become the base that the usage of the new peer is added to, and
+// are recorded before the peer is added so that the usage sync never sees the new peer with the old base.
+// Sessions whose peer cannot be re-added are logged and left to the session validation.
+func (c *Context) restoreSessionPeers(ctx context.Context) error {
+	items, err := c.SessionStore().Find(nil)
+	if err != nil {
+		return fmt.Errorf("retrieving sessions from database: %w", err)
+	}
+
+	for i := range items {
+		item := &items[i]
+		if IsReleasedPeerID(item.GetPeerID()) || strings.HasPrefix(item.GetPeerID(), importedPeerPrefix) {
+			continue
+		}
+
+		query := map[string]interface{}{
+			"id": item.GetID(),
+		}
+		updates := map[string]interface{}{
+			"rx_bytes_base": item.GetRxBytes().String(),
+			"tx_bytes_base": item.GetTxBytes().String(),
+		}
+
+		if _, err := c.SessionStore().FindOneAndUpdate(query, updates); err != nil {
+			return fmt.Errorf("updating byte bases of session %d in database: %w", item.GetID(), err)
+		}
+
+		id, data, err := c.Service().AddPeer(ctx, item.GetPeerRequest())
+		if err != nil {
+			log.Error("Failed to restore peer of session", "id", item.GetID(), "cause", err)
+
+			continue
+		}
+
+		metadata, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("encoding add-peer service response: %w", err)
+		}
+
+		updates = map[string]interface{}{
+			"peer_id":       id,
+			"peer_metadata": base64.StdEncoding.EncodeToString(metadata),
+		}
+
+		if _, err := c.SessionStore().FindOneAndUpdate(query, updates); err != nil {
+			return fmt.Errorf("updating peer of session %d in database: %w", item.GetID(), err)
+		}
+
+		log.Info("Peer of session has been restored", "id", item.GetID(), "peer_id", id)
+	}
 
 	return nil
 }
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sentinel-official/sentinel-go-sdk/types"
+	"github.com/sentinel-official/sentinel-go-sdk/wireguard"
+)
+
+// wireGuardPeerRequest returns the peer request of a new WireGuard key pair and the base64 public key.
+func wireGuardPeerRequest(t *testing.T) ([]byte, string) {
+	t.Helper()
+
+	key, err := wireguard.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("generating wireguard key: %v", err)
+	}
+
+	req := &wireguard.PeerRequest{PublicKey: key.Public()}
+
+	buf, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("encoding wireguard peer request: %v", err)
+	}
+
+	return buf, key.Public().String()
+}
+
+// uuidJSON returns the JSON encoding of a UUID, an array of its 16 bytes, whose bytes count up from start.
+func uuidJSON(start byte) string {
+	items := make([]string, 0, 16)
+	for i := byte(0); i < 16; i++ {
+		items = append(items, strconv.Itoa(int(start+i)))
+	}
+
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestPeerIDFromRequest(t *testing.T) {
+	var (
+		uuid1 = uuidJSON(0x01)
+		uuid2 = uuidJSON(0x11)
+	)
+
+	wgReq1, wgKey1 := wireGuardPeerRequest(t)
+	wgReq2, wgKey2 := wireGuardPeerRequest(t)
+
+	tests := []struct {
+		name        string
+		serviceType types.ServiceType
+		req1, req2  []byte
+		reencoded   []byte
+		want1       string
+		want2       string
+	}{
+		{
+			name:        "openvpn",
+			serviceType: types.ServiceTypeOpenVPN,
+			req1:        []byte(`{"uuid":` + uuid1 + `}`),
+			req2:        []byte(`{"uuid":` + uuid2 + `}`),
+			reencoded:   []byte(`{ "uuid" : ` + strings.ReplaceAll(uuid1, ",", ", ") + `, "extra": true }`),
+			want1:       "01020304-0506-0708-090a-0b0c0d0e0f10",
+			want2:       "11121314-1516-1718-191a-1b1c1d1e1f20",
+		},
+		{
+			name:        "v2ray",
+			serviceType: types.ServiceTypeV2Ray,
+			req1:        []byte(`{"uuid":` + uuid1 + `}`),
+			req2:        []byte(`{"uuid":` + uuid2 + `}`),
+			reencoded:   []byte(`{ "uuid" : ` + strings.ReplaceAll(uuid1, ",", ", ") + `, "extra": true }`),
+			want1:       "01020304-0506-0708-090a-0b0c0d0e0f10",
+			want2:       "11121314-1516-1718-191a-1b1c1d1e1f20",
+		},
+		{
+			name:        "wireguard",
+			serviceType: types.ServiceTypeWireGuard,
+			req1:        wgReq1,
+			req2:        wgReq2,
+			reencoded:   []byte(`{ "public_key" : "` + wgKey1 + `", "extra": true }`),
+			want1:       wgKey1,
+			want2:       wgKey2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id1, err := PeerIDFromRequest(tt.serviceType, tt.req1)
+			if err != nil {
+				t.Fatalf("deriving peer id: %v", err)
+			}
+
+			if id1 != tt.want1 {
+				t.Fatalf("expected peer id %q, got %q", tt.want1, id1)
+			}
+
+			// The same identity yields the same peer ID, however the request is encoded.
+			for _, req := range [][]byte{tt.req1, tt.reencoded} {
+				id, err := PeerIDFromRequest(tt.serviceType, req)
+				if err != nil {
+					t.Fatalf("deriving peer id again: %v", err)
+				}
+
+				if id != id1 {
+					t.Fatalf("expected stable peer id %q, got %q", id1, id)
+				}
+			}
+
+			// A different identity yields a different peer ID.
+			id2, err := PeerIDFromRequest(tt.serviceType, tt.req2)
+			if err != nil {
+				t.Fatalf("deriving peer id of other request: %v", err)
+			}
+
+			if id2 != tt.want2 || id2 == id1 {
+				t.Fatalf("expected distinct peer id %q, got %q", tt.want2, id2)
+			}
+		})
+	}
+}
+
+func TestPeerIDFromRequestInvalid(t *testing.T) {
+	zeroUUID := "[" + strings.TrimSuffix(strings.Repeat("0,", 16), ",") + "]"
+
+	tests := []struct {
+		name        string
+		serviceType types.ServiceType
+		req         []byte
+	}{
+		{"openvpn zero uuid", types.ServiceTypeOpenVPN, []byte(`{"uuid":` + zeroUUID + `}`)},
+		{"openvpn missing uuid", types.ServiceTypeOpenVPN, []byte(`{}`)},
+		{"openvpn malformed", types.ServiceTypeOpenVPN, []byte(`not json`)},
+		{"v2ray zero uuid", types.ServiceTypeV2Ray, []byte(`{"uuid":` + zeroUUID + `}`)},
+		{"v2ray malformed", types.ServiceTypeV2Ray, []byte(`{"uuid":"not-a-uuid"}`)},
+		{"wireguard missing public key", types.ServiceTypeWireGuard, []byte(`{}`)},
+		{"wireguard zero public key", types.ServiceTypeWireGuard, []byte(`{"public_key":"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}`)},
+		{"unsupported service type", types.ServiceTypeUnspecified, []byte(`{}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id, err := PeerIDFromRequest(tt.serviceType, tt.req); err == nil {
+				t.Fatalf("expected an error, got peer id %q", id)
+			}
+		})
+	}
+}
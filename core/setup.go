@@ -2,9 +2,18 @@ package core
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
-
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"cosmossdk.io/math"
+	cosmossdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/sentinel-official/sentinel-go-sdk/core"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/geoip"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
@@ -13,9 +22,12 @@ import (
 	"github.com/sentinel-official/sentinel-go-sdk/types"
 	"github.com/sentinel-official/sentinel-go-sdk/v2ray"
 	"github.com/sentinel-official/sentinel-go-sdk/wireguard"
+	sentinelhub "github.com/sentinel-official/sentinelhub/v12/types"
+	"gorm.io/gorm"
 
 	"github.com/sentinel-official/sentinel-dvpnx/config"
 	"github.com/sentinel-official/sentinel-dvpnx/database"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
 )
 
 // SetupAccAddr retrieves the account address for transactions and assigns it to the context.
@@ -44,14 +56,36 @@ func (c *Context) SetupAccAddr(ctx context.Context, cfg *config.Config) error {
 	return nil
 }
 
+// KeyNodeAddr returns the node address of the transaction key in the keyring, without querying the blockchain.
+func KeyNodeAddr(cfg *config.Config) (string, error) {
+	v, err := core.NewClientFromConfig(cfg.Config)
+	if err != nil {
+		return "", fmt.Errorf("creating client from config: %w", err)
+	}
+
+	addr, err := v.KeyAddr(cfg.Tx.GetFromName())
+	if err != nil {
+		return "", fmt.Errorf("getting addr for key %q: %w", cfg.Tx.GetFromName(), err)
+	}
+
+	return sentinelhub.NodeAddress(addr.Bytes()).String(), nil
+}
+
 // SetupClient initializes the SDK client with the given configuration and assigns it to the context.
 func (c *Context) SetupClient(cfg *config.Config) error {
+	// Template the resolved moniker into the memo, or the configured one if the moniker is not resolved yet.
+	memo := cfg.Node.GetTxMemo()
+	if moniker := c.Moniker(); moniker != "" {
+		memo = cfg.Node.TxMemoFor(moniker)
+	}
+
 	log.Info("Initializing blockchain client",
 		"keyring.backend", cfg.Keyring.GetBackend(),
 		"keyring.name", cfg.Keyring.GetName(),
 		"rpc.addr", cfg.RPC.GetAddr(),
 		"rpc.chain_id", cfg.RPC.GetChainID(),
 		"tx.from_name", cfg.Tx.GetFromName(),
+		"tx.memo", memo,
 	)
 
 	v, err := core.NewClientFromConfig(cfg.Config)
@@ -59,35 +93,130 @@ func (c *Context) SetupClient(cfg *config.Config) error {
 		return fmt.Errorf("creating client from config: %w", err)
 	}
 
+	// Attach the memo to every transaction broadcast by the client.
+	v.WithTxMemo(memo)
+
 	// Seal the client.
 	v.Seal()
 
 	// Assign the initialized client to the context.
 	c.WithClient(v)
 
+	// newClients creates a sealed client broadcasting with each of the gas prices.
+	newClients := func(items []cosmossdk.DecCoins) ([]*core.Client, error) {
+		clients := make([]*core.Client, 0, len(items))
+		for _, prices := range items {
+			v, err := core.NewClientFromConfig(cfg.Config)
+			if err != nil {
+				return nil, err //nolint:wrapcheck
+			}
+
+			v.WithTxGasPrices(prices).
+				WithTxMemo(memo).
+				Seal()
+
+			clients = append(clients, v)
+		}
+
+		return clients, nil
+	}
+
+	// Create a client for each fallback gas price, tried in order on insufficient fee errors.
+	fallbacks, err := newClients(cfg.TxFallback.GetGasPrices())
+	if err != nil {
+		return fmt.Errorf("creating fallback client from config: %w", err)
+	}
+
+	if len(fallbacks) > 0 {
+		log.Info("Initializing fallback gas prices", "tx_fallback.gas_prices", cfg.TxFallback.GasPrices)
+	}
+
+	c.WithFallbackClients(fallbacks)
+
+	// Create a client for each adjusted gas price, tried in order on tx timeouts, if enabled.
+	var adjusted []*core.Client
+	if factor := cfg.TxFallback.GetGasPriceFactor(); factor > 0 {
+		items := AdjustedGasPrices(cfg.Tx.GetGasPrices(), cfg.TxFallback.GetGasPriceCeiling(), factor)
+
+		adjusted, err = newClients(items)
+		if err != nil {
+			return fmt.Errorf("creating gas price client from config: %w", err)
+		}
+
+		log.Info("Initializing gas price adjustments",
+			"tx_fallback.gas_price_factor", factor,
+			"tx_fallback.gas_price_ceiling", cfg.TxFallback.GasPriceCeiling,
+			"adjustments", items,
+		)
+	}
+
+	c.WithGasPriceClients(adjusted)
+
 	return nil
 }
 
+// OpenDatabase opens the database of the configured driver: the database file in the home directory for SQLite,
+// or the database of the configured DSN for PostgreSQL.
+func (c *Context) OpenDatabase(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+	dsn := c.DatabaseFile()
+	if cfg.GetDriver() == config.DatabaseDriverPostgres {
+		dsn = cfg.GetDSN()
+	}
+
+	return database.NewDefault(cfg.GetDriver(), dsn) //nolint:wrapcheck
+}
+
 // SetupDatabase creates and configures the database, then assigns it to the context.
-func (c *Context) SetupDatabase(_ *config.Config) error {
-	log.Info("Initializing database", "file", c.DatabaseFile())
+func (c *Context) SetupDatabase(cfg *config.Config) error {
+	if cfg.Database.GetDriver() == config.DatabaseDriverSQLite {
+		log.Info("Initializing database", "driver", cfg.Database.GetDriver(), "file", c.DatabaseFile())
+	} else {
+		log.Info("Initializing database", "driver", cfg.Database.GetDriver())
+	}
 
-	db, err := database.NewDefault(c.DatabaseFile())
+	db, err := c.OpenDatabase(cfg.Database)
 	if err != nil {
-		return fmt.Errorf("initializing database %q: %w", c.DatabaseFile(), err)
+		return fmt.Errorf("initializing %s database: %w", cfg.Database.GetDriver(), err)
+	}
+
+	// Assign the database instance and the default stores to the context. The stores of a database shared by several
+	// nodes are scoped to the records of this node.
+	store := database.NewGormSessionStore(db)
+	speedtestStore := database.NewGormSpeedtestStore(db)
+
+	if database.IsShared(db) {
+		store = store.WithNodeAddr(c.NodeAddr().String())
+		speedtestStore = speedtestStore.WithNodeAddr(c.NodeAddr().String())
 	}
 
-	// Assign the database instance to the context.
 	c.WithDatabase(db)
+	c.WithSessionStore(store)
+	c.WithSpeedtestStore(speedtestStore)
 
 	return nil
 }
 
-// SetupGeoIPClient initializes the GeoIP client and assigns it to the context.
-func (c *Context) SetupGeoIPClient(_ *config.Config) error {
-	log.Info("Initializing GeoIP client")
+// SetupGeoIPClient initializes the GeoIP client of the configured provider and assigns it to the context.
+func (c *Context) SetupGeoIPClient(cfg *config.Config) error {
+	provider := cfg.GeoIP.GetProvider()
+	log.Info("Initializing GeoIP client", "provider", provider)
+
+	var v geoip.Client
 
-	v := geoip.NewDefaultClient()
+	switch provider {
+	case config.GeoIPProviderDefault:
+		v = geoip.NewDefaultClient()
+	case config.GeoIPProviderMaxMind:
+		// The local database cannot discover the public IP address, so the remote addresses are resolved instead.
+		client, err := NewMaxMindClient(cfg.GeoIP.GetMaxMindDBPath(), cfg.Node.GetRemoteAddrs())
+		if err != nil {
+			return fmt.Errorf("creating maxmind client: %w", err)
+		}
+
+		v = client
+	default:
+		return fmt.Errorf("unsupported provider %q", provider)
+	}
 
 	// Assign the GeoIP client to the context.
 	c.WithGeoIPClient(v)
@@ -95,6 +224,51 @@ func (c *Context) SetupGeoIPClient(_ *config.Config) error {
 	return nil
 }
 
+// monikerUnknownRegion replaces the region placeholder of the moniker when the GeoIP location cannot be resolved.
+const monikerUnknownRegion = "unknown"
+
+// SetupMoniker resolves the placeholders of the configured moniker and assigns it to the context. The region is the
+// country code of the location resolved by the GeoIP client, which must be set up first, or "unknown" if the
+// location cannot be resolved.
+func (c *Context) SetupMoniker(ctx context.Context, cfg *config.Config) error {
+	var hostname, region string
+
+	if cfg.Node.MonikerHasHostname() {
+		v, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("getting hostname: %w", err)
+		}
+
+		hostname = v
+	}
+
+	if cfg.Node.MonikerHasRegion() {
+		geoIPCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		// A GeoIP outage must not stop the node from starting, so the region falls back to a placeholder value.
+		loc, err := c.GeoIPClient().Get(geoIPCtx, "")
+		if err != nil {
+			log.Warn("Failed to get GeoIP location for the moniker region", "error", err, "region", monikerUnknownRegion)
+
+			region = monikerUnknownRegion
+		} else {
+			region = loc.CountryCode
+		}
+	}
+
+	moniker, err := cfg.Node.ResolveMoniker(hostname, region)
+	if err != nil {
+		return fmt.Errorf("resolving moniker: %w", err)
+	}
+
+	log.Info("Resolved moniker", "moniker", moniker)
+
+	c.WithMoniker(moniker)
+
+	return nil
+}
+
 // SetupOracleClient initializes the oracle client and assigns it to the context.
 func (c *Context) SetupOracleClient(cfg *config.Config) error {
 	var (
@@ -110,9 +284,19 @@ func (c *Context) SetupOracleClient(cfg *config.Config) error {
 
 	switch name {
 	case "coingecko":
-		client = oracle.NewCoinGeckoClient(cfg.Oracle.CoinGecko.GetAPIKey())
+		apiKey, err := cfg.Oracle.CoinGecko.ResolveAPIKey()
+		if err != nil {
+			return fmt.Errorf("resolving coingecko api_key: %w", err)
+		}
+
+		client = oracle.NewCoinGeckoClient(apiKey)
 	case "osmosis":
-		client = oracle.NewOsmosisClient(cfg.Oracle.Osmosis.GetAPIAddr())
+		apiAddr, err := cfg.Oracle.Osmosis.ResolveAPIAddr()
+		if err != nil {
+			return fmt.Errorf("resolving osmosis api_addr: %w", err)
+		}
+
+		client = oracle.NewOsmosisClient(apiAddr)
 	default:
 		return fmt.Errorf("unsupported name %q", name)
 	}
@@ -123,27 +307,193 @@ func (c *Context) SetupOracleClient(cfg *config.Config) error {
 	return nil
 }
 
-// SetupService determines the service type and configures it accordingly.
-func (c *Context) SetupService(ctx context.Context, cfg *config.Config) error {
-	var (
-		service     types.ServerService         // Interface for the node service
-		serviceType = cfg.Node.GetServiceType() // Get the service type from config
+// SetupOutboundNetwork restricts the default HTTP transport, which is shared by the RPC, oracle, GeoIP, and webhook
+// clients, to dial outbound connections over the configured network.
+func (c *Context) SetupOutboundNetwork(cfg *config.Config) error {
+	network := cfg.Node.GetOutboundNetwork()
+	if network == "tcp" {
+		return nil
+	}
+
+	log.Info("Initializing outbound network", "network", network)
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unsupported default transport type %T", http.DefaultTransport)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return nil
+}
+
+// SetupRPCTLS routes the requests to the RPC addresses through a copy of the default HTTP transport trusting the
+// configured CA bundle, or skipping the certificate verification if requested. It wraps the outbound network, so
+// that the RPC connections use it as well.
+func (c *Context) SetupRPCTLS(cfg *config.Config) error {
+	pool, err := cfg.RPCTLS.CertPool()
+	if err != nil {
+		return fmt.Errorf("loading rpc ca certificates: %w", err)
+	}
+
+	skipVerify := cfg.RPCTLS.GetInsecureSkipVerify()
+	if pool == nil && !skipVerify {
+		return nil
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unsupported default transport type %T", http.DefaultTransport)
+	}
+
+	log.Info("Initializing RPC TLS", "ca_cert_file", cfg.RPCTLS.GetCACertFile())
+
+	if skipVerify {
+		log.Warn("TLS certificate verification of the RPC addresses is DISABLED; use only for testing",
+			"rpc.addrs", cfg.RPC.GetAddrs(),
+		)
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: skipVerify, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            pool,
+	}
+
+	http.DefaultTransport = NewRPCTLSTransport(transport, cfg.RPC.GetAddrs(), tlsConfig)
+
+	return nil
+}
+
+// SetupRPCBackoff wraps the default HTTP transport, which is shared by the RPC clients, to back off the RPC
+// endpoints that rate limit the node, and assigns the backoff to the context.
+func (c *Context) SetupRPCBackoff(cfg *config.Config) error {
+	if !cfg.RateLimit.GetEnable() {
+		return nil
+	}
+
+	log.Info("Initializing RPC backoff",
+		"default_backoff", cfg.RateLimit.GetDefaultBackoff(), "max_backoff", cfg.RateLimit.GetMaxBackoff(),
 	)
 
-	log.Info("Initializing service", "type", serviceType)
+	v := NewRPCBackoff(cfg.RPC.GetAddrs(), cfg.RateLimit.GetDefaultBackoff(), cfg.RateLimit.GetMaxBackoff())
+	http.DefaultTransport = v.Transport(http.DefaultTransport)
+
+	// Assign the RPC backoff to the context.
+	c.WithRPCBackoff(v)
+
+	return nil
+}
 
+// SetupRPCFallback wraps the default HTTP transport, which is shared by the RPC clients, to fall back to the next
+// RPC address on connection errors, and assigns the fallback to the context. It wraps the RPC backoff, so that
+// requests to rate-limited RPC endpoints fall back as well.
+func (c *Context) SetupRPCFallback(cfg *config.Config) error {
+	log.Info("Initializing RPC fallback", "cooldown", cfg.Node.GetRPCFailoverCooldown())
+
+	v := NewRPCFallback(c.RPCAddrs, cfg.Node.GetRPCFailoverCooldown())
+	http.DefaultTransport = v.Transport(http.DefaultTransport)
+
+	// Assign the RPC fallback to the context.
+	c.WithRPCFallback(v)
+
+	return nil
+}
+
+// SetupWebhook initializes the webhook dispatcher and assigns it to the context.
+func (c *Context) SetupWebhook(cfg *config.Config) error {
+	url := cfg.Webhook.GetURL()
+	if !cfg.Webhook.GetEnabled() || url == "" {
+		return nil
+	}
+
+	log.Info("Initializing webhook dispatcher", "url", url)
+
+	v := webhook.NewDispatcher(url, cfg.Webhook.GetQueueSize()).
+		WithBatchSize(cfg.Webhook.GetBatchSize()).
+		WithFlushTimeout(cfg.Webhook.GetFlushTimeout()).
+		WithRetryAttempts(cfg.Webhook.GetRetryAttempts()).
+		WithRetryDelay(cfg.Webhook.GetRetryDelay()).
+		WithSecret(cfg.Webhook.GetSecret()).
+		WithSendInterval(cfg.Webhook.GetSendInterval()).
+		WithTimeout(cfg.Webhook.GetTimeout())
+
+	// Queue the events left undelivered at the previous shutdown if enabled.
+	if cfg.Webhook.GetPersistPending() {
+		v.WithPendingFile(filepath.Join(c.HomeDir(), "webhook_pending.json"))
+
+		if err := v.LoadPending(); err != nil {
+			return fmt.Errorf("loading pending webhook events: %w", err)
+		}
+	}
+
+	// Assign the webhook dispatcher to the context.
+	c.WithWebhook(v)
+
+	return nil
+}
+
+// NewService creates the server service of the configured service type in the home directory.
+func NewService(homeDir string, cfg *config.Config) (types.ServerService, error) {
 	// Initialize the appropriate server service based on the configured type
-	switch serviceType {
+	switch serviceType := cfg.Node.GetServiceType(); serviceType {
 	case types.ServiceTypeV2Ray:
-		service = v2ray.NewServer("v2ray", c.HomeDir(), cfg.Services[types.ServiceTypeV2Ray].(*v2ray.ServerConfig))
+		return v2ray.NewServer("v2ray", homeDir, cfg.Services[types.ServiceTypeV2Ray].(*v2ray.ServerConfig)), nil
 	case types.ServiceTypeWireGuard:
-		service = wireguard.NewServer("wireguard", c.HomeDir(), cfg.Services[types.ServiceTypeWireGuard].(*wireguard.ServerConfig))
+		return wireguard.NewServer("wireguard", homeDir, cfg.Services[types.ServiceTypeWireGuard].(*wireguard.ServerConfig)), nil
 	case types.ServiceTypeOpenVPN:
-		service = openvpn.NewServer("openvpn", c.HomeDir(), cfg.Services[types.ServiceTypeOpenVPN].(*openvpn.ServerConfig))
+		return openvpn.NewServer("openvpn", homeDir, cfg.Services[types.ServiceTypeOpenVPN].(*openvpn.ServerConfig)), nil
 	case types.ServiceTypeUnspecified:
-		return errors.New("unspecified service type")
+		return nil, errors.New("unspecified service type")
+	default:
+		return nil, fmt.Errorf("unsupported service type %q", serviceType)
+	}
+}
+
+// ServiceProtocols returns the protocols supported by the configured service type.
+// V2Ray protocols are formatted as proxy/transport/security for each inbound.
+func ServiceProtocols(cfg *config.Config) []string {
+	switch serviceType := cfg.Node.GetServiceType(); serviceType {
+	case types.ServiceTypeV2Ray:
+		inbounds := cfg.Services[types.ServiceTypeV2Ray].(*v2ray.ServerConfig).Inbounds
+
+		protocols := make([]string, 0, len(inbounds))
+		for _, v := range inbounds {
+			protocols = append(protocols, fmt.Sprintf("%s/%s/%s", v.ProxyProtocol, v.TransportProtocol, v.TransportSecurity))
+		}
+
+		return protocols
+	case types.ServiceTypeWireGuard:
+		return []string{"udp"}
+	case types.ServiceTypeOpenVPN:
+		return []string{cfg.Services[types.ServiceTypeOpenVPN].(*openvpn.ServerConfig).Protocol}
 	default:
-		return fmt.Errorf("unsupported service type %q", serviceType)
+		return nil
+	}
+}
+
+// SetupService determines the service type and configures it accordingly.
+func (c *Context) SetupService(ctx context.Context, cfg *config.Config) error {
+	serviceType := cfg.Node.GetServiceType()
+
+	log.Info("Initializing service", "type", serviceType)
+
+	service, err := NewService(c.HomeDir(), cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Checking service binaries")
+
+	if err := CheckServiceBinaries(serviceType); err != nil {
+		return fmt.Errorf("checking service %q binaries: %w", serviceType, err)
 	}
 
 	log.Info("Checking service status")
@@ -161,58 +511,197 @@ func (c *Context) SetupService(ctx context.Context, cfg *config.Config) error {
 		return err //nolint:wrapcheck
 	}
 
-	// Assign the service to the context
+	// Assign the service and its supported protocols to the context
+	c.WithProtocols(ServiceProtocols(cfg))
 	c.WithService(service)
 
 	return nil
 }
 
+// CheckServiceBinaries verifies that the executables required by the service type are installed.
+func CheckServiceBinaries(serviceType types.ServiceType) error {
+	item, ok := serviceBinaries[serviceType]
+	if !ok {
+		return nil
+	}
+
+	for _, name := range item.names {
+		if _, err := exec.LookPath(name); err != nil {
+			return fmt.Errorf("required executable %q not found, %s: %w", name, item.hint, err)
+		}
+	}
+
+	return nil
+}
+
 // Setup initializes all components of the node context.
 func (c *Context) Setup(ctx context.Context, cfg *config.Config) error {
+	// Ensure the maximum peers fits in the address pools of the service.
+	maxPeers, capacity, err := CheckMaxPeers(cfg)
+	if err != nil {
+		return fmt.Errorf("checking max peers: %w", err)
+	}
+
+	// Warn about prices that are implausible for the exponents of their denominations.
+	CheckPriceExponents(cfg)
+
+	// Warn about gas prices in a denomination other than the prices, as configured for another chain.
+	CheckGasPriceDenoms(cfg)
+
 	// Assign configuration values to the context.
+	c.WithAdminToken(cfg.Admin.GetToken())
 	c.WithAPIAddrs(cfg.Node.APIAddrs())
+	c.WithAPIHandshakeListenAddr(cfg.Node.APIHandshakeListenAddr())
 	c.WithAPIListenAddr(cfg.Node.APIListenAddr())
+	c.WithAPITLSEnable(cfg.Node.GetAPITLSEnable())
+	c.WithBlocklist(NewBlocklist(cfg.Blocklist.GetAccounts()).WithSource(cfg.Blocklist.GetURL(), cfg.Blocklist.GetPublicKey()))
+	c.WithCapabilities(cfg.Capabilities.GetSign(), cfg.Capabilities.GetCacheTTL())
+	c.WithDatabaseBusyRetry(cfg.Database.GetBusyRetryAttempts(), cfg.Database.GetBusyRetryDelay())
+	c.WithDrain(cfg.Drain.GetStrategy(), cfg.Drain.GetBatchSize(), cfg.Drain.GetBatchDelay())
+	c.WithDrainOnShutdown(cfg.Node.GetDrainOnShutdown())
+	c.WithDrainSyncUsage(cfg.Drain.GetSyncUsage())
+	c.WithDrainTimeout(cfg.Drain.GetTimeout())
+	c.WithExposeStartupTimings(cfg.Info.GetExposeStartupTimings())
 	c.WithGigabytePrices(cfg.Node.GetGigabytePrices())
 	c.WithHourlyPrices(cfg.Node.GetHourlyPrices())
-	c.WithMaxPeers(cfg.QoS.GetMaxPeers())
-	c.WithMoniker(cfg.Node.GetMoniker())
+	c.WithHumanReadableBytes(cfg.Display.GetHumanReadableBytes())
+	c.WithInfoCacheTTL(cfg.Info.GetCacheTTL())
+	c.WithMaxPeers(maxPeers)
+	c.WithMaxSessionsPerAccount(cfg.QoS.GetMaxSessionsPerAccount())
+	c.WithMinDeposit(cfg.QoS.GetMinDeposit())
+	c.WithMonthlyByteCap(cfg.QoS.GetMonthlyByteCap())
+	c.WithPeerCapacity(capacity)
+	c.WithPeerRequestReuse(cfg.QoS.GetPeerRequestReuse())
+	c.WithPersistState(cfg.Node.GetPersistState())
+	c.WithPing(cfg.Ping.GetEnable(), cfg.Ping.GetRecordLatency())
+	c.WithPlans(cfg.Plans)
+	c.WithPricingSchedule(cfg.PricingSchedule)
 	c.WithRemoteAddrs(cfg.Node.GetRemoteAddrs())
+	c.WithRemoteAddrsProbe(cfg.Node.GetRemoteAddrsProbe())
+	c.WithRemovePeersIfInactive(cfg.Node.GetRemovePeersIfInactive())
+	c.WithRequireAllocation(cfg.QoS.GetRequireAllocation())
 	c.WithRPCAddrs(cfg.RPC.GetAddrs())
+	c.WithSessionIdle(cfg.QoS.GetIdleRateThreshold(), cfg.QoS.GetIdleTimeout())
+	c.WithSessionUpdateBatchSize(cfg.Node.GetSessionUpdateBatchSize())
+	c.WithSessionWorkerConcurrency(cfg.Node.GetSessionWorkerConcurrency())
+	c.WithStaleSessions(cfg.Node.GetStaleSessions())
+	c.WithStartupJitterMax(cfg.Node.GetStartupJitterMax())
+	c.WithStaticSpeedtestResults(
+		math.NewIntFromUint64(cfg.Speedtest.GetStaticDLSpeed()),
+		math.NewIntFromUint64(cfg.Speedtest.GetStaticULSpeed()),
+	)
+	c.WithTunnel(cfg.Tunnel.Settings(cfg.Node.GetServiceType()))
+	c.WithTxFailureLog(cfg.Node.GetTxFailureLog())
+	c.WithUsageAnomaly(cfg.QoS.GetMaxPlausibleThroughputMultiplier(), cfg.QoS.GetUsageAnomalyAction())
+
+	// Derive the maximum peers from the measured upload speed if enabled.
+	if cfg.QoS.GetMaxPeersAuto() {
+		c.WithPeerBandwidth(math.NewIntFromUint64(cfg.QoS.GetPeerBandwidth()))
+	}
+
+	// Reject replayed handshake requests only if replay protection is enabled.
+	if cfg.Replay.GetEnable() {
+		c.WithReplayGuard(NewReplayGuard(cfg.Replay.GetWindow(), cfg.Replay.GetRequireTimestamp()))
+	}
+
+	// Record the timing of the scheduler workers only if the schedule is exposed.
+	if cfg.Scheduler.GetExposeSchedule() {
+		c.WithWorkerSchedule(NewWorkerSchedule())
+	}
+
+	// Record the duration of each setup phase to make slow steps visible.
+	timings := c.StartupTimings()
+
+	log.Info("Setting up outbound network")
+
+	if err := timings.Time("outbound_network", func() error { return c.SetupOutboundNetwork(cfg) }); err != nil {
+		return fmt.Errorf("setting up outbound network: %w", err)
+	}
+
+	log.Info("Setting up GeoIP client")
+
+	if err := timings.Time("geoip", func() error { return c.SetupGeoIPClient(cfg) }); err != nil {
+		return fmt.Errorf("setting up GeoIP client: %w", err)
+	}
+
+	log.Info("Setting up moniker")
+
+	if err := timings.Time("moniker", func() error { return c.SetupMoniker(ctx, cfg) }); err != nil {
+		return fmt.Errorf("setting up moniker: %w", err)
+	}
+
+	log.Info("Setting up RPC TLS")
+
+	if err := timings.Time("rpc_tls", func() error { return c.SetupRPCTLS(cfg) }); err != nil {
+		return fmt.Errorf("setting up RPC TLS: %w", err)
+	}
+
+	log.Info("Setting up RPC backoff")
+
+	if err := timings.Time("rpc_backoff", func() error { return c.SetupRPCBackoff(cfg) }); err != nil {
+		return fmt.Errorf("setting up RPC backoff: %w", err)
+	}
+
+	log.Info("Setting up RPC fallback")
+
+	if err := timings.Time("rpc_fallback", func() error { return c.SetupRPCFallback(cfg) }); err != nil {
+		return fmt.Errorf("setting up RPC fallback: %w", err)
+	}
 
 	log.Info("Setting up blockchain client")
 
-	if err := c.SetupClient(cfg); err != nil {
+	if err := timings.Time("client", func() error { return c.SetupClient(cfg) }); err != nil {
 		return fmt.Errorf("setting up client: %w", err)
 	}
 
+	if cfg.Node.GetCheckPriceDenoms() {
+		log.Info("Checking price denoms")
+
+		if err := timings.Time("price_denoms", func() error { return c.CheckPriceDenoms(ctx) }); err != nil {
+			return fmt.Errorf("checking price denoms: %w", err)
+		}
+	}
+
+	log.Info("Setting up account addr")
+
+	if err := timings.Time("acc_addr", func() error { return c.SetupAccAddr(ctx, cfg) }); err != nil {
+		return fmt.Errorf("setting up account addr: %w", err)
+	}
+
 	log.Info("Setting up database")
 
-	if err := c.SetupDatabase(cfg); err != nil {
+	if err := timings.Time("database", func() error { return c.SetupDatabase(cfg) }); err != nil {
 		return fmt.Errorf("setting up database: %w", err)
 	}
 
-	log.Info("Setting up GeoIP client")
+	log.Info("Loading persisted state")
 
-	if err := c.SetupGeoIPClient(cfg); err != nil {
-		return fmt.Errorf("setting up GeoIP client: %w", err)
+	if err := timings.Time("state", c.LoadState); err != nil {
+		return fmt.Errorf("loading persisted state: %w", err)
 	}
 
 	log.Info("Setting up oracle client")
 
-	if err := c.SetupOracleClient(cfg); err != nil {
+	if err := timings.Time("oracle", func() error { return c.SetupOracleClient(cfg) }); err != nil {
 		return fmt.Errorf("setting up oracle client: %w", err)
 	}
 
-	log.Info("Setting up service")
+	log.Info("Setting up webhook dispatcher")
 
-	if err := c.SetupService(ctx, cfg); err != nil {
-		return fmt.Errorf("setting up service: %w", err)
+	if err := timings.Time("webhook", func() error { return c.SetupWebhook(cfg) }); err != nil {
+		return fmt.Errorf("setting up webhook dispatcher: %w", err)
 	}
 
-	log.Info("Setting up account addr")
+	log.Info("Setting up event log")
 
-	if err := c.SetupAccAddr(ctx, cfg); err != nil {
-		return fmt.Errorf("setting up account addr: %w", err)
+	if err := timings.Time("event_log", func() error { return c.SetupEventLog(cfg) }); err != nil {
+		return fmt.Errorf("setting up event log: %w", err)
+	}
+
+	log.Info("Setting up service")
+
+	if err := timings.Time("service", func() error { return c.SetupService(ctx, cfg) }); err != nil {
+		return fmt.Errorf("setting up service: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// StartupPhase holds the duration of a single startup phase of the node.
+type StartupPhase struct {
+	Duration time.Duration // Duration of the phase.
+	Name     string        // Name of the phase.
+}
+
+// StartupTimings records the durations of the startup phases of the node in the order they ran.
+type StartupTimings struct {
+	mu     sync.RWMutex
+	phases []StartupPhase
+}
+
+// NewStartupTimings creates a new, empty StartupTimings.
+func NewStartupTimings() *StartupTimings {
+	return &StartupTimings{}
+}
+
+// Time runs the function as the named startup phase, then logs and records its duration.
+// The duration is recorded even if the function fails, so that the failing phase is visible.
+func (t *StartupTimings) Time(name string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	d := time.Since(start)
+
+	t.mu.Lock()
+	t.phases = append(t.phases, StartupPhase{Duration: d, Name: name})
+	t.mu.Unlock()
+
+	log.Info("Startup phase finished", "phase", name, "duration", d, "success", err == nil)
+
+	return err
+}
+
+// Phases returns a copy of the recorded startup phases in the order they ran.
+func (t *StartupTimings) Phases() []StartupPhase {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	items := make([]StartupPhase, len(t.phases))
+	copy(items, t.phases)
+
+	return items
+}
+
+// Total returns the sum of the durations of the recorded startup phases.
+func (t *StartupTimings) Total() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var total time.Duration
+	for _, phase := range t.phases {
+		total += phase.Duration
+	}
+
+	return total
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/geoip"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// nodeState represents the results of the GeoIP and speedtest workers persisted across restarts.
+type nodeState struct {
+	DLSpeed   string          `json:"dl_speed,omitempty"`
+	Location  *geoip.Location `json:"location,omitempty"`
+	ULSpeed   string          `json:"ul_speed,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// StateFile returns the path of the file holding the persisted state.
+func (c *Context) StateFile() string {
+	return filepath.Join(c.HomeDir(), "state.json")
+}
+
+// LoadState loads the persisted location and speedtest results into the context, if persisting is enabled.
+// A missing state file is not an error, and an unreadable one is logged and ignored.
+func (c *Context) LoadState() error {
+	if !c.PersistState() {
+		return nil
+	}
+
+	buf, err := os.ReadFile(c.StateFile())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("reading state file %q: %w", c.StateFile(), err)
+	}
+
+	var state nodeState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		log.Warn("Ignoring invalid state file", "file", c.StateFile(), "cause", err)
+
+		return nil
+	}
+
+	if state.Location != nil {
+		c.SetLocation(state.Location)
+	}
+
+	dlSpeed, okDL := math.NewIntFromString(state.DLSpeed)
+	ulSpeed, okUL := math.NewIntFromString(state.ULSpeed)
+
+	if okDL && okUL {
+		c.SetSpeedtestResults(dlSpeed, ulSpeed)
+	}
+
+	log.Info("Loaded persisted state", "file", c.StateFile(), "updated_at", state.UpdatedAt)
+
+	return nil
+}
+
+// SaveState writes the current location and speedtest results of the context to the state file.
+// The file is replaced atomically with a uniquely named temporary file so that neither a crash nor a concurrent
+// writer leaves a partially written state, and writers are serialized so that an older state never replaces a
+// newer one.
+func (c *Context) SaveState() error {
+	c.statem.Lock()
+	defer c.statem.Unlock()
+
+	dlSpeed, ulSpeed := c.SpeedtestResults()
+
+	state := nodeState{
+		Location:  c.Location(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if dlSpeed.IsPositive() || ulSpeed.IsPositive() {
+		state.DLSpeed = dlSpeed.String()
+		state.ULSpeed = ulSpeed.String()
+	}
+
+	buf, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+
+	file := c.StateFile()
+
+	f, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary state file: %w", err)
+	}
+
+	tmp := f.Name()
+	defer func() {
+		_ = os.Remove(tmp)
+	}()
+
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("writing state file %q: %w", tmp, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing state file %q: %w", tmp, err)
+	}
+
+	if err := os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("renaming state file %q: %w", tmp, err)
+	}
+
+	return nil
+}
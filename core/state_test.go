@@ -0,0 +1,63 @@
+package core
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/geoip"
+)
+
+func TestSaveStateConcurrent(t *testing.T) {
+	dir := t.TempDir()
+
+	c := NewContext().WithHomeDir(dir).WithPersistState(true)
+	c.SetLocation(&geoip.Location{City: "London", CountryCode: "GB"})
+	c.SetSpeedtestResults(math.NewInt(1000), math.NewInt(500))
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 16)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs <- c.SaveState()
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("saving state: %v", err)
+		}
+	}
+
+	// Only the state file is left behind, without temporary files.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading home directory: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Name() != "state.json" {
+		t.Fatalf("expected only the state file, got %v", entries)
+	}
+
+	loaded := NewContext().WithHomeDir(dir).WithPersistState(true)
+	if err := loaded.LoadState(); err != nil {
+		t.Fatalf("loading state: %v", err)
+	}
+
+	if loc := loaded.Location(); loc == nil || loc.City != "London" || loc.CountryCode != "GB" {
+		t.Fatalf("unexpected loaded location %+v", loc)
+	}
+
+	if dlSpeed, ulSpeed := loaded.SpeedtestResults(); !dlSpeed.Equal(math.NewInt(1000)) || !ulSpeed.Equal(math.NewInt(500)) {
+		t.Fatalf("unexpected loaded speedtest results %s, %s", dlSpeed, ulSpeed)
+	}
+}
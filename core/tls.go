@@ -0,0 +1,101 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/crypto"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// pkiFiles lists the PKI and TLS files generated in the home directory.
+var pkiFiles = []string{"ca.crt", "ca.key", "ca.rl", "tls.crt", "tls.key"}
+
+// InitPKI initializes the PKI in the given directory and issues a TLS certificate for the remote addresses.
+func InitPKI(dir string, remoteAddrs []string) error {
+	log.Info("Initializing PKI with CA certificate and key", "dir", dir)
+
+	pki := crypto.NewPKI(dir)
+	if err := pki.Init(); err != nil {
+		return fmt.Errorf("initializing PKI: %w", err)
+	}
+
+	opts := []crypto.CertOption{
+		crypto.CertSAN(remoteAddrs...),
+	}
+
+	log.Info("Issuing certificate and key", "name", "tls")
+
+	if _, _, err := pki.Issue("tls", opts...); err != nil {
+		return fmt.Errorf("issuing TLS certificate and key: %w", err)
+	}
+
+	return nil
+}
+
+// ResetTLS regenerates the PKI and TLS certificate in the home directory for the remote addresses.
+// The new material is generated and validated in a staging directory before it replaces the existing files,
+// which are left untouched on failure.
+func ResetTLS(homeDir string, remoteAddrs []string) error {
+	// Create a staging directory for the new material
+	stagingDir, err := os.MkdirTemp(homeDir, ".pki-")
+	if err != nil {
+		return fmt.Errorf("creating staging directory in %q: %w", homeDir, err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(stagingDir)
+	}()
+
+	if err := InitPKI(stagingDir, remoteAddrs); err != nil {
+		return err
+	}
+
+	log.Info("Validating TLS certificate and key", "remote_addrs", remoteAddrs)
+
+	if err := ValidateTLS(stagingDir, remoteAddrs); err != nil {
+		return fmt.Errorf("validating TLS material: %w", err)
+	}
+
+	// Replace the existing material with the new files
+	for _, name := range pkiFiles {
+		file := filepath.Join(homeDir, name)
+
+		log.Info("Replacing file", "file", file)
+
+		if err := os.Rename(filepath.Join(stagingDir, name), file); err != nil {
+			return fmt.Errorf("replacing file %q: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateTLS verifies that the TLS key pair in the given directory is valid, unexpired, and covers the remote addresses.
+func ValidateTLS(dir string, remoteAddrs []string) error {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"))
+	if err != nil {
+		return fmt.Errorf("loading TLS certificate and key: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parsing TLS certificate: %w", err)
+	}
+
+	if time.Now().After(cert.NotAfter) {
+		return fmt.Errorf("TLS certificate expired at %s", cert.NotAfter)
+	}
+
+	for _, addr := range remoteAddrs {
+		if err := cert.VerifyHostname(addr); err != nil {
+			return fmt.Errorf("verifying TLS certificate for remote addr %q: %w", addr, err)
+		}
+	}
+
+	return nil
+}
@@ -3,13 +3,89 @@ package core
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"cosmossdk.io/math"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
 )
 
+// IsInsufficientFeeErr checks if the error message indicates that the fees of a transaction were too low.
+func IsInsufficientFeeErr(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(err.Error()), "insufficient fee")
+}
+
+// maxTxErrLogLen is the maximum length of the raw log of a failed transaction included in the returned error.
+const maxTxErrLogLen = 256
+
+// truncateTxLog returns the raw log of a transaction cut to maxTxErrLogLen bytes.
+func truncateTxLog(s string) string {
+	if len(s) <= maxTxErrLogLen {
+		return s
+	}
+
+	return s[:maxTxErrLogLen] + "..."
+}
+
+// maxGasPriceAdjustments is the maximum number of gas price adjustments reached after timed out transactions.
+const maxGasPriceAdjustments = 10
+
+// IsTxTimeoutErr checks if the error message indicates that a transaction was not found in a block in time.
+func IsTxTimeoutErr(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(err.Error()), "querying tx failed")
+}
+
+// AdjustedGasPrices returns the gas prices multiplied by the factor in turn, up to maxGasPriceAdjustments times.
+// The amount of each denomination stops at its amount in the ceiling, and denominations absent from the ceiling
+// are not adjusted. The adjustments end once every amount reached its ceiling.
+func AdjustedGasPrices(prices, ceiling types.DecCoins, factor float64) []types.DecCoins {
+	f, err := math.LegacyNewDecFromStr(strconv.FormatFloat(factor, 'f', -1, 64))
+	if err != nil {
+		return nil
+	}
+
+	var items []types.DecCoins
+
+	current := prices
+	for i := 0; i < maxGasPriceAdjustments; i++ {
+		next := make(types.DecCoins, 0, len(current))
+
+		for _, coin := range current {
+			amount := coin.Amount
+			if limit := ceiling.AmountOf(coin.Denom); amount.LT(limit) {
+				amount = math.LegacyMinDec(amount.Mul(f), limit)
+			}
+
+			next = append(next, types.NewDecCoinFromDec(coin.Denom, amount))
+		}
+
+		if next.IsEqual(current) {
+			break
+		}
+
+		items = append(items, next)
+		current = next
+	}
+
+	return items
+}
+
 // BroadcastTx safely broadcasts a transaction with the provided messages.
 // It locks the transaction mutex to ensure only one transaction is broadcast at a time.
+// A transaction rejected for insufficient fees is broadcast again with each fallback gas price in turn. A
+// transaction timing out before being included in a block is not broadcast again, since the mempool already
+// accepted it; the next transaction is broadcast with the next adjusted gas price instead, and each transaction
+// included in a block lowers the gas price of the next one by one adjustment.
 func (c *Context) BroadcastTx(ctx context.Context, msgs ...types.Msg) error {
 	c.txm.Lock()
 	defer c.txm.Unlock()
@@ -19,10 +95,39 @@ func (c *Context) BroadcastTx(ctx context.Context, msgs ...types.Msg) error {
 		return nil
 	}
 
+	fallbacks := c.FallbackClients()
+
+	// Use the gas price adjustment reached by the previous transactions.
+	adjusted := c.GasPriceClients()
+	level := min(c.gasPriceLevel, len(adjusted))
+
+	client := c.Client()
+	if level > 0 {
+		client = adjusted[level-1]
+	}
+
 	// Broadcast the transaction and wait for it to be included in a block.
-	txResp, txRes, err := c.Client().BroadcastTxCommit(ctx, msgs...)
+	txResp, txRes, err := client.BroadcastTxCommit(ctx, msgs...)
+	for i := 0; IsInsufficientFeeErr(err) && i < len(fallbacks); i++ {
+		log.Warn("Retrying transaction with fallback gas prices", "fallback", i+1, "cause", err)
+
+		txResp, txRes, err = fallbacks[i].BroadcastTxCommit(ctx, msgs...)
+		if err == nil {
+			log.Info("Transaction broadcasted with fallback gas prices", "fallback", i+1, "hash", txResp.Hash)
+		}
+	}
+
+	switch {
+	case IsTxTimeoutErr(err) && level < len(adjusted):
+		c.gasPriceLevel = level + 1
+		log.Warn("Raising gas prices of the next transaction", "adjustment", c.gasPriceLevel, "cause", err)
+	case err == nil && level > 0:
+		c.gasPriceLevel = level - 1
+		log.Info("Lowering gas prices of the next transaction", "adjustment", c.gasPriceLevel)
+	}
+
 	if err != nil {
-		return fmt.Errorf("broadcasting tx commit: %w", err)
+		return c.broadcastTxErr(txResp, txRes, len(msgs), err)
 	}
 
 	log.Debug(
@@ -36,3 +141,40 @@ func (c *Context) BroadcastTx(ctx context.Context, msgs ...types.Msg) error {
 
 	return nil
 }
+
+// broadcastTxErr returns the error of a failed broadcast. A transaction rejected by the mempool or failing in the
+// block carries the code, codespace, and raw log of the chain, which are logged in full at error level if enabled
+// and returned with the raw log truncated. Other errors are returned wrapped.
+func (c *Context) broadcastTxErr(txResp *coretypes.ResultBroadcastTx, txRes *coretypes.ResultTx, msgs int, err error) error {
+	var (
+		stage     string
+		code      uint32
+		codespace string
+		hash      string
+		rawLog    string
+	)
+
+	switch {
+	case txRes != nil && !txRes.TxResult.IsOK():
+		stage, code, codespace, rawLog = "tx failed", txRes.TxResult.Code, txRes.TxResult.Codespace, txRes.TxResult.Log
+		hash = txRes.Hash.String()
+	case txResp != nil && txResp.Code != 0:
+		stage, code, codespace, rawLog = "tx rejected by mempool", txResp.Code, txResp.Codespace, txResp.Log
+		hash = txResp.Hash.String()
+	default:
+		return fmt.Errorf("broadcasting tx commit: %w", err)
+	}
+
+	if c.TxFailureLog() {
+		log.Error("Transaction failed",
+			"stage", stage,
+			"code", code,
+			"codespace", codespace,
+			"hash", hash,
+			"log", rawLog,
+			"msgs", msgs,
+		)
+	}
+
+	return fmt.Errorf("broadcasting tx commit: %s: code=%s/%d, log=%s", stage, codespace, code, truncateTxLog(rawLog))
+}
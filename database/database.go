@@ -1,31 +1,50 @@
 package database
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"github.com/sentinel-official/sentinel-dvpnx/config"
 	"github.com/sentinel-official/sentinel-dvpnx/database/models"
 )
 
-// New initializes a new database connection with the specified file path and configuration.
-// It also performs migrations to ensure the database schema is up to date with the models.
-func New(file string, cfg *gorm.Config) (*gorm.DB, error) {
-	// Build the SQLite DSN
-	dsn := file + "?_busy_timeout=5000&_journal_mode=WAL"
+// New initializes a new database connection with the specified driver, data source name, and configuration.
+// The data source name of the SQLite driver is the path of the database file. It also performs migrations to
+// ensure the database schema is up to date with the models.
+func New(driver, dsn string, cfg *gorm.Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
 
-	// Open a database connection using the provided filepath and configuration.
-	db, err := gorm.Open(sqlite.Open(dsn), cfg)
+	switch driver {
+	case config.DatabaseDriverPostgres:
+		dialector = postgres.Open(dsn)
+	case config.DatabaseDriverSQLite:
+		// Build the SQLite DSN
+		dialector = sqlite.Open(dsn + "?_busy_timeout=5000&_journal_mode=WAL")
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+
+	// Open a database connection using the provided dialector and configuration. The DSN of the PostgreSQL
+	// driver is left out of the error since it may contain credentials.
+	db, err := gorm.Open(dialector, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("opening database file %q: %w", file, err)
+		if driver == config.DatabaseDriverSQLite {
+			return nil, fmt.Errorf("opening database file %q: %w", dsn, err)
+		}
+
+		return nil, fmt.Errorf("opening %s database: %w", driver, err)
 	}
 
 	// List of models to be migrated.
-	items := []interface{}{
-		&models.Session{},
-	}
+	items := migratedModels()
 
 	// Run migrations to apply the schema of the models to the database.
 	if err := db.AutoMigrate(items...); err != nil {
@@ -36,8 +55,18 @@ func New(file string, cfg *gorm.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
+// migratedModels returns the models whose schema is migrated to the database.
+func migratedModels() []interface{} {
+	return []interface{}{
+		&models.AccountUsage{},
+		&models.Session{},
+		&models.SessionEvent{},
+		&models.Speedtest{},
+	}
+}
+
 // NewDefault uses default configuration settings and calls the New function to initialize the database.
-func NewDefault(file string) (*gorm.DB, error) {
+func NewDefault(driver, dsn string) (*gorm.DB, error) {
 	// Define default GORM configuration settings.
 	cfg := gorm.Config{
 		Logger:         logger.Discard,
@@ -46,5 +75,133 @@ func NewDefault(file string) (*gorm.DB, error) {
 	}
 
 	// Call New with the default configuration.
-	return New(file, &cfg)
+	return New(driver, dsn, &cfg)
+}
+
+// CheckIntegrity runs the SQLite integrity check on the database and returns an error describing any problems found.
+// Databases of other drivers, which check their own integrity, are only checked to be reachable.
+func CheckIntegrity(db *gorm.DB) error {
+	if db.Dialector.Name() != config.DatabaseDriverSQLite {
+		v, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("getting sql database: %w", err)
+		}
+
+		if err := v.Ping(); err != nil {
+			return fmt.Errorf("pinging database: %w", err)
+		}
+
+		return nil
+	}
+
+	var results []string
+	if err := db.Raw("PRAGMA integrity_check").Scan(&results).Error; err != nil {
+		return fmt.Errorf("running integrity check: %w", err)
+	}
+
+	if len(results) != 1 || results[0] != "ok" {
+		return fmt.Errorf("integrity check failed: %v", results)
+	}
+
+	return nil
+}
+
+// IsShared reports whether the database may be shared by several nodes, which is the case for every driver except
+// SQLite, whose database file belongs to a single node.
+func IsShared(db *gorm.DB) bool {
+	return db.Dialector.Name() != config.DatabaseDriverSQLite
+}
+
+// busyErrMessages are the messages of the SQLite errors reported while the database is busy or locked. The error
+// is matched on its message so that the SQLite driver, which requires cgo, is not imported here.
+var busyErrMessages = []string{
+	"database is locked",
+	"database table is locked",
+}
+
+// busyErrCodes are the codes of the PostgreSQL errors reported when a transaction conflicts with a concurrent one
+// and can be retried: serialization_failure, deadlock_detected, and lock_not_available.
+var busyErrCodes = []string{
+	"40001",
+	"40P01",
+	"55P03",
+}
+
+// IsBusyError reports whether the error was caused by the database being busy or locked.
+func IsBusyError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return slices.Contains(busyErrCodes, pgErr.Code)
+	}
+
+	msg := err.Error()
+	for _, v := range busyErrMessages {
+		if strings.Contains(msg, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Vacuum rebuilds the SQLite database file, or the tables of a PostgreSQL database, to reclaim the space left by
+// deleted rows.
+func Vacuum(db *gorm.DB) error {
+	if err := db.Exec("VACUUM").Error; err != nil {
+		return fmt.Errorf("running vacuum: %w", err)
+	}
+
+	return nil
+}
+
+// Wipe deletes all the records of the migrated models from the database, leaving the schema in place.
+func Wipe(db *gorm.DB) error {
+	fn := func(tx *gorm.DB) error {
+		tx = tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+
+		for _, item := range migratedModels() {
+			if err := tx.Delete(item).Error; err != nil {
+				return fmt.Errorf("deleting records of %T: %w", item, err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := db.Transaction(fn); err != nil {
+		return fmt.Errorf("running tx: %w", err)
+	}
+
+	return nil
+}
+
+// WipeNode deletes the session, session event, account usage, and speed test records of the node address from a
+// database shared by several nodes, leaving the records of the other nodes in place.
+func WipeNode(db *gorm.DB, nodeAddr string) error {
+	fn := func(tx *gorm.DB) error {
+		ids := tx.Model(&models.Session{}).Select("id").Where("node_addr = ?", nodeAddr)
+
+		query := tx.Where("node_addr = ? OR session_id IN (?)", nodeAddr, ids)
+		if err := query.Delete(&models.SessionEvent{}).Error; err != nil {
+			return fmt.Errorf("deleting records of %T: %w", &models.SessionEvent{}, err)
+		}
+
+		for _, item := range []interface{}{&models.Session{}, &models.AccountUsage{}, &models.Speedtest{}} {
+			if err := tx.Where("node_addr = ?", nodeAddr).Delete(item).Error; err != nil {
+				return fmt.Errorf("deleting records of %T: %w", item, err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := db.Transaction(fn); err != nil {
+		return fmt.Errorf("running tx: %w", err)
+	}
+
+	return nil
 }
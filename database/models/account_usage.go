@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// AccountUsage represents the bytes served to an account by a node in a calendar month in the database. Unlike
+// the session records, it is kept when sessions are deleted, so that it covers every byte served in the month.
+// The bytes are stored as an integer, which holds far more than a node serves in a month, so that the database
+// can sum them.
+type AccountUsage struct {
+	ID        uint64    `gorm:"column:id;primaryKey;autoIncrement"` // Unique identifier for the record
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`   // Timestamp when the record was created
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`   // Timestamp when the record was last updated
+
+	AccAddr  string `gorm:"column:acc_addr;not null;uniqueIndex:idx_account_usage"`  // Account address the bytes were served to
+	Month    string `gorm:"column:month;not null;uniqueIndex:idx_account_usage"`     // Calendar month in UTC formatted as YYYY-MM
+	NodeAddr string `gorm:"column:node_addr;not null;uniqueIndex:idx_account_usage"` // Address of the node that served the bytes
+
+	Bytes    int64 `gorm:"column:bytes;not null;default:0"`    // Total rx and tx bytes served in the month
+	Sessions int64 `gorm:"column:sessions;not null;default:0"` // Number of sessions started in the month
+}
+
+// UsageMonth returns the calendar month of the time in UTC in the format of the Month field.
+func UsageMonth(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// NewAccountUsage creates and returns a new instance of the AccountUsage struct for the account and node addresses
+// in the calendar month of the time.
+func NewAccountUsage(accAddr, nodeAddr string, t time.Time) *AccountUsage {
+	return &AccountUsage{
+		AccAddr:  accAddr,
+		Month:    UsageMonth(t),
+		NodeAddr: nodeAddr,
+	}
+}
+
+// WithBytes sets the Bytes field and returns the updated AccountUsage instance.
+func (u *AccountUsage) WithBytes(v int64) *AccountUsage {
+	u.Bytes = v
+
+	return u
+}
+
+// WithSessions sets the Sessions field and returns the updated AccountUsage instance.
+func (u *AccountUsage) WithSessions(v int64) *AccountUsage {
+	u.Sessions = v
+
+	return u
+}
@@ -30,10 +30,15 @@ type Session struct {
 	PeerMetadata string `gorm:"column:peer_metadata;not null"`            // Peer metadata (could be JSON or another format)
 	PeerRequest  string `gorm:"column:peer_request;not null;uniqueIndex"` // Unique peer request for the session, indexed and cannot be null
 
-	Duration  time.Duration `gorm:"column:duration;not null"`  // Duration of the session in nanoseconds
-	RxBytes   string        `gorm:"column:rx_bytes;not null"`  // Rx bytes represented as a string
-	Signature string        `gorm:"column:signature;not null"` // Signature associated with the session
-	TxBytes   string        `gorm:"column:tx_bytes;not null"`  // Tx bytes represented as a string
+	Duration        time.Duration `gorm:"column:duration;not null"`                    // Duration of the session in nanoseconds
+	IdleDuration    time.Duration `gorm:"column:idle_duration;not null;default:0"`     // Continuous duration the session has stayed below the idle rate threshold in nanoseconds
+	LastSyncedBytes string        `gorm:"column:last_synced_bytes;not null;default:0"` // Total bytes confirmed on the blockchain represented as a string
+	RxBytes         string        `gorm:"column:rx_bytes;not null"`                    // Rx bytes represented as a string
+	RxBytesBase     string        `gorm:"column:rx_bytes_base;not null;default:0"`     // Rx bytes carried over from a previous node instance represented as a string
+	Signature       string        `gorm:"column:signature;not null"`                   // Signature associated with the session
+	TxBytes         string        `gorm:"column:tx_bytes;not null"`                    // Tx bytes represented as a string
+	TxBytesBase     string        `gorm:"column:tx_bytes_base;not null;default:0"`     // Tx bytes carried over from a previous node instance represented as a string
+	UsageSyncedAt   *time.Time    `gorm:"column:usage_synced_at"`                      // Timestamp when the rx and tx bytes were last synced from the peer statistics, nil until the first sync
 }
 
 // NewSession creates and returns a new instance of the Session struct with default values.
@@ -62,6 +67,20 @@ func (s *Session) WithID(v uint64) *Session {
 	return s
 }
 
+// WithIdleDuration sets the IdleDuration field from time.Duration and returns the updated Session instance.
+func (s *Session) WithIdleDuration(v time.Duration) *Session {
+	s.IdleDuration = v
+
+	return s
+}
+
+// WithLastSyncedBytes sets the LastSyncedBytes field from math.Int and returns the updated Session instance.
+func (s *Session) WithLastSyncedBytes(v math.Int) *Session {
+	s.LastSyncedBytes = v.String()
+
+	return s
+}
+
 // WithMaxBytes sets the MaxBytes field from math.Int and returns the updated Session instance.
 func (s *Session) WithMaxBytes(v math.Int) *Session {
 	s.MaxBytes = v.String()
@@ -111,6 +130,13 @@ func (s *Session) WithRxBytes(v math.Int) *Session {
 	return s
 }
 
+// WithRxBytesBase sets the RxBytesBase field from math.Int and returns the updated Session instance.
+func (s *Session) WithRxBytesBase(v math.Int) *Session {
+	s.RxBytesBase = v.String()
+
+	return s
+}
+
 // WithServiceType sets the ServiceType field and returns the updated Session instance.
 func (s *Session) WithServiceType(v sentinelsdk.ServiceType) *Session {
 	s.ServiceType = v.String()
@@ -132,6 +158,13 @@ func (s *Session) WithTxBytes(v math.Int) *Session {
 	return s
 }
 
+// WithTxBytesBase sets the TxBytesBase field from math.Int and returns the updated Session instance.
+func (s *Session) WithTxBytesBase(v math.Int) *Session {
+	s.TxBytesBase = v.String()
+
+	return s
+}
+
 // GetAccAddr returns the AccAddr field as cosmossdk.AccAddress.
 func (s *Session) GetAccAddr() cosmossdk.AccAddress {
 	addr, err := cosmossdk.AccAddressFromBech32(s.AccAddr)
@@ -152,6 +185,21 @@ func (s *Session) GetID() uint64 {
 	return s.ID
 }
 
+// GetIdleDuration returns the IdleDuration field as time.Duration.
+func (s *Session) GetIdleDuration() time.Duration {
+	return s.IdleDuration
+}
+
+// GetLastSyncedBytes returns the LastSyncedBytes field as math.Int.
+func (s *Session) GetLastSyncedBytes() math.Int {
+	v, ok := math.NewIntFromString(s.LastSyncedBytes)
+	if !ok {
+		panic(fmt.Errorf("parsing last_synced_bytes %q", s.LastSyncedBytes))
+	}
+
+	return v
+}
+
 // GetMaxBytes returns the MaxBytes field as math.Int.
 func (s *Session) GetMaxBytes() math.Int {
 	v, ok := math.NewIntFromString(s.MaxBytes)
@@ -212,6 +260,16 @@ func (s *Session) GetRxBytes() math.Int {
 	return v
 }
 
+// GetRxBytesBase returns the RxBytesBase field as math.Int.
+func (s *Session) GetRxBytesBase() math.Int {
+	v, ok := math.NewIntFromString(s.RxBytesBase)
+	if !ok {
+		panic(fmt.Errorf("parsing rx_bytes_base %q", s.RxBytesBase))
+	}
+
+	return v
+}
+
 // GetServiceType returns the ServiceType field as sentinelsdk.ServiceType.
 func (s *Session) GetServiceType() sentinelsdk.ServiceType {
 	return sentinelsdk.ServiceTypeFromString(s.ServiceType)
@@ -249,6 +307,26 @@ func (s *Session) GetTxBytes() math.Int {
 	return v
 }
 
+// GetTxBytesBase returns the TxBytesBase field as math.Int.
+func (s *Session) GetTxBytesBase() math.Int {
+	v, ok := math.NewIntFromString(s.TxBytesBase)
+	if !ok {
+		panic(fmt.Errorf("parsing tx_bytes_base %q", s.TxBytesBase))
+	}
+
+	return v
+}
+
+// GetUsageSyncedAt returns the UsageSyncedAt field, or the CreatedAt field if the usage was never synced, since the
+// session starts without usage.
+func (s *Session) GetUsageSyncedAt() time.Time {
+	if s.UsageSyncedAt == nil {
+		return s.CreatedAt
+	}
+
+	return *s.UsageSyncedAt
+}
+
 // BeforeUpdate is a GORM hook that updates the Duration field if relevant fields change.
 func (s *Session) BeforeUpdate(db *gorm.DB) (err error) {
 	if s.ID == 0 {
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+)
+
+// Session event types recorded in the database.
+const (
+	SessionEventTypeConnect    = "connect"    // Peer has been added to the service.
+	SessionEventTypeSnapshot   = "snapshot"   // Periodic snapshot of the session usage.
+	SessionEventTypeDisconnect = "disconnect" // Peer has been removed from the service.
+)
+
+// SessionEvent represents a session event record in the database.
+type SessionEvent struct {
+	ID        uint64    `gorm:"column:id;primaryKey;autoIncrement"` // Unique identifier for the event
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`   // Timestamp when the event occurred
+
+	EventType string `gorm:"column:event_type;not null"`                                              // Type of the event
+	NodeAddr  string `gorm:"column:node_addr;index:idx_session_events_node_addr;not null;default:''"` // Address of the node associated with the session of the event
+	RxBytes   string `gorm:"column:rx_bytes;not null"`                                                // Rx bytes at the time of the event represented as a string
+	SessionID uint64 `gorm:"column:session_id;index:idx_session_id;not null"`                         // Identifier of the session associated with the event
+	TxBytes   string `gorm:"column:tx_bytes;not null"`                                                // Tx bytes at the time of the event represented as a string
+}
+
+// NewSessionEvent creates and returns a new instance of the SessionEvent struct with default values.
+func NewSessionEvent() *SessionEvent {
+	return &SessionEvent{}
+}
+
+// WithEventType sets the EventType field and returns the updated SessionEvent instance.
+func (e *SessionEvent) WithEventType(v string) *SessionEvent {
+	e.EventType = v
+
+	return e
+}
+
+// WithNodeAddr sets the NodeAddr field and returns the updated SessionEvent instance.
+func (e *SessionEvent) WithNodeAddr(v string) *SessionEvent {
+	e.NodeAddr = v
+
+	return e
+}
+
+// WithRxBytes sets the RxBytes field from math.Int and returns the updated SessionEvent instance.
+func (e *SessionEvent) WithRxBytes(v math.Int) *SessionEvent {
+	e.RxBytes = v.String()
+
+	return e
+}
+
+// WithSessionID sets the SessionID field and returns the updated SessionEvent instance.
+func (e *SessionEvent) WithSessionID(v uint64) *SessionEvent {
+	e.SessionID = v
+
+	return e
+}
+
+// WithTxBytes sets the TxBytes field from math.Int and returns the updated SessionEvent instance.
+func (e *SessionEvent) WithTxBytes(v math.Int) *SessionEvent {
+	e.TxBytes = v.String()
+
+	return e
+}
+
+// GetCreatedAt returns the CreatedAt field.
+func (e *SessionEvent) GetCreatedAt() time.Time {
+	return e.CreatedAt
+}
+
+// GetEventType returns the EventType field.
+func (e *SessionEvent) GetEventType() string {
+	return e.EventType
+}
+
+// GetNodeAddr returns the NodeAddr field.
+func (e *SessionEvent) GetNodeAddr() string {
+	return e.NodeAddr
+}
+
+// GetRxBytes returns the RxBytes field as math.Int.
+func (e *SessionEvent) GetRxBytes() math.Int {
+	v, ok := math.NewIntFromString(e.RxBytes)
+	if !ok {
+		panic(fmt.Errorf("parsing rx_bytes %q", e.RxBytes))
+	}
+
+	return v
+}
+
+// GetSessionID returns the SessionID field.
+func (e *SessionEvent) GetSessionID() uint64 {
+	return e.SessionID
+}
+
+// GetTxBytes returns the TxBytes field as math.Int.
+func (e *SessionEvent) GetTxBytes() math.Int {
+	v, ok := math.NewIntFromString(e.TxBytes)
+	if !ok {
+		panic(fmt.Errorf("parsing tx_bytes %q", e.TxBytes))
+	}
+
+	return v
+}
+
+// NewSessionEventFromSession creates a SessionEvent of the given type from the usage of the session.
+func NewSessionEventFromSession(v *Session, eventType string) *SessionEvent {
+	return NewSessionEvent().
+		WithEventType(eventType).
+		WithNodeAddr(v.NodeAddr).
+		WithRxBytes(v.GetRxBytes()).
+		WithSessionID(v.GetID()).
+		WithTxBytes(v.GetTxBytes())
+}
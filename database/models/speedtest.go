@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+	sentinelhub "github.com/sentinel-official/sentinelhub/v12/types"
+)
+
+// Speedtest represents a speed test result record in the database.
+type Speedtest struct {
+	ID        uint64    `gorm:"column:id;primaryKey;autoIncrement"`                    // Unique identifier for the result
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;index:idx_created_at"` // Timestamp when the speed test finished
+
+	DownloadBytesPerSec string `gorm:"column:download_bytes_per_sec;not null"`                              // Measured download speed represented as a string
+	NodeAddr            string `gorm:"column:node_addr;index:idx_speedtests_node_addr;not null;default:''"` // Address of the node that ran the speed test
+	UploadBytesPerSec   string `gorm:"column:upload_bytes_per_sec;not null"`                                // Measured upload speed represented as a string
+}
+
+// NewSpeedtest creates and returns a new instance of the Speedtest struct with default values.
+func NewSpeedtest() *Speedtest {
+	return &Speedtest{}
+}
+
+// WithDownloadBytesPerSec sets the DownloadBytesPerSec field from math.Int and returns the updated Speedtest instance.
+func (s *Speedtest) WithDownloadBytesPerSec(v math.Int) *Speedtest {
+	s.DownloadBytesPerSec = v.String()
+
+	return s
+}
+
+// WithNodeAddr sets the NodeAddr field from sentinelhub.NodeAddress and returns the updated Speedtest instance.
+func (s *Speedtest) WithNodeAddr(v sentinelhub.NodeAddress) *Speedtest {
+	s.NodeAddr = v.String()
+
+	return s
+}
+
+// WithUploadBytesPerSec sets the UploadBytesPerSec field from math.Int and returns the updated Speedtest instance.
+func (s *Speedtest) WithUploadBytesPerSec(v math.Int) *Speedtest {
+	s.UploadBytesPerSec = v.String()
+
+	return s
+}
+
+// GetCreatedAt returns the CreatedAt field.
+func (s *Speedtest) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// GetDownloadBytesPerSec returns the DownloadBytesPerSec field as math.Int.
+func (s *Speedtest) GetDownloadBytesPerSec() math.Int {
+	v, ok := math.NewIntFromString(s.DownloadBytesPerSec)
+	if !ok {
+		panic(fmt.Errorf("parsing download_bytes_per_sec %q", s.DownloadBytesPerSec))
+	}
+
+	return v
+}
+
+// GetUploadBytesPerSec returns the UploadBytesPerSec field as math.Int.
+func (s *Speedtest) GetUploadBytesPerSec() math.Int {
+	v, ok := math.NewIntFromString(s.UploadBytesPerSec)
+	if !ok {
+		panic(fmt.Errorf("parsing upload_bytes_per_sec %q", s.UploadBytesPerSec))
+	}
+
+	return v
+}
@@ -0,0 +1,71 @@
+package operations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// AccountUsageAdd adds the bytes and sessions of the usage to the AccountUsage record of its account, node, and
+// month, inserting the record if none exists yet.
+func AccountUsageAdd(db *gorm.DB, usage *models.AccountUsage) error {
+	fn := func(db *gorm.DB) error {
+		onConflict := clause.OnConflict{
+			Columns: []clause.Column{{Name: "acc_addr"}, {Name: "month"}, {Name: "node_addr"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"bytes":      gorm.Expr("account_usages.bytes + ?", usage.Bytes),
+				"sessions":   gorm.Expr("account_usages.sessions + ?", usage.Sessions),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			}),
+		}
+
+		if err := db.Clauses(onConflict).Create(usage).Error; err != nil {
+			return fmt.Errorf("adding account usage: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := db.Transaction(fn); err != nil {
+		return fmt.Errorf("running tx: %w", err)
+	}
+
+	return nil
+}
+
+// AccountUsageSumBytes sums the bytes of the AccountUsage records matching the query in the month.
+func AccountUsageSumBytes(db *gorm.DB, query map[string]interface{}, month string) (total int64, err error) {
+	db = applyQuery(db.Model(&models.AccountUsage{}), query).Where("month = ?", month)
+	if err := db.Select("COALESCE(SUM(bytes), 0)").Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("summing account usage bytes of month %q: %w", month, err)
+	}
+
+	return total, nil
+}
+
+// AccountUsageTotal represents the total bytes and sessions of the AccountUsage records of an account.
+type AccountUsageTotal struct {
+	AccAddr    string
+	Sessions   int64
+	TotalBytes int64
+}
+
+// AccountUsageAggregate sums the bytes and sessions of the AccountUsage records matching the query by account
+// address and returns at most limit accounts, ordered from the largest to the smallest total of bytes.
+func AccountUsageAggregate(
+	db *gorm.DB, query map[string]interface{}, limit int,
+) (items []*AccountUsageTotal, err error) {
+	db = applyQuery(db.Model(&models.AccountUsage{}), query).
+		Select("acc_addr, SUM(bytes) AS total_bytes, SUM(sessions) AS sessions").
+		Group("acc_addr").
+		Order("total_bytes DESC, acc_addr ASC").
+		Limit(limit)
+	if err := db.Scan(&items).Error; err != nil {
+		return nil, fmt.Errorf("aggregating account usage: %w", err)
+	}
+
+	return items, nil
+}
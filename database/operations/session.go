@@ -3,19 +3,26 @@ package operations
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/sentinel-official/sentinel-dvpnx/database/models"
 )
 
-// SessionInsertOne inserts a single Session record into the database.
+// SessionInsertOne inserts a single Session record into the database and counts it in the AccountUsage record of
+// its account in the month it was created.
 func SessionInsertOne(db *gorm.DB, session *models.Session) error {
 	fn := func(db *gorm.DB) error {
 		if err := db.Create(session).Error; err != nil {
 			return fmt.Errorf("inserting session: %w", err)
 		}
 
+		usage := models.NewAccountUsage(session.AccAddr, session.NodeAddr, session.CreatedAt).WithSessions(1)
+		if err := AccountUsageAdd(db, usage); err != nil {
+			return fmt.Errorf("adding account usage of session: %w", err)
+		}
+
 		return nil
 	}
 
@@ -67,6 +74,62 @@ func SessionFind(db *gorm.DB, query map[string]interface{}) (sessions []models.S
 	return sessions, nil
 }
 
+// SessionFindPaginated retrieves at most limit session records matching the provided query, skipping the first
+// offset records in the provided order. The order must be deterministic, such as by id, for the pages to be
+// consistent.
+func SessionFindPaginated(
+	db *gorm.DB, query map[string]interface{}, limit, offset int, orderBy string,
+) (sessions []models.Session, err error) {
+	db = applyQuery(db, query)
+	if err := db.Order(orderBy).Limit(limit).Offset(offset).Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("finding sessions with query %v, limit %d and offset %d: %w", query, limit, offset, err)
+	}
+
+	return sessions, nil
+}
+
+// SessionFindStale retrieves the session records whose node address differs from the provided node address.
+func SessionFindStale(db *gorm.DB, nodeAddr string) (sessions []models.Session, err error) {
+	if err := db.Where("node_addr <> ?", nodeAddr).Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("finding sessions with node_addr other than %q: %w", nodeAddr, err)
+	}
+
+	return sessions, nil
+}
+
+// SessionFindCreatedBefore retrieves the session records matching the query created before the provided time.
+func SessionFindCreatedBefore(
+	db *gorm.DB, query map[string]interface{}, t time.Time,
+) (sessions []models.Session, err error) {
+	if err := applyQuery(db, query).Where("created_at < ?", t).Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("finding sessions created before %s: %w", t.Format(time.RFC3339), err)
+	}
+
+	return sessions, nil
+}
+
+// SessionFindByPeerIDPrefix retrieves the session records matching the query whose peer ID starts with the provided
+// prefix.
+func SessionFindByPeerIDPrefix(
+	db *gorm.DB, query map[string]interface{}, prefix string,
+) (sessions []models.Session, err error) {
+	if err := applyQuery(db, query).Where("peer_id LIKE ?", prefix+"%").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("finding sessions with peer_id prefix %q: %w", prefix, err)
+	}
+
+	return sessions, nil
+}
+
+// SessionCount counts the session records in the database matching the provided query.
+func SessionCount(db *gorm.DB, query map[string]interface{}) (count int64, err error) {
+	db = applyQuery(db, query)
+	if err := db.Model(&models.Session{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("counting sessions with query %v: %w", query, err)
+	}
+
+	return count, nil
+}
+
 // SessionFindOneAndUpdate finds a single session record based on the provided query and updates it with the provided updates.
 func SessionFindOneAndUpdate(db *gorm.DB, query, updates map[string]interface{}) (session *models.Session, err error) {
 	fn := func(db *gorm.DB) error {
@@ -93,6 +156,39 @@ func SessionFindOneAndUpdate(db *gorm.DB, query, updates map[string]interface{})
 	return session, nil
 }
 
+// SessionFindOneAndUpdateUsage updates a single session record matching the query like SessionFindOneAndUpdate, and
+// adds the bytes served since its previous update to the AccountUsage record of its account in the month, in the
+// same transaction.
+func SessionFindOneAndUpdateUsage(
+	db *gorm.DB, query, updates map[string]interface{}, bytes int64, month string,
+) (session *models.Session, err error) {
+	fn := func(db *gorm.DB) error {
+		session, err = SessionFindOneAndUpdate(db, query, updates)
+		if err != nil {
+			return err
+		}
+
+		if session == nil || bytes <= 0 {
+			return nil
+		}
+
+		usage := &models.AccountUsage{
+			AccAddr:  session.AccAddr,
+			Bytes:    bytes,
+			Month:    month,
+			NodeAddr: session.NodeAddr,
+		}
+
+		return AccountUsageAdd(db, usage)
+	}
+
+	if err := db.Transaction(fn); err != nil {
+		return nil, fmt.Errorf("running tx: %w", err)
+	}
+
+	return session, nil
+}
+
 // SessionUpdateMany updates multiple session records based on the provided query and updates them with the provided updates.
 func SessionUpdateMany(db *gorm.DB, query map[string]interface{}, updates map[string]interface{}) error {
 	fn := func(db *gorm.DB) error {
@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// SessionEventInsertOne inserts a single SessionEvent record into the database.
+func SessionEventInsertOne(db *gorm.DB, event *models.SessionEvent) error {
+	fn := func(db *gorm.DB) error {
+		if err := db.Create(event).Error; err != nil {
+			return fmt.Errorf("inserting session event: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := db.Transaction(fn); err != nil {
+		return fmt.Errorf("running tx: %w", err)
+	}
+
+	return nil
+}
+
+// SessionEventFind retrieves multiple session event records from the database based on the provided query,
+// ordered by their creation time.
+func SessionEventFind(db *gorm.DB, query map[string]interface{}) (events []models.SessionEvent, err error) {
+	db = applyQuery(db, query)
+	if err := db.Order("created_at ASC").Order("id ASC").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("finding session events with query %v: %w", query, err)
+	}
+
+	return events, nil
+}
+
+// SessionEventDeleteOrphaned deletes the session event records created before the provided time whose session record
+// no longer exists, and returns the number of deleted records.
+func SessionEventDeleteOrphaned(db *gorm.DB, t time.Time) (count int64, err error) {
+	fn := func(db *gorm.DB) error {
+		ids := db.Model(&models.Session{}).Select("id")
+
+		res := db.Where("created_at < ? AND session_id NOT IN (?)", t, ids).Delete(&models.SessionEvent{})
+		if res.Error != nil {
+			return fmt.Errorf("deleting orphaned session events created before %s: %w", t.Format(time.RFC3339), res.Error)
+		}
+
+		count = res.RowsAffected
+
+		return nil
+	}
+
+	if err := db.Transaction(fn); err != nil {
+		return 0, fmt.Errorf("running tx: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,37 @@
+package operations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// SpeedtestInsertOne inserts a single Speedtest record into the database.
+func SpeedtestInsertOne(db *gorm.DB, speedtest *models.Speedtest) error {
+	fn := func(db *gorm.DB) error {
+		if err := db.Create(speedtest).Error; err != nil {
+			return fmt.Errorf("inserting speedtest: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := db.Transaction(fn); err != nil {
+		return fmt.Errorf("running tx: %w", err)
+	}
+
+	return nil
+}
+
+// SpeedtestFindRecent retrieves up to limit of the most recent speedtest records matching the query from the
+// database, ordered from the newest to the oldest.
+func SpeedtestFindRecent(db *gorm.DB, query map[string]interface{}, limit int) (speedtests []models.Speedtest, err error) {
+	db = applyQuery(db, query)
+	if err := db.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&speedtests).Error; err != nil {
+		return nil, fmt.Errorf("finding %d recent speedtest(s) with query %v: %w", limit, query, err)
+	}
+
+	return speedtests, nil
+}
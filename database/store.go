@@ -0,0 +1,251 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+	"github.com/sentinel-official/sentinel-dvpnx/database/operations"
+)
+
+// SessionStore defines the storage backend for session records.
+type SessionStore interface {
+	// InsertOne inserts a single session record and counts it in the usage of its account.
+	InsertOne(session *models.Session) error
+	// InsertMany inserts multiple session records.
+	InsertMany(sessions []models.Session) error
+	// FindOne retrieves a single session record matching the query, or nil if none exists.
+	FindOne(query map[string]interface{}) (*models.Session, error)
+	// Find retrieves all session records matching the query.
+	Find(query map[string]interface{}) ([]models.Session, error)
+	// FindPaginated retrieves at most limit session records matching the query, skipping the first offset records
+	// in the given order.
+	FindPaginated(query map[string]interface{}, limit, offset int, orderBy string) ([]models.Session, error)
+	// FindStale retrieves the session records whose node address differs from the node address.
+	FindStale(nodeAddr string) ([]models.Session, error)
+	// FindCreatedBefore retrieves the session records created before t.
+	FindCreatedBefore(t time.Time) ([]models.Session, error)
+	// FindByPeerIDPrefix retrieves the session records whose peer ID starts with the prefix.
+	FindByPeerIDPrefix(prefix string) ([]models.Session, error)
+	// Count counts the session records matching the query.
+	Count(query map[string]interface{}) (int64, error)
+	// FindOneAndUpdate updates a single session record matching the query and returns it, or nil if none exists.
+	FindOneAndUpdate(query, updates map[string]interface{}) (*models.Session, error)
+	// FindOneAndUpdateUsage updates a single session record matching the query and returns it, or nil if none
+	// exists, adding the bytes served since its previous update to the usage of its account in the month of t.
+	FindOneAndUpdateUsage(query, updates map[string]interface{}, bytes int64, t time.Time) (*models.Session, error)
+	// SumUsageBytes sums the bytes served in the calendar month of t, including those of deleted sessions.
+	SumUsageBytes(t time.Time) (int64, error)
+	// TopAccountsByUsage retrieves at most limit accounts with the most bytes served over all months, including
+	// those of deleted sessions, largest first.
+	TopAccountsByUsage(limit int) ([]*operations.AccountUsageTotal, error)
+	// UpdateMany updates all session records matching the query.
+	UpdateMany(query, updates map[string]interface{}) error
+	// FindOneAndDelete deletes a single session record matching the query and returns it, or nil if none exists.
+	FindOneAndDelete(query map[string]interface{}) (*models.Session, error)
+	// DeleteMany deletes all session records matching the query.
+	DeleteMany(query map[string]interface{}) error
+	// FindEvents retrieves the session event records matching the query, ordered by their creation time.
+	FindEvents(query map[string]interface{}) ([]models.SessionEvent, error)
+	// DeleteOrphanedEvents deletes the session events created before t whose session record no longer exists, and
+	// returns the number of deleted events.
+	DeleteOrphanedEvents(t time.Time) (int64, error)
+}
+
+// SpeedtestStore defines the storage backend for speed test records.
+type SpeedtestStore interface {
+	// InsertOne inserts a single speed test record.
+	InsertOne(speedtest *models.Speedtest) error
+	// FindRecent retrieves at most limit of the most recent speed test records, newest first.
+	FindRecent(limit int) ([]models.Speedtest, error)
+}
+
+var _ SessionStore = (*GormSessionStore)(nil)
+
+// GormSessionStore is a SessionStore backed by a GORM database.
+type GormSessionStore struct {
+	db       *gorm.DB
+	nodeAddr string
+}
+
+// NewGormSessionStore creates a new GormSessionStore using the given database connection.
+func NewGormSessionStore(db *gorm.DB) *GormSessionStore {
+	return &GormSessionStore{db: db}
+}
+
+// WithNodeAddr scopes every read, update, and delete of the store to the session records of the node address, so
+// that several nodes can share one database. It returns the updated store.
+func (s *GormSessionStore) WithNodeAddr(addr string) *GormSessionStore {
+	s.nodeAddr = addr
+
+	return s
+}
+
+// OwnsNodeAddr returns whether the records of the node address belong to the store, which is the case for every
+// node address if the store is not scoped.
+func (s *GormSessionStore) OwnsNodeAddr(addr string) bool {
+	return s.nodeAddr == "" || s.nodeAddr == addr
+}
+
+// scoped returns a copy of the query restricted to the node address of the store, or the query itself if the
+// store is not scoped.
+func (s *GormSessionStore) scoped(query map[string]interface{}) map[string]interface{} {
+	return scopeQuery(query, s.nodeAddr)
+}
+
+// scopeQuery returns a copy of the query restricted to the node address, or the query itself if the node address
+// is empty.
+func scopeQuery(query map[string]interface{}, nodeAddr string) map[string]interface{} {
+	if nodeAddr == "" {
+		return query
+	}
+
+	m := make(map[string]interface{}, len(query)+1)
+	for k, v := range query {
+		m[k] = v
+	}
+
+	m["node_addr"] = nodeAddr
+
+	return m
+}
+
+// InsertOne inserts a single session record and counts it in the usage of its account.
+func (s *GormSessionStore) InsertOne(session *models.Session) error {
+	return operations.SessionInsertOne(s.db, session) //nolint:wrapcheck
+}
+
+// InsertMany inserts multiple session records.
+func (s *GormSessionStore) InsertMany(sessions []models.Session) error {
+	return operations.SessionInsertMany(s.db, sessions) //nolint:wrapcheck
+}
+
+// FindOne retrieves a single session record matching the query, or nil if none exists.
+func (s *GormSessionStore) FindOne(query map[string]interface{}) (*models.Session, error) {
+	return operations.SessionFindOne(s.db, s.scoped(query)) //nolint:wrapcheck
+}
+
+// Find retrieves all session records matching the query.
+func (s *GormSessionStore) Find(query map[string]interface{}) ([]models.Session, error) {
+	return operations.SessionFind(s.db, s.scoped(query)) //nolint:wrapcheck
+}
+
+// FindPaginated retrieves at most limit session records matching the query, skipping the first offset records
+// in the given order.
+func (s *GormSessionStore) FindPaginated(
+	query map[string]interface{}, limit, offset int, orderBy string,
+) ([]models.Session, error) {
+	return operations.SessionFindPaginated(s.db, s.scoped(query), limit, offset, orderBy) //nolint:wrapcheck
+}
+
+// FindStale retrieves the session records whose node address differs from the node address. A store scoped to a
+// node address has no stale sessions, since the sessions of other node addresses belong to the nodes sharing the
+// database.
+func (s *GormSessionStore) FindStale(nodeAddr string) ([]models.Session, error) {
+	if s.nodeAddr != "" {
+		return nil, nil
+	}
+
+	return operations.SessionFindStale(s.db, nodeAddr) //nolint:wrapcheck
+}
+
+// FindCreatedBefore retrieves the session records created before t.
+func (s *GormSessionStore) FindCreatedBefore(t time.Time) ([]models.Session, error) {
+	return operations.SessionFindCreatedBefore(s.db, s.scoped(nil), t) //nolint:wrapcheck
+}
+
+// FindByPeerIDPrefix retrieves the session records whose peer ID starts with the prefix.
+func (s *GormSessionStore) FindByPeerIDPrefix(prefix string) ([]models.Session, error) {
+	return operations.SessionFindByPeerIDPrefix(s.db, s.scoped(nil), prefix) //nolint:wrapcheck
+}
+
+// Count counts the session records matching the query.
+func (s *GormSessionStore) Count(query map[string]interface{}) (int64, error) {
+	return operations.SessionCount(s.db, s.scoped(query)) //nolint:wrapcheck
+}
+
+// FindOneAndUpdate updates a single session record matching the query and returns it, or nil if none exists.
+func (s *GormSessionStore) FindOneAndUpdate(query, updates map[string]interface{}) (*models.Session, error) {
+	return operations.SessionFindOneAndUpdate(s.db, s.scoped(query), updates) //nolint:wrapcheck
+}
+
+// FindOneAndUpdateUsage updates a single session record matching the query and returns it, or nil if none exists,
+// adding the bytes served since its previous update to the usage of its account in the month of t.
+func (s *GormSessionStore) FindOneAndUpdateUsage(
+	query, updates map[string]interface{}, bytes int64, t time.Time,
+) (*models.Session, error) {
+	return operations.SessionFindOneAndUpdateUsage( //nolint:wrapcheck
+		s.db, s.scoped(query), updates, bytes, models.UsageMonth(t),
+	)
+}
+
+// SumUsageBytes sums the bytes served in the calendar month of t, including those of deleted sessions.
+func (s *GormSessionStore) SumUsageBytes(t time.Time) (int64, error) {
+	return operations.AccountUsageSumBytes(s.db, s.scoped(nil), models.UsageMonth(t)) //nolint:wrapcheck
+}
+
+// TopAccountsByUsage retrieves at most limit accounts with the most bytes served over all months, including those
+// of deleted sessions, largest first.
+func (s *GormSessionStore) TopAccountsByUsage(limit int) ([]*operations.AccountUsageTotal, error) {
+	return operations.AccountUsageAggregate(s.db, s.scoped(nil), limit) //nolint:wrapcheck
+}
+
+// UpdateMany updates all session records matching the query.
+func (s *GormSessionStore) UpdateMany(query, updates map[string]interface{}) error {
+	return operations.SessionUpdateMany(s.db, s.scoped(query), updates) //nolint:wrapcheck
+}
+
+// FindOneAndDelete deletes a single session record matching the query and returns it, or nil if none exists.
+func (s *GormSessionStore) FindOneAndDelete(query map[string]interface{}) (*models.Session, error) {
+	return operations.SessionFindOneAndDelete(s.db, s.scoped(query)) //nolint:wrapcheck
+}
+
+// DeleteMany deletes all session records matching the query.
+func (s *GormSessionStore) DeleteMany(query map[string]interface{}) error {
+	return operations.SessionDeleteMany(s.db, s.scoped(query)) //nolint:wrapcheck
+}
+
+// FindEvents retrieves the session event records matching the query, ordered by their creation time.
+func (s *GormSessionStore) FindEvents(query map[string]interface{}) ([]models.SessionEvent, error) {
+	return operations.SessionEventFind(s.db, s.scoped(query)) //nolint:wrapcheck
+}
+
+// DeleteOrphanedEvents deletes the session events created before t whose session record no longer exists, and
+// returns the number of deleted events. The orphaned events are deleted regardless of their node address, since
+// no node sharing the database has their session record anymore, which includes the events recorded before the
+// events carried a node address.
+func (s *GormSessionStore) DeleteOrphanedEvents(t time.Time) (int64, error) {
+	return operations.SessionEventDeleteOrphaned(s.db, t) //nolint:wrapcheck
+}
+
+var _ SpeedtestStore = (*GormSpeedtestStore)(nil)
+
+// GormSpeedtestStore is a SpeedtestStore backed by a GORM database.
+type GormSpeedtestStore struct {
+	db       *gorm.DB
+	nodeAddr string
+}
+
+// NewGormSpeedtestStore creates a new GormSpeedtestStore using the given database connection.
+func NewGormSpeedtestStore(db *gorm.DB) *GormSpeedtestStore {
+	return &GormSpeedtestStore{db: db}
+}
+
+// WithNodeAddr scopes every read of the store to the speed test records of the node address, so that several nodes
+// can share one database. It returns the updated store.
+func (s *GormSpeedtestStore) WithNodeAddr(addr string) *GormSpeedtestStore {
+	s.nodeAddr = addr
+
+	return s
+}
+
+// InsertOne inserts a single speed test record.
+func (s *GormSpeedtestStore) InsertOne(speedtest *models.Speedtest) error {
+	return operations.SpeedtestInsertOne(s.db, speedtest) //nolint:wrapcheck
+}
+
+// FindRecent retrieves at most limit of the most recent speed test records, newest first.
+func (s *GormSpeedtestStore) FindRecent(limit int) ([]models.Speedtest, error) {
+	return operations.SpeedtestFindRecent(s.db, scopeQuery(nil, s.nodeAddr), limit) //nolint:wrapcheck
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+)
+
+// newTestSessionStore opens a migrated SQLite database in a temporary directory and returns a store using it.
+func newTestSessionStore(t *testing.T) *GormSessionStore {
+	t.Helper()
+
+	db, err := NewDefault(config.DatabaseDriverSQLite, filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+
+	return NewGormSessionStore(db)
+}
+
+func TestGormSessionStoreRetention(t *testing.T) {
+	s := newTestSessionStore(t)
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	sessions := []models.Session{
+		{ID: 1, PeerID: "peer-1", PeerRequest: "req-1", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: 2, PeerID: "peer-2", PeerRequest: "req-2", CreatedAt: now},
+	}
+	if err := s.db.Create(&sessions).Error; err != nil {
+		t.Fatalf("inserting sessions: %v", err)
+	}
+
+	items, err := s.FindCreatedBefore(before)
+	if err != nil {
+		t.Fatalf("finding sessions created before: %v", err)
+	}
+
+	if len(items) != 1 || items[0].ID != 1 {
+		t.Fatalf("expected only session 1 to be expired, got %+v", items)
+	}
+
+	// Events of session 3 are orphaned, since the session record does not exist.
+	events := []models.SessionEvent{
+		{SessionID: 1, CreatedAt: now.Add(-2 * time.Hour)},
+		{SessionID: 3, CreatedAt: now.Add(-2 * time.Hour)},
+		{SessionID: 3, CreatedAt: now},
+	}
+	if err := s.db.Create(&events).Error; err != nil {
+		t.Fatalf("inserting session events: %v", err)
+	}
+
+	purged, err := s.DeleteOrphanedEvents(before)
+	if err != nil {
+		t.Fatalf("deleting orphaned events: %v", err)
+	}
+
+	if purged != 1 {
+		t.Fatalf("expected one expired orphaned event to be deleted, got %d", purged)
+	}
+
+	var remaining []models.SessionEvent
+	if err := s.db.Order("id ASC").Find(&remaining).Error; err != nil {
+		t.Fatalf("finding remaining events: %v", err)
+	}
+
+	if len(remaining) != 2 || remaining[0].SessionID != 1 || remaining[1].SessionID != 3 {
+		t.Fatalf("expected the event of session 1 and the recent orphaned event to remain, got %+v", remaining)
+	}
+}
+
+func TestGormStoresScopedByNodeAddr(t *testing.T) {
+	s := newTestSessionStore(t)
+
+	sessions := []models.Session{
+		{ID: 1, NodeAddr: "node-a", PeerID: "peer-1", PeerRequest: "req-1"},
+		{ID: 2, NodeAddr: "node-b", PeerID: "peer-2", PeerRequest: "req-2"},
+	}
+	if err := s.db.Create(&sessions).Error; err != nil {
+		t.Fatalf("inserting sessions: %v", err)
+	}
+
+	events := []models.SessionEvent{
+		*models.NewSessionEventFromSession(&models.Session{ID: 1, NodeAddr: "node-a", RxBytes: "0", TxBytes: "0"}, models.SessionEventTypeConnect),
+		*models.NewSessionEventFromSession(&models.Session{ID: 2, NodeAddr: "node-b", RxBytes: "0", TxBytes: "0"}, models.SessionEventTypeConnect),
+	}
+	if err := s.db.Create(&events).Error; err != nil {
+		t.Fatalf("inserting session events: %v", err)
+	}
+
+	speedtests := []models.Speedtest{
+		{NodeAddr: "node-a", DownloadBytesPerSec: "1", UploadBytesPerSec: "1"},
+		{NodeAddr: "node-b", DownloadBytesPerSec: "2", UploadBytesPerSec: "2"},
+	}
+	if err := s.db.Create(&speedtests).Error; err != nil {
+		t.Fatalf("inserting speedtests: %v", err)
+	}
+
+	scoped := NewGormSessionStore(s.db).WithNodeAddr("node-a")
+
+	items, err := scoped.Find(nil)
+	if err != nil {
+		t.Fatalf("finding sessions: %v", err)
+	}
+
+	if len(items) != 1 || items[0].ID != 1 {
+		t.Fatalf("expected only the session of node-a, got %+v", items)
+	}
+
+	// The events of another node are not returned, even when queried by their session ID.
+	for id, want := range map[uint64]int{1: 1, 2: 0} {
+		items, err := scoped.FindEvents(map[string]interface{}{"session_id": id})
+		if err != nil {
+			t.Fatalf("finding events of session %d: %v", id, err)
+		}
+
+		if len(items) != want {
+			t.Fatalf("expected %d event(s) of session %d, got %+v", want, id, items)
+		}
+	}
+
+	if !scoped.OwnsNodeAddr("node-a") || scoped.OwnsNodeAddr("node-b") || !s.OwnsNodeAddr("node-b") {
+		t.Fatal("unexpected node address ownership")
+	}
+
+	recent, err := NewGormSpeedtestStore(s.db).WithNodeAddr("node-a").FindRecent(10)
+	if err != nil {
+		t.Fatalf("finding recent speedtests: %v", err)
+	}
+
+	if len(recent) != 1 || recent[0].NodeAddr != "node-a" {
+		t.Fatalf("expected only the speedtest of node-a, got %+v", recent)
+	}
+
+	if recent, err = NewGormSpeedtestStore(s.db).FindRecent(10); err != nil || len(recent) != 2 {
+		t.Fatalf("expected both speedtests from an unscoped store, got %+v, %v", recent, err)
+	}
+
+	// Wiping a node leaves the records of the other node in place.
+	if err := WipeNode(s.db, "node-a"); err != nil {
+		t.Fatalf("wiping node: %v", err)
+	}
+
+	for _, item := range []interface{}{&models.Session{}, &models.SessionEvent{}, &models.Speedtest{}} {
+		var addrs []string
+		if err := s.db.Model(item).Pluck("node_addr", &addrs).Error; err != nil {
+			t.Fatalf("finding node addresses of %T: %v", item, err)
+		}
+
+		if len(addrs) != 1 || addrs[0] != "node-b" {
+			t.Fatalf("expected only the %T records of node-b, got %v", item, addrs)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Session lifecycle event types recorded in the event log.
+const (
+	EventTypeSessionCreated = "session_created" // Session has been created by a successful handshake.
+	EventTypeSessionUpdated = "session_updated" // Usage of the session has changed since the previous sync.
+	EventTypeSessionDeleted = "session_deleted" // Session has been deleted by the session validation.
+)
+
+// Event represents a single session lifecycle event written to the event log.
+type Event struct {
+	AccAddr   string    `json:"acc_addr"`
+	EventType string    `json:"event_type"`
+	PeerID    string    `json:"peer_id"`
+	SessionID uint64    `json:"session_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Writer appends events as JSON lines to a file. It is safe for concurrent use.
+// Once the file would grow beyond maxBytes, it is renamed with a ".1" suffix, replacing the previous one, and a new
+// file is started, so that the event log never uses more than twice maxBytes on disk.
+type Writer struct {
+	mu       sync.Mutex
+	file     *os.File
+	maxBytes int64
+	name     string
+	size     int64
+}
+
+// NewWriter opens the file for appending, creating it if it does not exist, and returns a Writer for it that
+// rotates the file at maxBytes.
+func NewWriter(name string, maxBytes int64) (*Writer, error) {
+	w := &Writer{
+		maxBytes: maxBytes,
+		name:     name,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// open opens the file of the Writer for appending, creating it if it does not exist.
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening event log file %q: %w", w.name, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("getting info of event log file %q: %w", w.name, err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+
+	return nil
+}
+
+// rotate renames the file of the Writer with a ".1" suffix, replacing the previous one, and opens a new file.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("closing event log file %q: %w", w.name, err)
+	}
+
+	if err := os.Rename(w.name, w.name+".1"); err != nil {
+		return fmt.Errorf("renaming event log file %q: %w", w.name, err)
+	}
+
+	return w.open()
+}
+
+// Write appends the event to the file as a single JSON line.
+// The timestamp of the event is set to the current time if it is zero.
+func (w *Writer) Write(event *Event) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	buf, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	buf = append(buf, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.size+int64(len(buf)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return fmt.Errorf("rotating event log: %w", err)
+		}
+	}
+
+	n, err := w.file.Write(buf)
+	w.size += int64(n)
+
+	if err != nil {
+		return fmt.Errorf("writing event to %q: %w", w.name, err)
+	}
+
+	return nil
+}
+
+// Close closes the file of the Writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("closing event log file %q: %w", w.name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readEvents reads the events of the event log file or fails the test.
+func readEvents(t *testing.T, name string) []*Event {
+	t.Helper()
+
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("opening event log file: %v", err)
+	}
+	defer file.Close()
+
+	var items []*Event
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var v Event
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			t.Fatalf("decoding event %q: %v", scanner.Text(), err)
+		}
+
+		items = append(items, &v)
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("reading event log file: %v", err)
+	}
+
+	return items
+}
+
+func TestWriterRotate(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "events.jsonl")
+
+	timestamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	buf, err := json.Marshal(&Event{EventType: EventTypeSessionCreated, SessionID: 1, Timestamp: timestamp})
+	if err != nil {
+		t.Fatalf("encoding event: %v", err)
+	}
+
+	// Allow two events of the same encoded length per file.
+	w, err := NewWriter(name, 2*int64(len(buf)+1))
+	if err != nil {
+		t.Fatalf("creating writer: %v", err)
+	}
+
+	for i := uint64(1); i <= 5; i++ {
+		if err := w.Write(&Event{EventType: EventTypeSessionCreated, SessionID: i, Timestamp: timestamp}); err != nil {
+			t.Fatalf("writing event %d: %v", i, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing writer: %v", err)
+	}
+
+	current := readEvents(t, name)
+	if len(current) != 1 || current[0].SessionID != 5 {
+		t.Fatalf("expected event 5 in the current file, got %d events", len(current))
+	}
+
+	previous := readEvents(t, name+".1")
+	if len(previous) != 2 || previous[0].SessionID != 3 || previous[1].SessionID != 4 {
+		t.Fatalf("expected events 3 and 4 in the rotated file, got %d events", len(previous))
+	}
+}
+
+func TestWriterReopen(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "events.jsonl")
+
+	for i := uint64(1); i <= 2; i++ {
+		w, err := NewWriter(name, 1<<20)
+		if err != nil {
+			t.Fatalf("creating writer: %v", err)
+		}
+
+		if err := w.Write(&Event{EventType: EventTypeSessionCreated, SessionID: i}); err != nil {
+			t.Fatalf("writing event %d: %v", i, err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("closing writer: %v", err)
+		}
+	}
+
+	if items := readEvents(t, name); len(items) != 2 {
+		t.Fatalf("expected 2 events appended to the file, got %d", len(items))
+	}
+
+	if _, err := os.Stat(name + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("expected no rotated file, got error %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dvpnx"
+
+// Session status categories used as label values for the session metrics.
+const (
+	SessionStatusActive       = "active"       // Peer is connected and has recent traffic updates.
+	SessionStatusIdle         = "idle"         // Peer is connected without recent traffic updates.
+	SessionStatusOverLimit    = "over_limit"   // Session exceeds its maximum bytes or duration.
+	SessionStatusDisconnected = "disconnected" // Session exists in the database without a connected peer.
+)
+
+var (
+	// registry holds all the metrics exposed by the node.
+	registry = prometheus.NewRegistry()
+
+	// clientLatency tracks the distribution of round-trip times reported by clients.
+	clientLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "client",
+			Name:      "latency_seconds",
+			Help:      "Distribution of round-trip times between clients and the node, as reported by clients.",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		},
+	)
+
+	// databaseBusyErrors tracks the number of database writes failing with a busy error.
+	databaseBusyErrors = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "database",
+			Name:      "busy_errors_total",
+			Help:      "Total number of database writes failing with a busy error.",
+		},
+	)
+
+	// earnings tracks the estimated cumulative earnings of completed sessions by denom.
+	earnings = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "earnings",
+			Name:      "total",
+			Help:      "Estimated cumulative earnings of completed sessions by denom, based on the advertised prices.",
+		},
+		[]string{"denom"},
+	)
+
+	// nodeRecoveries tracks the number of times the node has been set active again after lapsing to inactive.
+	nodeRecoveries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "node",
+			Name:      "recoveries_total",
+			Help:      "Total number of times the node has been set active again after lapsing to inactive on-chain.",
+		},
+	)
+
+	// nodeDowntime tracks the duration of the last inactive period of the node.
+	nodeDowntime = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "node",
+			Name:      "last_downtime_seconds",
+			Help:      "Duration of the last period the node was inactive on-chain before being set active again.",
+		},
+	)
+
+	// peerDivergence tracks the current divergence between the session records and the peers of the service.
+	peerDivergence = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "peers",
+			Name:      "divergence",
+			Help:      "Current number of peers without a session record (orphaned) and session records without a peer (missing).",
+		},
+		[]string{"kind"},
+	)
+
+	// sessions tracks the current number of sessions by derived status.
+	sessions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "sessions",
+			Name:      "current",
+			Help:      "Current number of sessions by status.",
+		},
+		[]string{"status"},
+	)
+)
+
+func init() {
+	registry.MustRegister(clientLatency)
+	registry.MustRegister(databaseBusyErrors)
+	registry.MustRegister(earnings)
+	registry.MustRegister(nodeDowntime)
+	registry.MustRegister(nodeRecoveries)
+	registry.MustRegister(peerDivergence)
+	registry.MustRegister(sessions)
+}
+
+// AddEarnings adds the amount to the estimated cumulative earnings of the denom.
+func AddEarnings(denom string, amount float64) {
+	earnings.WithLabelValues(denom).Add(amount)
+}
+
+// ObserveClientLatency records a round-trip time reported by a client.
+func ObserveClientLatency(rtt time.Duration) {
+	clientLatency.Observe(rtt.Seconds())
+}
+
+// IncDatabaseBusyErrors increments the number of database writes failing with a busy error.
+func IncDatabaseBusyErrors() {
+	databaseBusyErrors.Inc()
+}
+
+// ObserveNodeRecovery records that the node has been set active again after being inactive for the duration.
+func ObserveNodeRecovery(downtime time.Duration) {
+	nodeRecoveries.Inc()
+	nodeDowntime.Set(downtime.Seconds())
+}
+
+// SetPeerDivergence sets the number of orphaned peers and missing peers.
+func SetPeerDivergence(orphaned, missing int) {
+	peerDivergence.WithLabelValues("orphaned").Set(float64(orphaned))
+	peerDivergence.WithLabelValues("missing").Set(float64(missing))
+}
+
+// SessionStatuses returns all the session status categories.
+func SessionStatuses() []string {
+	return []string{
+		SessionStatusActive,
+		SessionStatusIdle,
+		SessionStatusOverLimit,
+		SessionStatusDisconnected,
+	}
+}
+
+// SetSessions sets the number of sessions for each status, resetting the statuses not present in counts.
+func SetSessions(counts map[string]int) {
+	for _, status := range SessionStatuses() {
+		sessions.WithLabelValues(status).Set(float64(counts[status]))
+	}
+}
+
+// Handler returns an HTTP handler serving the registered metrics, negotiating the OpenMetrics format when requested.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
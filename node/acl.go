@@ -0,0 +1,49 @@
+package node
+
+import (
+	"errors"
+	"net"
+
+	"github.com/gin-gonic/gin"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+)
+
+// ipAllowed reports whether the IP address is allowed by the access lists.
+// Denied ranges take precedence over allowed ranges, and an empty allowlist allows every address that is not denied.
+func ipAllowed(ip net.IP, allow, deny []*net.IPNet) bool {
+	for _, v := range deny {
+		if v.Contains(ip) {
+			return false
+		}
+	}
+
+	if len(allow) == 0 {
+		return true
+	}
+
+	for _, v := range allow {
+		if v.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ACLMiddleware returns a middleware rejecting requests from IP addresses outside the access lists with HTTP 403.
+// The address of the connection is used, or the address forwarded by the connection if it is a trusted proxy of the
+// router; forwarded headers of other connections are ignored, since the client controls them.
+func ACLMiddleware(allow, deny []*net.IPNet) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ip := net.ParseIP(ctx.ClientIP())
+		if ip == nil || !ipAllowed(ip, allow, deny) {
+			err := errors.New("access denied for remote address")
+			apierrors.ErrAccessDenied.Abort(ctx, err)
+
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package node
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseCIDRs parses the CIDR ranges or fails the test.
+func parseCIDRs(t *testing.T, cidrs ...string) []*net.IPNet {
+	t.Helper()
+
+	items := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, v := range cidrs {
+		_, ipNet, err := net.ParseCIDR(v)
+		if err != nil {
+			t.Fatalf("parsing cidr %q: %v", v, err)
+		}
+
+		items = append(items, ipNet)
+	}
+
+	return items
+}
+
+func TestIPAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		ip    string
+		allow []string
+		deny  []string
+		want  bool
+	}{
+		{"empty lists", "203.0.113.7", nil, nil, true},
+		{"ipv4 in allowlist", "10.1.2.3", []string{"10.0.0.0/8"}, nil, true},
+		{"ipv4 outside allowlist", "11.1.2.3", []string{"10.0.0.0/8"}, nil, false},
+		{"ipv4 in denylist", "192.168.1.10", nil, []string{"192.168.1.0/24"}, false},
+		{"ipv4 outside denylist", "192.168.2.10", nil, []string{"192.168.1.0/24"}, true},
+		{"ipv4 denied over allowed", "10.0.0.1", []string{"10.0.0.0/8"}, []string{"10.0.0.0/24"}, false},
+		{"ipv4 single address", "198.51.100.1", []string{"198.51.100.1/32"}, nil, true},
+		{"ipv4 next to single address", "198.51.100.2", []string{"198.51.100.1/32"}, nil, false},
+		{"ipv6 in allowlist", "2001:db8::1", []string{"2001:db8::/32"}, nil, true},
+		{"ipv6 outside allowlist", "2001:db9::1", []string{"2001:db8::/32"}, nil, false},
+		{"ipv6 in denylist", "fe80::1", nil, []string{"fe80::/10"}, false},
+		{"ipv6 denied over allowed", "2001:db8::1", []string{"2001:db8::/32"}, []string{"2001:db8::/64"}, false},
+		{"ipv6 against ipv4 allowlist", "2001:db8::1", []string{"10.0.0.0/8"}, nil, false},
+		{"ipv4 against ipv6 allowlist", "10.0.0.1", []string{"2001:db8::/32"}, nil, false},
+		{"ipv4-mapped ipv6 in ipv4 allowlist", "::ffff:10.0.0.1", []string{"10.0.0.0/8"}, nil, true},
+		{"mixed allowlist", "2001:db8::1", []string{"10.0.0.0/8", "2001:db8::/32"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if ip == nil {
+				t.Fatalf("parsing ip %q", tt.ip)
+			}
+
+			if got := ipAllowed(ip, parseCIDRs(t, tt.allow...), parseCIDRs(t, tt.deny...)); got != tt.want {
+				t.Fatalf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestACLMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       int
+	}{
+		{"allowed remote addr", "10.0.0.1:1234", "", http.StatusOK},
+		{"denied remote addr", "203.0.113.7:1234", "", http.StatusForbidden},
+		{"forwarded header ignored", "203.0.113.7:1234", "10.0.0.1", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if err := router.SetTrustedProxies(nil); err != nil {
+				t.Fatalf("setting trusted proxies: %v", err)
+			}
+
+			router.Use(ACLMiddleware(parseCIDRs(t, "10.0.0.0/8"), nil))
+			router.GET("/", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.RemoteAddr = tt.remoteAddr
+
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
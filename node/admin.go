@@ -0,0 +1,153 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+
+	"github.com/sentinel-official/sentinel-dvpnx/admin"
+	"github.com/sentinel-official/sentinel-dvpnx/api/session"
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+)
+
+// AdminPeerResult represents a peer of the service in the response of the peers command.
+type AdminPeerResult struct {
+	ID        string    `json:"id"`
+	RxBytes   int64     `json:"rx_bytes"`
+	TxBytes   int64     `json:"tx_bytes"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// adminHandlerHelp returns the handler listing the methods of the admin socket.
+func adminHandlerHelp(s *admin.Server) admin.HandlerFunc {
+	return func(_ context.Context, _ map[string]string) (interface{}, error) {
+		return s.Methods(), nil
+	}
+}
+
+// adminHandlerPeers returns the handler listing the peers of the service with their traffic.
+func (n *Node) adminHandlerPeers() admin.HandlerFunc {
+	return func(_ context.Context, _ map[string]string) (interface{}, error) {
+		items, err := n.Context().Service().PeerStatistics()
+		if err != nil {
+			return nil, fmt.Errorf("retrieving peer statistics from service: %w", err)
+		}
+
+		res := make([]*AdminPeerResult, 0, len(items))
+		for id, item := range items {
+			res = append(res, &AdminPeerResult{
+				ID:        id,
+				RxBytes:   item.RxBytes,
+				TxBytes:   item.TxBytes,
+				UpdatedAt: item.UpdatedAt,
+			})
+		}
+
+		slices.SortFunc(res, func(a, b *AdminPeerResult) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+
+		return res, nil
+	}
+}
+
+// adminHandlerSessions returns the handler listing the sessions of the node stored in the database.
+func (n *Node) adminHandlerSessions() admin.HandlerFunc {
+	return func(_ context.Context, _ map[string]string) (interface{}, error) {
+		query := map[string]interface{}{
+			"node_addr": n.Context().NodeAddr().String(),
+		}
+
+		items, err := n.Context().SessionStore().Find(query)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving sessions from database: %w", err)
+		}
+
+		res := make([]*session.SessionResult, 0, len(items))
+		for i := range items {
+			res = append(res, session.NewSessionResult(&items[i], n.Context().HumanReadableBytes()))
+		}
+
+		return res, nil
+	}
+}
+
+// adminHandlerReloadPrices returns the handler replacing the prices of the node without a restart. The
+// gigabyte_prices and hourly_prices params use the same format as the configuration.
+func (n *Node) adminHandlerReloadPrices() admin.HandlerFunc {
+	return func(ctx context.Context, params map[string]string) (interface{}, error) {
+		gigabytePrices, err := v1.NewPricesFromString(params["gigabyte_prices"])
+		if err != nil {
+			return nil, fmt.Errorf("parsing gigabyte_prices %q: %w: %w", params["gigabyte_prices"], admin.ErrInvalidParams, err)
+		}
+
+		hourlyPrices, err := v1.NewPricesFromString(params["hourly_prices"])
+		if err != nil {
+			return nil, fmt.Errorf("parsing hourly_prices %q: %w: %w", params["hourly_prices"], admin.ErrInvalidParams, err)
+		}
+
+		if err := n.Context().ReloadPrices(ctx, gigabytePrices, hourlyPrices); err != nil {
+			return nil, fmt.Errorf("reloading prices: %w", err)
+		}
+
+		res := map[string]string{
+			"gigabyte_prices": n.Context().GigabytePrices().String(),
+			"hourly_prices":   n.Context().HourlyPrices().String(),
+		}
+
+		return res, nil
+	}
+}
+
+// adminHandlerDrain returns the handler draining the connected peers of the node. New handshakes are rejected
+// afterwards until the node is restarted.
+func (n *Node) adminHandlerDrain() admin.HandlerFunc {
+	return func(ctx context.Context, _ map[string]string) (interface{}, error) {
+		if timeout := n.Context().DrainTimeout(); timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		if err := n.Drain(ctx); err != nil {
+			return nil, fmt.Errorf("draining peers: %w", err)
+		}
+
+		return "drained", nil
+	}
+}
+
+// SetupAdminSocket sets up the admin socket, if a socket path is configured.
+func (n *Node) SetupAdminSocket(cfg *config.Config) error {
+	path := cfg.Admin.GetSocket()
+	if path == "" {
+		log.Info("Skipping admin socket")
+
+		return nil
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(n.Context().HomeDir(), path)
+	}
+
+	log.Info("Initializing admin socket", "path", path)
+
+	s := admin.NewServer(path)
+	s.WithHandler("drain", n.adminHandlerDrain()).
+		WithHandler("help", adminHandlerHelp(s)).
+		WithHandler("peers", n.adminHandlerPeers()).
+		WithHandler("reload-prices", n.adminHandlerReloadPrices()).
+		WithHandler("sessions", n.adminHandlerSessions())
+
+	// Attach the admin socket to the Node instance.
+	n.WithAdminSocket(s)
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package node
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+)
+
+// BodyLimitMiddleware returns a middleware rejecting requests whose body exceeds limit bytes with HTTP 413.
+// The body is read in full before the handlers run, so that oversized bodies sent without a Content-Length are
+// rejected with the same status instead of failing to bind.
+func BodyLimitMiddleware(limit int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+			ctx.Next()
+
+			return
+		}
+
+		if ctx.Request.ContentLength > limit {
+			err := fmt.Errorf("request body cannot be larger than %d bytes", limit)
+			apierrors.ErrBodyTooLarge.Abort(ctx, err)
+
+			return
+		}
+
+		buf, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				err = fmt.Errorf("request body cannot be larger than %d bytes", limit)
+				apierrors.ErrBodyTooLarge.Abort(ctx, err)
+
+				return
+			}
+
+			err = fmt.Errorf("reading request body: %w", err)
+			apierrors.ErrInvalidRequest.Abort(ctx, err)
+
+			return
+		}
+
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(buf))
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// chunkedReader hides the length of the reader, so that the request is sent without a Content-Length.
+type chunkedReader struct {
+	io.Reader
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const limit = 16
+
+	tests := []struct {
+		name    string
+		body    string
+		chunked bool
+		want    int
+	}{
+		{"empty body", "", false, http.StatusOK},
+		{"body at limit", strings.Repeat("a", limit), false, http.StatusOK},
+		{"oversized body", strings.Repeat("a", limit+1), false, http.StatusRequestEntityTooLarge},
+		{"chunked body at limit", strings.Repeat("a", limit), true, http.StatusOK},
+		{"chunked oversized body", strings.Repeat("a", limit+1), true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []byte
+
+			router := gin.New()
+			router.Use(BodyLimitMiddleware(limit))
+			router.POST("/", func(ctx *gin.Context) {
+				buf, err := io.ReadAll(ctx.Request.Body)
+				if err != nil {
+					t.Errorf("reading body in handler: %v", err)
+				}
+
+				received = buf
+
+				ctx.Status(http.StatusOK)
+			})
+
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.chunked {
+				body = chunkedReader{body}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, rec.Code)
+			}
+
+			if tt.want == http.StatusOK && !bytes.Equal(received, []byte(tt.body)) {
+				t.Fatalf("expected the handler to receive %q, got %q", tt.body, received)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+	"github.com/sentinel-official/sentinelhub/v12/x/node/types/v3"
+
+	"github.com/sentinel-official/sentinel-dvpnx/database"
+	"github.com/sentinel-official/sentinel-dvpnx/workers"
+)
+
+// Deregister takes the node out of the network for decommissioning. The node is marked as inactive on the
+// blockchain so that clients no longer start sessions with it, and the peers of the recorded sessions are removed
+// from the service. If wipeDatabase is set, the records of the local database are deleted afterwards, once the
+// usage they record is synced with the blockchain so that it is billed; nothing is changed if the sync fails.
+// It is run on a node whose context is set up but which is not started, so the usage recorded in the database is
+// final and the service has no statistics left to record.
+func (n *Node) Deregister(ctx context.Context, wipeDatabase bool) error {
+	defer func() {
+		if w := n.Context().EventLog(); w != nil {
+			if err := w.Close(); err != nil {
+				log.Error("Failed to close event log", "cause", err)
+			}
+		}
+
+		if err := n.ReleaseLock(); err != nil {
+			log.Error("Failed to release lock", "cause", err)
+		}
+	}()
+
+	c := n.Context()
+	c.SetInactive(true)
+
+	// The records are the only account of the unbilled usage, so they are wiped only once it is on the blockchain.
+	if wipeDatabase {
+		log.Info("Syncing session usage with blockchain before wiping database")
+
+		if err := workers.SyncSessionUsageWithBlockchain(ctx, c, c.SessionWorkerConcurrency(), c.SessionUpdateBatchSize()); err != nil {
+			return fmt.Errorf("syncing session usage with blockchain, refusing to wipe database: %w", err)
+		}
+	}
+
+	node, err := c.Client().Node(ctx, c.NodeAddr())
+	if err != nil {
+		return fmt.Errorf("failed to query node: %w", err)
+	}
+
+	switch {
+	case node == nil:
+		log.Info("Node not registered, skipping status update", "addr", c.NodeAddr())
+	case node.Status.Equal(v1.StatusInactive):
+		log.Info("Node already inactive", "addr", c.NodeAddr())
+	default:
+		log.Info("Updating node status to inactive", "addr", c.NodeAddr())
+
+		msg := v3.NewMsgUpdateNodeStatusRequest(
+			c.AccAddr().Bytes(),
+			v1.StatusInactive,
+		)
+
+		if err := c.BroadcastTx(ctx, msg); err != nil {
+			return fmt.Errorf("broadcasting tx with update_node_status msg: %w", err)
+		}
+
+		log.Info("Node status updated to inactive", "addr", c.NodeAddr())
+	}
+
+	sessions, err := c.SessionStore().Find(nil)
+	if err != nil {
+		return fmt.Errorf("retrieving sessions from database: %w", err)
+	}
+
+	log.Info("Removing peers of recorded sessions", "count", len(sessions))
+
+	// The service is not started, so the peers are only left behind by an unclean shutdown and failures to reach
+	// the service are not fatal.
+	for _, session := range sessions {
+		if err := c.RemovePeerIfExists(ctx, session.GetPeerID()); err != nil {
+			log.Warn("Failed to remove peer from service", "peer_id", session.GetPeerID(), "cause", err)
+		}
+	}
+
+	if !wipeDatabase {
+		return nil
+	}
+
+	// A database shared by several nodes keeps the records of the other nodes.
+	if database.IsShared(c.Database()) {
+		log.Info("Wiping sessions of node from shared database", "addr", c.NodeAddr())
+
+		if err := database.WipeNode(c.Database(), c.NodeAddr().String()); err != nil {
+			return fmt.Errorf("wiping sessions of node %s from database: %w", c.NodeAddr(), err)
+		}
+
+		return nil
+	}
+
+	log.Info("Wiping database")
+
+	if err := database.Wipe(c.Database()); err != nil {
+		return fmt.Errorf("wiping database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/workers"
+)
+
+// Drain removes the connected peers from the service using the configured drain strategy.
+// New handshakes are rejected while draining by marking the node as inactive. If enabled, the final usage of the
+// peers is recorded in the database and synced with the blockchain first so that it is billed before the peers are
+// removed.
+func (n *Node) Drain(ctx context.Context) error {
+	c := n.Context()
+	c.SetInactive(true)
+
+	if c.DrainSyncUsage() {
+		// Record the usage since the last database sync, which the blockchain sync reads.
+		log.Info("Syncing final session usage with database")
+
+		if err := workers.SyncSessionUsageWithDatabase(ctx, c, 0, c.SessionWorkerConcurrency()); err != nil {
+			log.Error("Failed to sync final session usage with database", "cause", err)
+		}
+
+		log.Info("Syncing final session usage with blockchain")
+
+		if err := workers.SyncSessionUsageWithBlockchain(ctx, c, c.SessionWorkerConcurrency(), c.SessionUpdateBatchSize()); err != nil {
+			log.Error("Failed to sync final session usage with blockchain", "cause", err)
+		}
+	}
+
+	items, err := c.Service().PeerStatistics()
+	if err != nil {
+		return fmt.Errorf("retrieving peer statistics from service: %w", err)
+	}
+
+	peerIDs := make([]string, 0, len(items))
+	for peerID := range items {
+		peerIDs = append(peerIDs, peerID)
+	}
+
+	slices.Sort(peerIDs)
+
+	strategy, batchSize, batchDelay := c.Drain()
+	if strategy == config.DrainStrategyAll {
+		batchSize = uint(len(peerIDs))
+	}
+
+	log.Info("Draining peers", "count", len(peerIDs), "strategy", strategy)
+
+	for i, batch := range chunkPeerIDs(peerIDs, batchSize) {
+		// Wait between consecutive batches.
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err() //nolint:wrapcheck
+			case <-time.After(batchDelay):
+			}
+		}
+
+		log.Info("Removing batch of peers", "batch", i+1, "count", len(batch))
+
+		for _, peerID := range batch {
+			if err := c.RemovePeerIfExists(ctx, peerID); err != nil {
+				return fmt.Errorf("removing peer %q from service: %w", peerID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// chunkPeerIDs splits the peer IDs into batches of at most size elements.
+func chunkPeerIDs(peerIDs []string, size uint) [][]string {
+	if size == 0 {
+		return nil
+	}
+
+	return slices.Collect(slices.Chunk(peerIDs, int(size)))
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// DryRun runs the pre-flight checks of Start without changing any state on the blockchain or starting any
+// component, logging each step that Start would have performed. It releases the lock on the home directory
+// before returning, so it must be called instead of Start after Setup.
+func (n *Node) DryRun(ctx context.Context) error {
+	defer func() {
+		if w := n.Context().EventLog(); w != nil {
+			if err := w.Close(); err != nil {
+				log.Error("Failed to close event log", "cause", err)
+			}
+		}
+
+		if err := n.ReleaseLock(); err != nil {
+			log.Error("Failed to release lock", "cause", err)
+		}
+	}()
+
+	if err := n.CheckStaleSessions(); err != nil {
+		return fmt.Errorf("checking stale sessions: %w", err)
+	}
+
+	// Query the registration and prices without broadcasting, so that RPC and price errors still surface.
+	node, err := n.Context().Client().Node(ctx, n.Context().NodeAddr())
+	if err != nil {
+		return fmt.Errorf("failed to query node: %w", err)
+	}
+
+	gigabytePrices, err := n.Context().SanitizedGigabytePrices(ctx)
+	if err != nil {
+		return fmt.Errorf("sanitizing gigabyte prices: %w", err)
+	}
+
+	hourlyPrices, err := n.Context().SanitizedHourlyPrices(ctx)
+	if err != nil {
+		return fmt.Errorf("sanitizing hourly prices: %w", err)
+	}
+
+	if node == nil {
+		log.Info("Dry run: would register node",
+			"gigabyte_prices", gigabytePrices,
+			"hourly_prices", hourlyPrices,
+			"remote_addrs", n.Context().APIAddrs(),
+		)
+	} else {
+		log.Info("Dry run: node already registered", "addr", n.Context().NodeAddr())
+	}
+
+	log.Info("Dry run: would update node details",
+		"gigabyte_prices", gigabytePrices,
+		"hourly_prices", hourlyPrices,
+		"remote_addrs", n.Context().APIAddrs(),
+	)
+
+	log.Info("Dry run: would start scheduler")
+	log.Info("Dry run: would start API server", "addr", n.Context().APIListenAddr())
+
+	if addr := n.Context().APIHandshakeListenAddr(); addr != "" {
+		log.Info("Dry run: would start API handshake server", "addr", addr)
+	}
+	log.Info("Dry run: would start service", "type", n.Context().Service().Type())
+	log.Info("Dry run: would delete stale sessions and restore imported peers")
+
+	if n.Context().Webhook() != nil {
+		log.Info("Dry run: would start webhook dispatcher")
+	}
+
+	return nil
+}
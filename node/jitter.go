@@ -0,0 +1,61 @@
+package node
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+)
+
+// randomDelay returns a random delay below maxDelay, or zero if maxDelay is not positive.
+func randomDelay(maxDelay time.Duration) time.Duration {
+	if maxDelay <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int64N(int64(maxDelay)))
+}
+
+// sleep waits for the delay, and returns the error of the context if it is done first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// jitteredWorker wraps a scheduler worker to delay its first run by a random offset, so that the workers of nodes
+// started at once do not query the blockchain in lockstep.
+type jitteredWorker struct {
+	cron.Worker
+
+	delay   time.Duration
+	started atomic.Bool
+}
+
+// newJitteredWorker wraps the worker with a random first-run delay below maxDelay.
+func newJitteredWorker(w cron.Worker, maxDelay time.Duration) *jitteredWorker {
+	return &jitteredWorker{
+		Worker: w,
+		delay:  randomDelay(maxDelay),
+	}
+}
+
+// Run waits for the delay before the first run, then executes the wrapped worker.
+func (w *jitteredWorker) Run(ctx context.Context) error {
+	if w.started.CompareAndSwap(false, true) {
+		if err := sleep(ctx, w.delay); err != nil {
+			return err
+		}
+	}
+
+	return w.Worker.Run(ctx) //nolint:wrapcheck
+}
@@ -0,0 +1,74 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// AcquireLock acquires an exclusive lock on the home directory, preventing another node instance from running with it.
+// The lock is held by the operating system and released automatically if the process exits.
+func (n *Node) AcquireLock(homeDir string) error {
+	file := filepath.Join(homeDir, "node.lock")
+
+	log.Info("Acquiring home directory lock", "file", file)
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("opening lock file %q: %w", file, err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		buf, _ := os.ReadFile(file)
+		_ = f.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return fmt.Errorf("another node instance (pid %s) is already running with home directory %q", buf, homeDir)
+		}
+
+		return fmt.Errorf("locking file %q: %w", file, err)
+	}
+
+	// Record the PID of the current process for diagnostics.
+	if err := f.Truncate(0); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("truncating lock file %q: %w", file, err)
+	}
+
+	if _, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("writing lock file %q: %w", file, err)
+	}
+
+	n.lock = f
+
+	return nil
+}
+
+// ReleaseLock releases the lock on the home directory if it is held.
+func (n *Node) ReleaseLock() error {
+	if n.lock == nil {
+		return nil
+	}
+
+	log.Info("Releasing home directory lock", "file", n.lock.Name())
+
+	if err := syscall.Flock(int(n.lock.Fd()), syscall.LOCK_UN); err != nil {
+		return fmt.Errorf("unlocking file %q: %w", n.lock.Name(), err)
+	}
+
+	if err := n.lock.Close(); err != nil {
+		return fmt.Errorf("closing lock file %q: %w", n.lock.Name(), err)
+	}
+
+	n.lock = nil
+
+	return nil
+}
@@ -3,33 +3,50 @@ package node
 import (
 	"context"
 	"fmt"
+	"os"
 
-	"github.com/sentinel-official/sentinel-go-sdk/libs/cmux"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
 	"github.com/sentinel-official/sentinel-go-sdk/process"
 	"github.com/sentinel-official/sentinelhub/v12/x/node/types/v3"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/sentinel-official/sentinel-dvpnx/admin"
 	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
 )
 
 // Node represents the application node, holding its context, scheduler, and server.
 type Node struct {
 	*process.Manager // Embedded process manager for handling lifecycle.
 
-	ctx       *core.Context   // Application code context.
-	scheduler *cron.Scheduler // Scheduler for managing periodic tasks.
-	server    *cmux.Server    // HTTP server for handling API requests.
+	adminSocket     *admin.Server        // Unix domain socket server for runtime introspection.
+	ctx             *core.Context        // Application code context.
+	fatal           chan error           // Errors that shut down the node.
+	handshakeServer *Server              // HTTP server for handling handshake requests apart from the other API requests, if configured.
+	lock            *os.File             // Lock file held on the home directory.
+	scheduler       *cron.Scheduler      // Scheduler for managing periodic tasks.
+	server          *Server              // HTTP server for handling API requests.
+	serviceCtxs     chan context.Context // Contexts of the services started to replace a stopped one.
 }
 
 // New creates a new Node with the provided context.
 func New(name string) *Node {
 	return &Node{
-		Manager: process.NewManager(name),
+		Manager:     process.NewManager(name),
+		fatal:       make(chan error, 1),
+		serviceCtxs: make(chan context.Context, 1),
 	}
 }
 
+// WithAdminSocket sets the admin socket server for the Node and returns the updated Node.
+func (n *Node) WithAdminSocket(v *admin.Server) *Node {
+	n.adminSocket = v
+
+	return n
+}
+
 // WithContext sets the core context for the Node and returns the updated Node.
 func (n *Node) WithContext(ctx *core.Context) *Node {
 	n.ctx = ctx
@@ -37,6 +54,13 @@ func (n *Node) WithContext(ctx *core.Context) *Node {
 	return n
 }
 
+// WithHandshakeServer sets the handshake server for the Node and returns the updated Node.
+func (n *Node) WithHandshakeServer(v *Server) *Node {
+	n.handshakeServer = v
+
+	return n
+}
+
 // WithScheduler sets the scheduler for the Node and returns the updated Node.
 func (n *Node) WithScheduler(v *cron.Scheduler) *Node {
 	n.scheduler = v
@@ -45,24 +69,35 @@ func (n *Node) WithScheduler(v *cron.Scheduler) *Node {
 }
 
 // WithServer sets the server for the Node and returns the updated Node.
-func (n *Node) WithServer(v *cmux.Server) *Node {
+func (n *Node) WithServer(v *Server) *Node {
 	n.server = v
 
 	return n
 }
 
+// AdminSocket returns the admin socket server configured for the Node, or nil if it is disabled.
+func (n *Node) AdminSocket() *admin.Server {
+	return n.adminSocket
+}
+
 // Context returns the core context configured for the Node.
 func (n *Node) Context() *core.Context {
 	return n.ctx
 }
 
+// HandshakeServer returns the handshake server configured for the Node, or nil if the handshake route is served
+// by the API server.
+func (n *Node) HandshakeServer() *Server {
+	return n.handshakeServer
+}
+
 // Scheduler returns the scheduler configured for the Node.
 func (n *Node) Scheduler() *cron.Scheduler {
 	return n.scheduler
 }
 
 // Server returns the server configured for the Node.
-func (n *Node) Server() *cmux.Server {
+func (n *Node) Server() *Server {
 	return n.server
 }
 
@@ -80,6 +115,26 @@ func (n *Node) Register(ctx context.Context) error {
 		return nil
 	}
 
+	// Clients connect to the remote addresses over TLS, which must then be terminated by a reverse proxy.
+	if !n.Context().APITLSEnable() {
+		log.Warn("Registering remote addresses of an API server without TLS, "+
+			"clients can connect only through a reverse proxy terminating TLS", "remote_addrs", n.Context().APIAddrs())
+	}
+
+	// Check that clients can reach the addresses before registering them, unless disabled. The probe expects the
+	// certificate of the node, so it is skipped when a reverse proxy terminates TLS.
+	if probe := n.Context().RemoteAddrsProbe(); probe != "off" && n.Context().APITLSEnable() {
+		log.Info("Probing remote addresses", "remote_addrs", n.Context().APIAddrs())
+
+		if err := n.ProbeRemoteAddrs(ctx); err != nil {
+			if probe == "abort" {
+				return fmt.Errorf("probing remote addrs: %w", err)
+			}
+
+			log.Warn("Registering node with unreachable remote addresses", "cause", err)
+		}
+	}
+
 	gigabytePrices, err := n.Context().SanitizedGigabytePrices(ctx)
 	if err != nil {
 		return fmt.Errorf("sanitizing gigabyte prices: %w", err)
@@ -111,6 +166,11 @@ func (n *Node) Register(ctx context.Context) error {
 
 	log.Info("Node registered successfully", "addr", n.Context().NodeAddr())
 
+	n.Context().EmitEvent(webhook.EventTypeNodeRegistered, map[string]interface{}{
+		"addr":         n.Context().NodeAddr().String(),
+		"remote_addrs": n.Context().APIAddrs(),
+	})
+
 	return nil
 }
 
@@ -150,9 +210,153 @@ func (n *Node) UpdateDetails(ctx context.Context) error {
 	return nil
 }
 
+// CheckStaleSessions verifies that the database sessions belong to the node address of the configured key.
+// Sessions created against a previous node address are reported, and refused if stale sessions are rejected.
+// A database shared by several nodes is not checked, since the sessions of other node addresses belong to them.
+func (n *Node) CheckStaleSessions() error {
+	if n.Context().StaleSessions() == "keep" || database.IsShared(n.Context().Database()) {
+		return nil
+	}
+
+	addr := n.Context().NodeAddr().String()
+
+	sessions, err := n.Context().SessionStore().FindStale(addr)
+	if err != nil {
+		return fmt.Errorf("retrieving stale sessions from database: %w", err)
+	}
+
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	staleAddrs := make([]string, 0, 1)
+
+	for _, session := range sessions {
+		if !seen[session.NodeAddr] {
+			seen[session.NodeAddr] = true
+			staleAddrs = append(staleAddrs, session.NodeAddr)
+		}
+	}
+
+	log.Warn("Database contains sessions of another node address; the configured key may have changed",
+		"addr", addr, "stale_addrs", staleAddrs, "count", len(sessions),
+	)
+
+	if n.Context().StaleSessions() == "reject" {
+		return fmt.Errorf("database contains %d session(s) of node addrs %v other than %s", len(sessions), staleAddrs, addr)
+	}
+
+	return nil
+}
+
+// DeleteStaleSessions deletes the database sessions created against a previous node address.
+// Sessions whose peer is still served are kept so that no connected client loses its record. The sessions of a
+// database shared by several nodes are never deleted, since the sessions of other node addresses belong to them.
+func (n *Node) DeleteStaleSessions(ctx context.Context) error {
+	if n.Context().StaleSessions() != "delete" {
+		return nil
+	}
+
+	if database.IsShared(n.Context().Database()) {
+		log.Info("Skipping deletion of stale sessions in a database shared by several nodes")
+
+		return nil
+	}
+
+	sessions, err := n.Context().SessionStore().FindStale(n.Context().NodeAddr().String())
+	if err != nil {
+		return fmt.Errorf("retrieving stale sessions from database: %w", err)
+	}
+
+	ids := make([]uint64, 0, len(sessions))
+
+	for _, session := range sessions {
+		if !core.IsReleasedPeerID(session.GetPeerID()) {
+			ok, err := n.Context().Service().HasPeer(ctx, session.GetPeerID())
+			if err != nil {
+				return fmt.Errorf("checking if peer %q exists in service: %w", session.GetPeerID(), err)
+			}
+
+			if ok {
+				log.Warn("Keeping stale session with a served peer", "id", session.GetID(), "peer_id", session.GetPeerID())
+
+				continue
+			}
+		}
+
+		ids = append(ids, session.GetID())
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := map[string]interface{}{
+		"id": ids,
+	}
+
+	if err := n.Context().SessionStore().DeleteMany(query); err != nil {
+		return fmt.Errorf("deleting stale sessions from database: %w", err)
+	}
+
+	log.Info("Stale sessions deleted", "count", len(ids))
+
+	return nil
+}
+
+// Shutdown shuts down the running node with the error, so that the start command exits with it. Only the first
+// error is kept.
+func (n *Node) Shutdown(err error) {
+	select {
+	case n.fatal <- err:
+	default:
+	}
+}
+
+// ReloadTLS serves the TLS certificate on disk through the running API server and handshake server.
+func (n *Node) ReloadTLS() error {
+	if err := n.Server().ReloadTLS(); err != nil {
+		return fmt.Errorf("reloading TLS of server: %w", err)
+	}
+
+	if s := n.HandshakeServer(); s != nil {
+		if err := s.ReloadTLS(); err != nil {
+			return fmt.Errorf("reloading TLS of handshake server: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ServiceRestarted hands the context of a service started to replace a stopped one to the running node, which then
+// waits on the new service. Only the latest context is kept.
+func (n *Node) ServiceRestarted(ctx context.Context) {
+	select {
+	case <-n.serviceCtxs:
+	default:
+	}
+
+	n.serviceCtxs <- ctx
+}
+
 // Start initializes the Node's services, scheduler, and API server.
 func (n *Node) Start(ctx context.Context) (context.Context, error) {
 	return n.Manager.Start(ctx, func(ctx context.Context) error { //nolint:contextcheck,wrapcheck
+		if err := n.CheckStaleSessions(); err != nil {
+			return fmt.Errorf("checking stale sessions: %w", err)
+		}
+
+		// Delay the registration by a random offset, so that a fleet of nodes started at once does not broadcast in
+		// the same second.
+		if delay := randomDelay(n.Context().StartupJitterMax()); delay > 0 {
+			log.Info("Delaying registration", "delay", delay.String())
+
+			if err := sleep(ctx, delay); err != nil {
+				return fmt.Errorf("delaying registration: %w", err)
+			}
+		}
+
 		if err := n.Register(ctx); err != nil {
 			return fmt.Errorf("registering node: %w", err)
 		}
@@ -162,9 +366,10 @@ func (n *Node) Start(ctx context.Context) (context.Context, error) {
 		}
 
 		var (
-			schedulerCtx context.Context
-			serverCtx    context.Context
-			serviceCtx   context.Context
+			handshakeServerCtx context.Context
+			schedulerCtx       context.Context
+			serverCtx          context.Context
+			serviceCtx         context.Context
 		)
 
 		sg := &errgroup.Group{}
@@ -189,6 +394,18 @@ func (n *Node) Start(ctx context.Context) (context.Context, error) {
 			return nil
 		})
 
+		if s := n.HandshakeServer(); s != nil {
+			sg.Go(func() (err error) {
+				log.Info("Starting API handshake server")
+
+				if handshakeServerCtx, err = s.Start(ctx); err != nil {
+					return fmt.Errorf("starting API handshake server: %w", err)
+				}
+
+				return nil
+			})
+		}
+
 		sg.Go(func() (err error) {
 			log.Info("Starting service")
 
@@ -203,6 +420,54 @@ func (n *Node) Start(ctx context.Context) (context.Context, error) {
 			return fmt.Errorf("starting group: %w", err)
 		}
 
+		// Delete the stale sessions once the service can report which peers it serves.
+		if err := n.DeleteStaleSessions(ctx); err != nil {
+			return fmt.Errorf("deleting stale sessions: %w", err)
+		}
+
+		// Re-add the peers of the sessions handed over from another node instance.
+		if err := n.Context().RestoreImportedPeers(ctx); err != nil {
+			return fmt.Errorf("restoring imported peers: %w", err)
+		}
+
+		// Deliver the webhook events in the background if enabled.
+		if d := n.Context().Webhook(); d != nil {
+			n.Go(ctx, func() error {
+				if err := d.Run(ctx); err != nil {
+					return fmt.Errorf("running webhook dispatcher: %w", err)
+				}
+
+				return nil
+			})
+		}
+
+		// Serve the admin socket in the background if enabled.
+		if s := n.AdminSocket(); s != nil {
+			log.Info("Starting admin socket", "path", s.Path())
+
+			if err := s.Listen(ctx); err != nil {
+				return fmt.Errorf("listening on admin socket: %w", err)
+			}
+
+			n.Go(ctx, func() error {
+				if err := s.Serve(ctx); err != nil {
+					return fmt.Errorf("serving admin socket: %w", err)
+				}
+
+				return nil
+			})
+		}
+
+		// Shut down the node on unrecoverable errors of the components.
+		n.Go(ctx, func() error {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-n.fatal:
+				return fmt.Errorf("shutting down: %w", err)
+			}
+		})
+
 		n.Go(ctx, func() error {
 			if err := n.Scheduler().Wait(schedulerCtx); err != nil {
 				return fmt.Errorf("waiting scheduler: %w", err)
@@ -219,12 +484,33 @@ func (n *Node) Start(ctx context.Context) (context.Context, error) {
 			return nil
 		})
 
+		if s := n.HandshakeServer(); s != nil {
+			n.Go(ctx, func() error {
+				if err := s.Wait(handshakeServerCtx); err != nil {
+					return fmt.Errorf("waiting API handshake server: %w", err)
+				}
+
+				return nil
+			})
+		}
+
+		// Wait on the current service. A service that stopped unexpectedly is left to the service health worker,
+		// which replaces it or shuts down the node after failing to.
 		n.Go(ctx, func() error {
-			if err := n.Context().Service().Wait(serviceCtx); err != nil {
-				return fmt.Errorf("waiting service: %w", err)
+			for {
+				err := n.Context().Service().Wait(serviceCtx)
+				if err == nil {
+					return nil
+				}
+
+				log.Error("Service stopped unexpectedly, waiting for it to be restarted", "cause", err)
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case serviceCtx = <-n.serviceCtxs:
+				}
 			}
-
-			return nil
 		})
 
 		return nil
@@ -239,6 +525,21 @@ func (n *Node) Wait(ctx context.Context) error {
 // Stop gracefully stops the Node's operations.
 func (n *Node) Stop() error {
 	return n.Manager.Stop(func() error { //nolint:wrapcheck
+		// Remove the connected peers before stopping the components if enabled, within the drain timeout if set.
+		if n.Context().DrainOnShutdown() {
+			ctx := context.Background()
+			if timeout := n.Context().DrainTimeout(); timeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			if err := n.Drain(ctx); err != nil {
+				log.Error("Failed to drain peers", "cause", err)
+			}
+		}
+
 		sg := &errgroup.Group{}
 
 		sg.Go(func() error {
@@ -261,6 +562,18 @@ func (n *Node) Stop() error {
 			return nil
 		})
 
+		if s := n.HandshakeServer(); s != nil {
+			sg.Go(func() error {
+				log.Info("Stopping API handshake server")
+
+				if err := s.Stop(); err != nil {
+					return fmt.Errorf("stopping API handshake server: %w", err)
+				}
+
+				return nil
+			})
+		}
+
 		sg.Go(func() error {
 			log.Info("Stopping service")
 
@@ -275,11 +588,39 @@ func (n *Node) Stop() error {
 			return fmt.Errorf("stopping group: %w", err)
 		}
 
+		// Flush the webhook events emitted up to and during the shutdown.
+		if d := n.Context().Webhook(); d != nil {
+			log.Info("Stopping webhook dispatcher")
+
+			if err := d.Shutdown(); err != nil {
+				log.Error("Failed to stop webhook dispatcher", "cause", err)
+			}
+		}
+
+		// Close the session lifecycle event log after the last session has been handled.
+		if w := n.Context().EventLog(); w != nil {
+			if err := w.Close(); err != nil {
+				log.Error("Failed to close event log", "cause", err)
+			}
+		}
+
+		if err := n.ReleaseLock(); err != nil {
+			return fmt.Errorf("releasing lock: %w", err)
+		}
+
 		return nil
 	})
 }
 
-// Cleanup cleans up resources used by the node.
+// Cleanup cleans up resources used by the node, such as the admin socket file.
 func (n *Node) Cleanup() error {
-	return n.Manager.Cleanup(nil) //nolint:wrapcheck
+	return n.Manager.Cleanup(func() error { //nolint:wrapcheck
+		if s := n.AdminSocket(); s != nil {
+			if err := s.Cleanup(); err != nil {
+				return fmt.Errorf("cleaning up admin socket: %w", err)
+			}
+		}
+
+		return nil
+	})
 }
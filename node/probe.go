@@ -0,0 +1,138 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// remoteAddrProbeTimeout is the time allowed for the TLS handshake with each remote address.
+const remoteAddrProbeTimeout = 10 * time.Second
+
+// ProbeRemoteAddrs checks that clients can reach the node at each of its API addresses, including IPv6 ones,
+// before they are registered on-chain, and dials each API address over TLS. An address is reachable only if the
+// handshake presents the certificate of the node, so that another host answering on the address is not mistaken
+// for the node. The API addresses point to the handshake listen address when the handshake route is served apart
+// from the other routes. The handshake is answered by the live server once it is running, such as when the node
+// is re-registered at runtime.
+func (n *Node) ProbeRemoteAddrs(ctx context.Context) error {
+	cert, err := tls.LoadX509KeyPair(n.Context().TLSCertFile(), n.Context().TLSKeyFile())
+	if err != nil {
+		return fmt.Errorf("loading TLS X509 certificate key pair: %w", err)
+	}
+
+	listenAddr, server := n.Context().APIHandshakeListenAddr(), n.HandshakeServer()
+	if listenAddr == "" {
+		listenAddr, server = n.Context().APIListenAddr(), n.Server()
+	}
+
+	if server != nil && server.IsRunning() {
+		return n.probeRemoteAddrs(ctx, cert.Certificate[0])
+	}
+
+	// Since the server is not running yet, listen on its address with the certificate of the node for the
+	// duration of the probe.
+	lc := &net.ListenConfig{}
+
+	listener, err := lc.Listen(ctx, "tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("creating listener on %q: %w", listenAddr, err)
+	}
+
+	listener = tls.NewListener(listener, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	})
+
+	var wg sync.WaitGroup
+
+	defer wg.Wait()
+	defer listener.Close()
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				_ = conn.SetDeadline(time.Now().Add(remoteAddrProbeTimeout))
+
+				if v, ok := conn.(*tls.Conn); ok {
+					_ = v.HandshakeContext(ctx)
+				}
+			}()
+		}
+	}()
+
+	return n.probeRemoteAddrs(ctx, cert.Certificate[0])
+}
+
+// probeRemoteAddrs dials each API address over TLS and checks that the handshake presents the certificate.
+func (n *Node) probeRemoteAddrs(ctx context.Context, certificate []byte) error {
+	var unreachable []string
+
+	for _, addr := range n.Context().APIAddrs() {
+		if err := probeRemoteAddr(ctx, addr, certificate); err != nil {
+			log.Warn("Remote address is unreachable", "addr", addr, "cause", err)
+			unreachable = append(unreachable, addr)
+
+			continue
+		}
+
+		log.Info("Remote address is reachable", "addr", addr)
+	}
+
+	if len(unreachable) > 0 {
+		return fmt.Errorf("remote addrs %v are unreachable", unreachable)
+	}
+
+	return nil
+}
+
+// probeRemoteAddr dials the address over TLS and checks that the handshake presents the certificate.
+func probeRemoteAddr(ctx context.Context, addr string, certificate []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, remoteAddrProbeTimeout)
+	defer cancel()
+
+	d := &tls.Dialer{
+		Config: &tls.Config{
+			// The certificate of the node is usually self-signed, so it is compared with the presented one instead.
+			InsecureSkipVerify: true, //nolint:gosec
+			MinVersion:         tls.VersionTLS12,
+		},
+	}
+
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dialing %q: %w", addr, err)
+	}
+
+	defer conn.Close()
+
+	v, ok := conn.(*tls.Conn)
+	if !ok {
+		return errors.New("connection is not a TLS connection")
+	}
+
+	items := v.ConnectionState().PeerCertificates
+	if len(items) == 0 || !bytes.Equal(items[0].Raw, certificate) {
+		return errors.New("handshake presented a certificate other than the one of the node")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package node
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/gin/middlewares"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+)
+
+// RateLimiters returns the per-client rate limiting middlewares of the routes other than the handshake and of the
+// handshake route. The handshake route is limited separately, since each handshake adds a peer to the service.
+func RateLimiters(ctx context.Context, cfg *config.APIRateLimitConfig) (limiter, handshakeLimiter gin.HandlerFunc) {
+	limiter = middlewares.RateLimiter(ctx, &middlewares.RateLimiterOptions{
+		Limit: cfg.GetRPS(),
+		Burst: cfg.GetBurst(),
+	})
+	handshakeLimiter = middlewares.RateLimiter(ctx, &middlewares.RateLimiterOptions{
+		Limit: cfg.GetHandshakeRPS(),
+		Burst: cfg.GetHandshakeBurst(),
+	})
+
+	return limiter, handshakeLimiter
+}
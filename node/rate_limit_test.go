@@ -0,0 +1,72 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+)
+
+func TestRateLimiters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := config.DefaultAPIRateLimitConfig().
+		WithBurst(3).
+		WithRPS(0.001).
+		WithHandshakeBurst(2).
+		WithHandshakeRPS(0.001)
+
+	limiter, handshakeLimiter := RateLimiters(ctx, cfg)
+
+	handler := func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	}
+
+	router := gin.New()
+	router.GET("/", limiter, handler)
+	router.POST("/", handshakeLimiter, handler)
+
+	serve := func(method, remoteAddr string) int {
+		req := httptest.NewRequest(method, "/", http.NoBody)
+		req.RemoteAddr = remoteAddr
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	// The burst of each limiter is served, and the next request of the burst is refused.
+	for i := 0; i < cfg.GetHandshakeBurst(); i++ {
+		if code := serve(http.MethodPost, "10.0.0.1:1234"); code != http.StatusOK {
+			t.Fatalf("handshake request %d: expected status %d, got %d", i, http.StatusOK, code)
+		}
+	}
+
+	if code := serve(http.MethodPost, "10.0.0.1:1234"); code != http.StatusTooManyRequests {
+		t.Fatalf("handshake request over burst: expected status %d, got %d", http.StatusTooManyRequests, code)
+	}
+
+	// The other routes are limited apart from the handshake route.
+	for i := 0; i < cfg.GetBurst(); i++ {
+		if code := serve(http.MethodGet, "10.0.0.1:1234"); code != http.StatusOK {
+			t.Fatalf("request %d: expected status %d, got %d", i, http.StatusOK, code)
+		}
+	}
+
+	if code := serve(http.MethodGet, "10.0.0.1:1234"); code != http.StatusTooManyRequests {
+		t.Fatalf("request over burst: expected status %d, got %d", http.StatusTooManyRequests, code)
+	}
+
+	// Other clients are limited apart from the client that used its burst.
+	if code := serve(http.MethodPost, "10.0.0.2:1234"); code != http.StatusOK {
+		t.Fatalf("handshake request of other client: expected status %d, got %d", http.StatusOK, code)
+	}
+}
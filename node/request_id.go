@@ -0,0 +1,56 @@
+package node
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+)
+
+// maxRequestIDLen is the maximum length of a correlation ID accepted from a client.
+const maxRequestIDLen = 64
+
+// validRequestID reports whether the correlation ID sent by a client is safe to echo and log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID returns a random correlation ID.
+func newRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
+
+// RequestIDMiddleware returns a middleware assigning a correlation ID to every request, so that client reports can
+// be matched with the log lines of the node. The X-Request-ID header of the request is kept if it is valid, and a
+// random ID is generated otherwise. The ID is returned in the X-Request-ID header of the response and in the body
+// of error responses.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(apierrors.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		apierrors.SetRequestID(ctx, id)
+		ctx.Header(apierrors.RequestIDHeader, id)
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// timedWorker wraps a scheduler worker to record its run times in the worker schedule.
+type timedWorker struct {
+	cron.Worker
+
+	schedule *core.WorkerSchedule
+}
+
+// newTimedWorker wraps the worker and registers it in the schedule.
+func newTimedWorker(w cron.Worker, schedule *core.WorkerSchedule) *timedWorker {
+	schedule.Register(w.Name(), w.Interval())
+
+	return &timedWorker{
+		Worker:   w,
+		schedule: schedule,
+	}
+}
+
+// Run executes the wrapped worker and records its start and end in the schedule.
+func (w *timedWorker) Run(ctx context.Context) error {
+	w.schedule.RecordStart(w.Name())
+
+	defer func() {
+		w.schedule.RecordEnd(w.Name(), time.Now())
+	}()
+
+	return w.Worker.Run(ctx) //nolint:wrapcheck
+}
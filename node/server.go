@@ -0,0 +1,244 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/process"
+	"github.com/soheilhy/cmux"
+	"golang.org/x/net/http2"
+)
+
+// Server is an HTTP server that serves both HTTP and HTTPS traffic on the same TCP port using cmux.
+// HTTPS connections below the configured minimum TLS version are refused during the handshake, and HTTP/2 is
+// negotiated through ALPN. With TLS disabled, only plain HTTP is served and no certificate is needed, for setups
+// behind a reverse proxy that terminates TLS.
+type Server struct {
+	*process.Manager // Embedded process manager for handling lifecycle.
+
+	addr          string       // TCP address to listen on.
+	certFile      string       // Path to the TLS certificate file.
+	handler       http.Handler // HTTP handler for processing requests.
+	keyFile       string       // Path to the TLS private key file.
+	tlsEnable     bool         // Whether HTTPS traffic is served alongside plain HTTP.
+	tlsMinVersion uint16       // Minimum TLS version accepted for HTTPS connections.
+
+	cert      atomic.Pointer[tls.Certificate] // Certificate served to new HTTPS connections.
+	cMux      cmux.CMux                       // Multiplexer for matching connections, if TLS is enabled.
+	anyServer *http.Server                    // HTTP server for non-TLS traffic.
+	tlsServer *http.Server                    // HTTP server for TLS traffic, if TLS is enabled.
+}
+
+// NewServer initializes a new Server with the given address, TLS certificate and key files, and HTTP handler.
+func NewServer(name, addr, certFile, keyFile string, handler http.Handler) *Server {
+	return &Server{
+		Manager:       process.NewManager(name),
+		addr:          addr,
+		certFile:      certFile,
+		handler:       handler,
+		keyFile:       keyFile,
+		tlsEnable:     true,
+		tlsMinVersion: tls.VersionTLS12,
+	}
+}
+
+// WithTLSEnable sets whether HTTPS traffic is served and returns the updated Server.
+func (s *Server) WithTLSEnable(enable bool) *Server {
+	s.tlsEnable = enable
+
+	return s
+}
+
+// WithTLSMinVersion sets the minimum TLS version accepted for HTTPS connections and returns the updated Server.
+func (s *Server) WithTLSMinVersion(version uint16) *Server {
+	s.tlsMinVersion = version
+
+	return s
+}
+
+// TLSConfig returns the TLS configuration of HTTPS connections, which serves the current certificate.
+func (s *Server) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.cert.Load(), nil
+		},
+		MinVersion: s.tlsMinVersion,
+		NextProtos: []string{http2.NextProtoTLS, "http/1.1"},
+		Rand:       rand.Reader,
+	}
+}
+
+// loadCert loads the TLS certificate and key from disk and serves them to new HTTPS connections.
+func (s *Server) loadCert() error {
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading TLS X509 certificate key pair from %q and %q: %w", s.certFile, s.keyFile, err)
+	}
+
+	s.cert.Store(&cert)
+
+	return nil
+}
+
+// Setup prepares the server for operation.
+func (s *Server) Setup(ctx context.Context) error {
+	return s.Manager.Setup(ctx, nil) //nolint:wrapcheck
+}
+
+// Start launches the server and begins handling both HTTP and HTTPS traffic.
+func (s *Server) Start(parent context.Context) (context.Context, error) {
+	return s.Manager.Start(parent, func(ctx context.Context) error { //nolint:wrapcheck
+		if s.tlsEnable {
+			if err := s.loadCert(); err != nil {
+				return err
+			}
+		}
+
+		lc := &net.ListenConfig{}
+
+		listener, err := lc.Listen(ctx, "tcp", s.addr)
+		if err != nil {
+			return fmt.Errorf("creating listener on %q: %w", s.addr, err)
+		}
+
+		s.anyServer = &http.Server{
+			Handler:           s.handler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		if !s.tlsEnable {
+			s.Go(ctx, func() error {
+				if err := s.anyServer.Serve(listener); err != nil {
+					return fmt.Errorf("serving any: %w", err)
+				}
+
+				return nil
+			})
+		} else if err := s.startCMux(ctx, listener); err != nil {
+			_ = listener.Close()
+
+			return err
+		}
+
+		// Close the listener and the multiplexer on context cancellation.
+		s.Go(ctx, func() error {
+			defer func() {
+				_ = listener.Close()
+
+				if s.cMux != nil {
+					s.cMux.Close()
+				}
+			}()
+
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+
+		return nil
+	})
+}
+
+// startCMux separates TLS traffic from everything else on the listener and serves both.
+func (s *Server) startCMux(ctx context.Context, listener net.Listener) error {
+	s.cMux = cmux.New(listener)
+	tlsMux := s.cMux.Match(cmux.TLS())
+	anyMux := s.cMux.Match(cmux.Any())
+
+	// Discard the error log of the HTTPS server to avoid handshake noise.
+	s.tlsServer = &http.Server{
+		ErrorLog:          log.New(io.Discard, "", 0),
+		Handler:           s.handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		TLSConfig:         s.TLSConfig(),
+	}
+
+	if err := http2.ConfigureServer(s.tlsServer, nil); err != nil {
+		return fmt.Errorf("configuring HTTP/2: %w", err)
+	}
+
+	s.Go(ctx, func() error {
+		if err := s.cMux.Serve(); err != nil {
+			return fmt.Errorf("serving cMux: %w", err)
+		}
+
+		return nil
+	})
+
+	s.Go(ctx, func() error {
+		if err := s.tlsServer.Serve(tls.NewListener(tlsMux, s.tlsServer.TLSConfig)); err != nil {
+			return fmt.Errorf("serving TLS: %w", err)
+		}
+
+		return nil
+	})
+
+	s.Go(ctx, func() error {
+		if err := s.anyServer.Serve(anyMux); err != nil {
+			return fmt.Errorf("serving any: %w", err)
+		}
+
+		return nil
+	})
+
+	return nil
+}
+
+// ReloadTLS loads the certificate on disk and serves it to new HTTPS connections, without closing the listener.
+// It does nothing if the server is not running or serves plain HTTP only.
+func (s *Server) ReloadTLS() error {
+	if !s.IsRunning() || !s.tlsEnable {
+		return nil
+	}
+
+	return s.loadCert()
+}
+
+// Wait blocks until all server goroutines have exited or an error occurs.
+func (s *Server) Wait(ctx context.Context) error {
+	return s.Manager.Wait(ctx, nil) //nolint:wrapcheck
+}
+
+// Stop gracefully shuts down both the TLS and non-TLS servers and stops the multiplexer.
+func (s *Server) Stop() error {
+	return s.Manager.Stop(func() error { //nolint:wrapcheck
+		// Close the multiplexer first, which unblocks its listeners.
+		if s.cMux != nil {
+			s.cMux.Close()
+		}
+
+		for _, srv := range []*http.Server{s.tlsServer, s.anyServer} {
+			if srv == nil {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+			if err := srv.Shutdown(ctx); err != nil {
+				_ = srv.Close()
+			}
+
+			cancel()
+		}
+
+		return nil
+	})
+}
+
+// Cleanup releases any remaining resources associated with the server.
+func (s *Server) Cleanup() error {
+	return s.Manager.Cleanup(func() error { //nolint:wrapcheck
+		s.cMux = nil
+		s.anyServer = nil
+		s.tlsServer = nil
+
+		return nil
+	})
+}
@@ -0,0 +1,203 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// startTestServer starts a Server with a certificate for 127.0.0.1 on a free port and returns its address.
+func startTestServer(t *testing.T, tlsMinVersion uint16) string {
+	t.Helper()
+
+	_, addr := startTestServerInDir(t, t.TempDir(), tlsMinVersion)
+
+	return addr
+}
+
+// startTestServerInDir starts a Server with a certificate for 127.0.0.1 issued in the directory on a free port and
+// returns it with its address.
+func startTestServerInDir(t *testing.T, dir string, tlsMinVersion uint16) (*Server, string) {
+	t.Helper()
+
+	if err := core.InitPKI(dir, []string{"127.0.0.1"}); err != nil {
+		t.Fatalf("initializing PKI: %v", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening on free port: %v", err)
+	}
+
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	s := NewServer("test", addr, filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), handler).
+		WithTLSMinVersion(tlsMinVersion)
+
+	if err := s.Setup(context.Background()); err != nil {
+		t.Fatalf("setting up server: %v", err)
+	}
+
+	if _, err := s.Start(context.Background()); err != nil {
+		t.Fatalf("starting server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = s.Stop()
+	})
+
+	return s, addr
+}
+
+// peerCertificate returns the raw leaf certificate served at the address.
+func peerCertificate(t *testing.T, addr string) []byte {
+	t.Helper()
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec
+	})
+	if err != nil {
+		t.Fatalf("dialing server: %v", err)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	return conn.ConnectionState().PeerCertificates[0].Raw
+}
+
+func TestServerTLSMinVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		tlsMinVersion uint16
+		clientVersion uint16
+		wantErr       bool
+	}{
+		{"tls10 refused", tls.VersionTLS12, tls.VersionTLS10, true},
+		{"tls11 refused", tls.VersionTLS12, tls.VersionTLS11, true},
+		{"tls12 allowed", tls.VersionTLS12, tls.VersionTLS12, false},
+		{"tls13 allowed", tls.VersionTLS12, tls.VersionTLS13, false},
+		{"tls12 refused", tls.VersionTLS13, tls.VersionTLS12, true},
+		{"tls13 required", tls.VersionTLS13, tls.VersionTLS13, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startTestServer(t, tt.tlsMinVersion)
+
+			conn, err := tls.Dial("tcp", addr, &tls.Config{
+				InsecureSkipVerify: true, //nolint:gosec
+				MaxVersion:         tt.clientVersion,
+				MinVersion:         tls.VersionTLS10,
+			})
+			if tt.wantErr {
+				if err == nil {
+					_ = conn.Close()
+					t.Fatalf("expected the handshake over %s to be refused", tls.VersionName(tt.clientVersion))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("dialing server: %v", err)
+			}
+
+			defer func() {
+				_ = conn.Close()
+			}()
+
+			if v := conn.ConnectionState().Version; v != tt.clientVersion {
+				t.Fatalf("expected %s, got %s", tls.VersionName(tt.clientVersion), tls.VersionName(v))
+			}
+		})
+	}
+}
+
+func TestServerProtocols(t *testing.T) {
+	addr := startTestServer(t, tls.VersionTLS12)
+
+	tests := []struct {
+		name      string
+		url       string
+		transport *http.Transport
+		wantProto int
+	}{
+		{
+			name: "https negotiates http2",
+			url:  "https://" + addr + "/",
+			transport: &http.Transport{
+				ForceAttemptHTTP2: true,
+				TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+			},
+			wantProto: 2,
+		},
+		{
+			name: "https falls back to http1",
+			url:  "https://" + addr + "/",
+			transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+			},
+			wantProto: 1,
+		},
+		{
+			name:      "plain http on the same port",
+			url:       "http://" + addr + "/",
+			transport: &http.Transport{},
+			wantProto: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: tt.transport}
+			defer tt.transport.CloseIdleConnections()
+
+			resp, err := client.Get(tt.url)
+			if err != nil {
+				t.Fatalf("sending request: %v", err)
+			}
+
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+
+			if resp.ProtoMajor != tt.wantProto {
+				t.Fatalf("expected HTTP/%d, got %s", tt.wantProto, resp.Proto)
+			}
+		})
+	}
+}
+
+func TestServerReloadTLS(t *testing.T) {
+	dir := t.TempDir()
+	s, addr := startTestServerInDir(t, dir, tls.VersionTLS12)
+
+	before := peerCertificate(t, addr)
+
+	if err := core.ResetTLS(dir, []string{"127.0.0.1"}); err != nil {
+		t.Fatalf("resetting TLS: %v", err)
+	}
+
+	if err := s.ReloadTLS(); err != nil {
+		t.Fatalf("reloading TLS: %v", err)
+	}
+
+	if after := peerCertificate(t, addr); bytes.Equal(before, after) {
+		t.Fatal("expected the reissued certificate to be served")
+	}
+}
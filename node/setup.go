@@ -8,12 +8,12 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/sentinel-official/sentinel-go-sdk/libs/cmux"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
-	"github.com/sentinel-official/sentinel-go-sdk/libs/gin/middlewares"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
 
 	"github.com/sentinel-official/sentinel-dvpnx/api"
+	apierrors "github.com/sentinel-official/sentinel-dvpnx/api/errors"
+	"github.com/sentinel-official/sentinel-dvpnx/api/metrics"
 	"github.com/sentinel-official/sentinel-dvpnx/config"
 	"github.com/sentinel-official/sentinel-dvpnx/core"
 	"github.com/sentinel-official/sentinel-dvpnx/workers"
@@ -21,17 +21,104 @@ import (
 
 // SetupScheduler sets up the cron scheduler with various workers.
 func (n *Node) SetupScheduler(ctx context.Context, cfg *config.Config) error {
+	// Register the node again from the status update worker once it is no longer found on-chain, unless disabled.
+	var register func(context.Context) error
+	if cfg.Node.GetReregisterIfMissing() {
+		register = n.Register
+	}
+
 	// Define the list of cron workers with their respective handlers and intervals.
 	items := []cron.Worker{
 		workers.NewBestRPCAddrWorker(n.Context(), cfg.Node.GetIntervalBestRPCAddr()),
-		workers.NewGeoIPLocationWorker(n.Context(), cfg.Node.GetIntervalGeoIPLocation()),
+		workers.NewGeoIPLocationWorker(
+			n.Context(), cfg.Node.GetIntervalGeoIPLocation(), cfg.Node.GetRetryBackoffBase(), cfg.Node.GetRetryBackoffMax(),
+		),
 		workers.NewNodePricesUpdateWorker(n.Context(), cfg.Node.GetIntervalPricesUpdate()),
-		workers.NewNodeStatusUpdateWorker(n.Context(), cfg.Node.GetIntervalStatusUpdate()),
-		workers.NewSessionUsageSyncWithBlockchainWorker(n.Context(), cfg.Node.GetIntervalSessionUsageSyncWithBlockchain()),
-		workers.NewSessionUsageSyncWithDatabaseWorker(n.Context(), cfg.Node.GetIntervalSessionUsageSyncWithDatabase()),
-		workers.NewSessionUsageValidateWorker(n.Context(), cfg.Node.GetIntervalSessionUsageValidate()),
-		workers.NewSessionValidateWorker(n.Context(), cfg.Node.GetIntervalSessionValidate()),
-		workers.NewSpeedtestWorker(n.Context(), cfg.Node.GetIntervalSpeedtest()),
+		workers.NewNodeStatusCheckWorker(n.Context(), cfg.Node.GetIntervalStatusCheck()),
+		workers.NewNodeStatusUpdateWorker(
+			n.Context(), cfg.Node.GetIntervalStatusUpdate(), cfg.Node.GetRetryBackoffBase(), cfg.Node.GetRetryBackoffMax(),
+			register,
+		),
+		workers.NewServiceHealthWorker(
+			n.Context(), cfg, cfg.Node.GetIntervalServiceHealth(), cfg.Node.GetServiceMaxRestarts(),
+			n.ServiceRestarted, n.Shutdown,
+		),
+		workers.NewSessionRetentionWorker(n.Context(), cfg.Node.GetIntervalSessionRetention(), cfg.Node.GetSessionRetention()),
+		workers.NewSessionUsageSyncWithBlockchainWorker(
+			n.Context(), cfg.Node.GetIntervalSessionUsageSyncWithBlockchain(), cfg.Node.GetRetryBackoffBase(), cfg.Node.GetRetryBackoffMax(),
+			cfg.Node.GetSessionWorkerConcurrency(), cfg.Node.GetSessionUpdateBatchSize(),
+		),
+		workers.NewSessionUsageSyncWithDatabaseWorker(
+			n.Context(), cfg.Node.GetIntervalSessionUsageSyncWithDatabase(), cfg.Node.GetSessionWorkerConcurrency(),
+		),
+		workers.NewSessionUsageValidateWorker(
+			n.Context(), cfg.Node.GetIntervalSessionUsageValidate(), cfg.Node.GetSessionWorkerConcurrency(),
+		),
+		workers.NewSessionValidateWorker(
+			n.Context(), cfg.Node.GetIntervalSessionValidate(), cfg.Node.GetSessionWorkerConcurrency(),
+		),
+	}
+
+	// Register the speed test worker only if it is enabled.
+	if cfg.Speedtest.GetEnable() {
+		items = append(items, workers.NewSpeedtestWorker(n.Context(), cfg.Node.GetIntervalSpeedtest()))
+	} else {
+		log.Info("Skipping scheduler worker", "name", workers.NameSpeedtest, "cause", "speedtest disabled")
+	}
+
+	// Register the remote addrs update worker only if it is enabled.
+	if cfg.Node.GetRemoteAddrsAuto() {
+		items = append(items, workers.NewNodeRemoteAddrsUpdateWorker(
+			n.Context(), cfg.Node.GetIntervalRemoteAddrsUpdate(), n.ReloadTLS,
+		))
+	} else {
+		log.Info("Skipping scheduler worker", "name", workers.NameNodeRemoteAddrsUpdate, "cause", "remote addrs auto disabled")
+	}
+
+	// Register the peer reconcile worker only if it is enabled.
+	if cfg.Reconcile.GetEnable() {
+		items = append(items, workers.NewPeerReconcileWorker(
+			n.Context(), cfg.Reconcile.GetInterval(), cfg.Reconcile.GetTolerance(), cfg.Reconcile.GetRemoveOrphanedPeers(),
+		))
+	} else {
+		log.Info("Skipping scheduler worker", "name", workers.NamePeerReconcile, "cause", "reconcile disabled")
+	}
+
+	// Register the blocklist sync worker only if a remote blocklist is configured.
+	if cfg.Blocklist.GetURL() != "" {
+		items = append(items, workers.NewBlocklistSyncWorker(n.Context(), cfg.Blocklist.GetInterval()))
+	} else {
+		log.Info("Skipping scheduler worker", "name", workers.NameBlocklistSync, "cause", "blocklist url not set")
+	}
+
+	// Register the metrics workers only if metrics are enabled.
+	if cfg.Metrics.GetEnable() {
+		items = append(items, workers.NewMetricsSessionsWorker(n.Context(), cfg.Metrics.GetIntervalSessions()))
+	} else {
+		log.Info("Skipping scheduler worker", "name", workers.NameMetricsSessions, "cause", "metrics disabled")
+	}
+
+	// Wrap the workers to raise alerts on sustained failures only if alerting is enabled.
+	if threshold := cfg.Alert.GetWorkerFailureThreshold(); threshold > 0 {
+		for i, item := range items {
+			items[i] = newSupervisedWorker(n.Context(), item, threshold, cfg.Alert.GetWebhook())
+		}
+	} else {
+		log.Info("Skipping scheduler worker supervision", "cause", "alert worker failure threshold disabled")
+	}
+
+	// Wrap the workers to record their run times only if the schedule is exposed.
+	if schedule := n.Context().WorkerSchedule(); schedule != nil {
+		for i, item := range items {
+			items[i] = newTimedWorker(item, schedule)
+		}
+	}
+
+	// Wrap the workers to stagger their first runs only if a startup jitter is configured.
+	if maxDelay := cfg.Node.GetStartupJitterMax(); maxDelay > 0 {
+		for i, item := range items {
+			items[i] = newJitteredWorker(item, maxDelay)
+		}
 	}
 
 	log.Info("Initializing scheduler")
@@ -58,45 +145,120 @@ func (n *Node) SetupScheduler(ctx context.Context, cfg *config.Config) error {
 }
 
 // SetupServer sets up the API server with necessary middlewares and API routes.
-func (n *Node) SetupServer(ctx context.Context, _ *config.Config) error {
+func (n *Node) SetupServer(ctx context.Context, cfg *config.Config) error {
 	// Sets the Gin mode to ReleaseMode.
 	gin.SetMode(gin.ReleaseMode)
 
+	router, handshakeRouter, err := newRouters(ctx, n.Context(), cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Initializing API server", "tls", n.Context().APITLSEnable())
+
+	s := NewServer(
+		"API-server",
+		n.Context().APIListenAddr(),
+		n.Context().TLSCertFile(),
+		n.Context().TLSKeyFile(),
+		router,
+	).WithTLSEnable(n.Context().APITLSEnable()).WithTLSMinVersion(cfg.Node.GetTLSMinVersion())
+	if err := s.Setup(ctx); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// Attach the API server to the Node instance.
+	n.WithServer(s)
+
+	if handshakeRouter == nil {
+		return nil
+	}
+
+	log.Info("Initializing API handshake server", "addr", n.Context().APIHandshakeListenAddr())
+
+	hs := NewServer(
+		"API-handshake-server",
+		n.Context().APIHandshakeListenAddr(),
+		n.Context().TLSCertFile(),
+		n.Context().TLSKeyFile(),
+		handshakeRouter,
+	).WithTLSEnable(n.Context().APITLSEnable()).WithTLSMinVersion(cfg.Node.GetTLSMinVersion())
+	if err := hs.Setup(ctx); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	n.WithHandshakeServer(hs)
+
+	return nil
+}
+
+// newRouters creates the router of the API routes with the necessary middlewares, and a router of the handshake
+// route if it is served apart. The router serving the handshake route also serves the info route, since clients
+// query both at the registered remote addresses.
+func newRouters(ctx context.Context, c *core.Context, cfg *config.Config) (router, handshakeRouter *gin.Engine, _ error) {
 	// Define middlewares to be used by the router.
 	items := []gin.HandlerFunc{
 		cors.New(
 			cors.Config{
 				AllowAllOrigins: true,
 				AllowMethods:    []string{http.MethodGet, http.MethodPost},
+				ExposeHeaders:   []string{apierrors.RequestIDHeader},
 			},
 		),
-		middlewares.RateLimiter(ctx, nil),
 	}
 
-	// Create a new Gin router and apply the middlewares.
-	router := gin.New()
+	// Reject requests from denied IP ranges before they reach the handlers, if access lists are configured.
+	proxies := cfg.APIACL.GetTrustedProxies()
+	if allow, deny := cfg.APIACL.GetAllow(), cfg.APIACL.GetDeny(); len(allow) > 0 || len(deny) > 0 {
+		log.Info("Initializing API access lists", "allow", len(allow), "deny", len(deny), "trusted_proxies", len(proxies))
+		items = append([]gin.HandlerFunc{ACLMiddleware(allow, deny)}, items...)
+
+		if !c.APITLSEnable() && len(proxies) == 0 {
+			log.Warn("API access lists match the address of the reverse proxy, since no trusted proxies are configured")
+		}
+	}
+
+	// Assign a correlation ID to every request before any middleware can reject it.
+	items = append([]gin.HandlerFunc{RequestIDMiddleware()}, items...)
+
+	// Bound the size of request bodies, which are small for every route.
+	items = append(items, BodyLimitMiddleware(cfg.Node.GetAPIMaxBodyBytes()))
+
+	// Create a new Gin router, trusting the forwarded client addresses of the configured proxies only, and apply the
+	// middlewares.
+	router = gin.New()
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		return nil, nil, fmt.Errorf("setting trusted proxies: %w", err)
+	}
+
 	router.Use(items...)
 
-	// Register API routes to the router.
-	api.RegisterRoutes(n.Context(), router)
+	// Limit the handshake route separately from the other routes.
+	limiter, handshakeLimiter := RateLimiters(ctx, cfg.APIRateLimit)
 
-	log.Info("Initializing API server")
+	// Register API routes to the router, and the handshake route to a router of its own if it is served apart.
+	api.RegisterRoutes(c, router, limiter)
 
-	s := cmux.NewServer(
-		"API-server",
-		n.Context().APIListenAddr(),
-		n.Context().TLSCertFile(),
-		n.Context().TLSKeyFile(),
-		router,
-	)
-	if err := s.Setup(ctx); err != nil {
-		return err //nolint:wrapcheck
+	if c.APIHandshakeListenAddr() == "" {
+		api.RegisterHandshakeRoutes(c, router, handshakeLimiter)
+	} else {
+		handshakeRouter = gin.New()
+		if err := handshakeRouter.SetTrustedProxies(proxies); err != nil {
+			return nil, nil, fmt.Errorf("setting trusted proxies of handshake router: %w", err)
+		}
+
+		// The remote addresses registered on-chain point to the handshake port, where clients also query the info.
+		handshakeRouter.Use(items...)
+		api.RegisterInfoRoutes(c, handshakeRouter, limiter)
+		api.RegisterHandshakeRoutes(c, handshakeRouter, handshakeLimiter)
 	}
 
-	// Attach the API server to the Node instance.
-	n.WithServer(s)
+	// Register the metrics routes only if metrics are enabled.
+	if cfg.Metrics.GetEnable() {
+		metrics.RegisterRoutes(router.Group("", limiter))
+	}
 
-	return nil
+	return router, handshakeRouter, nil
 }
 
 // SetupContext sets up the core context.
@@ -113,33 +275,62 @@ func (n *Node) SetupContext(ctx context.Context, homeDir string, input io.Reader
 	// Seal the context.
 	c.Seal()
 
+	// Tag every subsequent log line with the configured static fields.
+	var fields []any
+	if cfg.Log.GetIdentityFields() {
+		fields = append(fields, "moniker", c.Moniker(), "node_addr", c.NodeAddr().String())
+	}
+
+	if region := cfg.Log.GetRegion(); region != "" {
+		fields = append(fields, "region", region)
+	}
+
+	if len(fields) > 0 {
+		log.SetLogger(log.With(fields...))
+	}
+
 	// Attach the code context to the Node instance.
 	n.WithContext(c)
 
 	return nil
 }
 
-// Setup sets up the context, scheduler and API server for the Node.
+// Setup sets up the context, scheduler, API server and admin socket for the Node.
 func (n *Node) Setup(ctx context.Context, homeDir string, input io.Reader, cfg *config.Config) error {
 	return n.Manager.Setup(ctx, func() error { //nolint:wrapcheck
+		// Refuse to start if another instance is running with the same home directory.
+		if err := n.AcquireLock(homeDir); err != nil {
+			return fmt.Errorf("acquiring lock: %w", err)
+		}
+
 		log.Info("Setting up context")
 
 		if err := n.SetupContext(ctx, homeDir, input, cfg); err != nil {
 			return fmt.Errorf("setting up context: %w", err)
 		}
 
+		timings := n.Context().StartupTimings()
+
 		log.Info("Setting up scheduler")
 
-		if err := n.SetupScheduler(ctx, cfg); err != nil {
+		if err := timings.Time("scheduler", func() error { return n.SetupScheduler(ctx, cfg) }); err != nil {
 			return fmt.Errorf("setting up scheduler: %w", err)
 		}
 
 		log.Info("Setting up API server")
 
-		if err := n.SetupServer(ctx, cfg); err != nil {
+		if err := timings.Time("server", func() error { return n.SetupServer(ctx, cfg) }); err != nil {
 			return fmt.Errorf("setting up API server: %w", err)
 		}
 
+		log.Info("Setting up admin socket")
+
+		if err := timings.Time("admin_socket", func() error { return n.SetupAdminSocket(cfg) }); err != nil {
+			return fmt.Errorf("setting up admin socket: %w", err)
+		}
+
+		log.Info("Setup finished", "duration", timings.Total())
+
 		return nil
 	})
 }
@@ -0,0 +1,110 @@
+package node
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sentinel-official/sentinel-go-sdk/wireguard"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+// serveStatus serves a request with the method on the root path through the router and returns the status.
+func serveStatus(router http.Handler, method string) int {
+	req := httptest.NewRequest(method, "/", http.NoBody)
+	req.RemoteAddr = "203.0.113.7:1234"
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func TestNewRoutersHandshakePort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := config.DefaultConfig()
+	cfg.Node.APIHandshakePort = "8443"
+	cfg.Node.RemoteAddrs = []string{"203.0.113.7"}
+
+	// The remote address registered on-chain points to the port of the handshake router.
+	_, registeredPort, err := net.SplitHostPort(cfg.Node.APIAddrs()[0])
+	if err != nil {
+		t.Fatalf("splitting registered address: %v", err)
+	}
+
+	_, handshakePort, err := net.SplitHostPort(cfg.Node.APIHandshakeListenAddr())
+	if err != nil {
+		t.Fatalf("splitting handshake listen address: %v", err)
+	}
+
+	if registeredPort != handshakePort {
+		t.Fatalf("expected registered port %q, got %q", handshakePort, registeredPort)
+	}
+
+	c := core.NewContext().
+		WithAPIHandshakeListenAddr(cfg.Node.APIHandshakeListenAddr()).
+		WithService(wireguard.NewServer("wireguard", t.TempDir(), nil))
+
+	router, handshakeRouter, err := newRouters(ctx, c, cfg)
+	if err != nil {
+		t.Fatalf("creating routers: %v", err)
+	}
+
+	if handshakeRouter == nil {
+		t.Fatal("expected a handshake router")
+	}
+
+	// Clients query the info at the registered address, as well as on the API port.
+	if code := serveStatus(handshakeRouter, http.MethodGet); code != http.StatusOK {
+		t.Fatalf("expected info status %d at the registered address, got %d", http.StatusOK, code)
+	}
+
+	if code := serveStatus(router, http.MethodGet); code != http.StatusOK {
+		t.Fatalf("expected info status %d on the API port, got %d", http.StatusOK, code)
+	}
+
+	// The handshake route is served at the registered address only.
+	if code := serveStatus(handshakeRouter, http.MethodPost); code == http.StatusNotFound {
+		t.Fatal("expected the handshake route at the registered address")
+	}
+
+	if code := serveStatus(router, http.MethodPost); code != http.StatusNotFound {
+		t.Fatalf("expected no handshake route on the API port, got status %d", code)
+	}
+}
+
+func TestNewRoutersSharedPort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := config.DefaultConfig()
+	c := core.NewContext().WithService(wireguard.NewServer("wireguard", t.TempDir(), nil))
+
+	router, handshakeRouter, err := newRouters(ctx, c, cfg)
+	if err != nil {
+		t.Fatalf("creating routers: %v", err)
+	}
+
+	if handshakeRouter != nil {
+		t.Fatal("expected no handshake router")
+	}
+
+	if code := serveStatus(router, http.MethodGet); code != http.StatusOK {
+		t.Fatalf("expected info status %d, got %d", http.StatusOK, code)
+	}
+
+	if code := serveStatus(router, http.MethodPost); code == http.StatusNotFound {
+		t.Fatal("expected the handshake route on the API port")
+	}
+}
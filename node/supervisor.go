@@ -0,0 +1,69 @@
+package node
+
+import (
+	"context"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+	"github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
+)
+
+// supervisedWorker wraps a scheduler worker and raises an alert when its runs fail consecutively.
+// A run counts as failed once it has exhausted all of its retry attempts.
+type supervisedWorker struct {
+	cron.Worker
+
+	ctx       *core.Context
+	failures  uint
+	threshold uint
+	webhook   bool
+}
+
+// newSupervisedWorker wraps the worker with failure supervision using the given alert threshold.
+func newSupervisedWorker(c *core.Context, w cron.Worker, threshold uint, webhook bool) *supervisedWorker {
+	return &supervisedWorker{
+		Worker:    w,
+		ctx:       c,
+		threshold: threshold,
+		webhook:   webhook,
+	}
+}
+
+// Run executes the wrapped worker and resets the failure count when it succeeds.
+func (w *supervisedWorker) Run(ctx context.Context) error {
+	if err := w.Worker.Run(ctx); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if w.failures >= w.threshold {
+		log.Info("Scheduler worker recovered", "name", w.Name(), "failures", w.failures)
+	}
+
+	w.failures = 0
+
+	return nil
+}
+
+// OnError counts the failed run, raises an alert when the threshold is crossed, and defers to the wrapped worker.
+func (w *supervisedWorker) OnError(err error) bool {
+	w.failures++
+
+	if w.failures == w.threshold {
+		log.Error("Scheduler worker failing persistently",
+			"name", w.Name(), "failures", w.failures, "interval", w.Interval().String(), "cause", err,
+		)
+
+		if w.webhook {
+			w.ctx.EmitEvent(webhook.EventTypeWorkerAlert, map[string]interface{}{
+				"error":    err.Error(),
+				"failures": w.failures,
+				"interval": w.Interval().String(),
+				"worker":   w.Name(),
+			})
+		}
+	}
+
+	return w.Worker.OnError(err)
+}
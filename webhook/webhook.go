@@ -0,0 +1,385 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
+)
+
+// Webhook event types emitted by the node.
+const (
+	EventTypeNodeRecovered          = "node_recovered"            // The node has been set active again after lapsing to inactive on-chain.
+	EventTypeNodeRegistered         = "node_registered"           // The node has been registered on-chain.
+	EventTypeNodeStatusUpdateFailed = "node_status_update_failed" // An update of the on-chain status of the node has failed.
+	EventTypePeerAdded              = "peer_added"                // A peer has been added to the service.
+	EventTypePeerRemoved            = "peer_removed"              // A peer has been removed from the service.
+	EventTypeSessionLimitExceeded   = "session_limit_exceeded"    // The peer of a session has been removed for exceeding its limits.
+	EventTypeWorkerAlert            = "worker_alert"              // A scheduler worker has failed persistently.
+)
+
+// Event represents a single event delivered to the webhook endpoint.
+type Event struct {
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Type      string      `json:"type"`
+}
+
+// NewEvent creates a new Event of the given type with the current timestamp.
+func NewEvent(eventType string, data interface{}) *Event {
+	return &Event{
+		Data:      data,
+		Timestamp: time.Now().UTC(),
+		Type:      eventType,
+	}
+}
+
+// SignatureHeader is the header of the HMAC-SHA256 signature of the request body, if a secret is set.
+const SignatureHeader = "X-Webhook-Signature"
+
+// Dispatcher buffers events and delivers them in batches to a webhook endpoint in the background.
+// On shutdown, the pending events are flushed within the flush timeout and the remaining ones are optionally
+// saved to the pending file for delivery on the next start.
+type Dispatcher struct {
+	batchSize     int
+	client        *http.Client
+	flushTimeout  time.Duration
+	pendingFile   string
+	queue         chan *Event
+	retryAttempts uint
+	retryDelay    time.Duration
+	secret        string
+	sendInterval  time.Duration
+	url           string
+
+	mu       sync.Mutex
+	closed   bool
+	done     chan struct{}
+	inflight []*Event
+	running  bool
+}
+
+// NewDispatcher creates a new Dispatcher delivering to the URL with a queue of the given size.
+func NewDispatcher(url string, queueSize uint) *Dispatcher {
+	return &Dispatcher{
+		batchSize:     1,
+		client:        &http.Client{},
+		done:          make(chan struct{}),
+		queue:         make(chan *Event, queueSize),
+		retryAttempts: 1,
+		url:           url,
+	}
+}
+
+// WithBatchSize sets the maximum number of events per request and returns the updated dispatcher.
+func (d *Dispatcher) WithBatchSize(size uint) *Dispatcher {
+	d.batchSize = int(size)
+
+	return d
+}
+
+// WithFlushTimeout sets the maximum duration for delivering the pending events at shutdown and returns the updated dispatcher.
+func (d *Dispatcher) WithFlushTimeout(timeout time.Duration) *Dispatcher {
+	d.flushTimeout = timeout
+
+	return d
+}
+
+// WithPendingFile sets the file saving the events left undelivered at shutdown, empty to drop them, and returns
+// the updated dispatcher.
+func (d *Dispatcher) WithPendingFile(file string) *Dispatcher {
+	d.pendingFile = file
+
+	return d
+}
+
+// WithRetryAttempts sets the number of delivery attempts per batch and returns the updated dispatcher.
+func (d *Dispatcher) WithRetryAttempts(attempts uint) *Dispatcher {
+	d.retryAttempts = attempts
+
+	return d
+}
+
+// WithRetryDelay sets the base delay between delivery retries and returns the updated dispatcher.
+func (d *Dispatcher) WithRetryDelay(delay time.Duration) *Dispatcher {
+	d.retryDelay = delay
+
+	return d
+}
+
+// WithSecret sets the secret signing the request bodies and returns the updated Dispatcher.
+func (d *Dispatcher) WithSecret(secret string) *Dispatcher {
+	d.secret = secret
+
+	return d
+}
+
+// WithSendInterval sets the minimum interval between deliveries and returns the updated dispatcher.
+func (d *Dispatcher) WithSendInterval(interval time.Duration) *Dispatcher {
+	d.sendInterval = interval
+
+	return d
+}
+
+// WithTimeout sets the timeout of a single delivery request and returns the updated dispatcher.
+func (d *Dispatcher) WithTimeout(timeout time.Duration) *Dispatcher {
+	d.client.Timeout = timeout
+
+	return d
+}
+
+// Enqueue adds the event to the delivery queue without blocking, dropping it if the queue is full or the
+// dispatcher has been shut down.
+func (d *Dispatcher) Enqueue(event *Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		logger.Error("Dropping webhook event", "type", event.Type, "cause", "dispatcher is shut down")
+
+		return
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		logger.Error("Dropping webhook event", "type", event.Type, "cause", "queue is full")
+	}
+}
+
+// LoadPending queues the events saved at the previous shutdown and removes the pending file.
+// An unreadable pending file is logged and ignored.
+func (d *Dispatcher) LoadPending() error {
+	if d.pendingFile == "" {
+		return nil
+	}
+
+	buf, err := os.ReadFile(d.pendingFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("reading pending webhook events file %q: %w", d.pendingFile, err)
+	}
+
+	var events []*Event
+	if err := json.Unmarshal(buf, &events); err != nil {
+		logger.Warn("Ignoring invalid pending webhook events file", "file", d.pendingFile, "cause", err)
+	}
+
+	for _, event := range events {
+		d.Enqueue(event)
+	}
+
+	if err := os.Remove(d.pendingFile); err != nil {
+		return fmt.Errorf("removing pending webhook events file %q: %w", d.pendingFile, err)
+	}
+
+	logger.Info("Loaded pending webhook events", "count", len(events), "file", d.pendingFile)
+
+	return nil
+}
+
+// Shutdown stops accepting events and delivers the pending ones within the flush timeout. It waits for Run to
+// return first, so it must be called after the context of Run is canceled. The events that cannot be delivered
+// in time are saved to the pending file if set, otherwise dropped.
+func (d *Dispatcher) Shutdown() error {
+	log := logger.With("module", "webhook", "name", "dispatcher")
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.flushTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	d.closed = true
+	running := d.running
+	d.mu.Unlock()
+
+	// Wait for Run to hand over its in-flight batch.
+	if running {
+		select {
+		case <-ctx.Done():
+		case <-d.done:
+		}
+	}
+
+	d.mu.Lock()
+	events := d.inflight
+	d.inflight = nil
+	d.mu.Unlock()
+
+collect:
+	for {
+		select {
+		case event := <-d.queue:
+			events = append(events, event)
+		default:
+			break collect
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	log.Info("Flushing pending webhook events", "count", len(events), "timeout", d.flushTimeout)
+
+	for len(events) > 0 && ctx.Err() == nil {
+		batch := events[:min(len(events), d.batchSize)]
+		if err := d.send(ctx, batch); err != nil {
+			log.Error("Failed to flush webhook events", "count", len(batch), "cause", err)
+
+			break
+		}
+
+		events = events[len(batch):]
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	if d.pendingFile == "" {
+		log.Error("Dropping webhook events", "count", len(events), "cause", "flush incomplete")
+
+		return nil
+	}
+
+	buf, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("encoding %d pending webhook event(s): %w", len(events), err)
+	}
+
+	if err := os.WriteFile(d.pendingFile, buf, 0o600); err != nil {
+		return fmt.Errorf("writing pending webhook events file %q: %w", d.pendingFile, err)
+	}
+
+	log.Info("Saved pending webhook events", "count", len(events), "file", d.pendingFile)
+
+	return nil
+}
+
+// Run delivers the queued events in batches until the context is canceled. A batch interrupted by the
+// cancellation is kept for Shutdown.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	log := logger.With("module", "webhook", "name", "dispatcher")
+	batch := make([]*Event, 0, d.batchSize)
+
+	d.mu.Lock()
+	d.running = true
+	d.mu.Unlock()
+
+	defer close(d.done)
+
+	for {
+		// Wait for the first event of the batch.
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-d.queue:
+			batch = append(batch, event)
+		}
+
+		// Collect the remaining queued events up to the batch size.
+	collect:
+		for len(batch) < d.batchSize {
+			select {
+			case event := <-d.queue:
+				batch = append(batch, event)
+			default:
+				break collect
+			}
+		}
+
+		if err := d.send(ctx, batch); err != nil {
+			if ctx.Err() != nil {
+				d.mu.Lock()
+				d.inflight = append(d.inflight, batch...)
+				d.mu.Unlock()
+
+				return nil
+			}
+
+			log.Error("Dropping webhook events", "count", len(batch), "cause", err)
+		}
+
+		batch = batch[:0]
+
+		// Limit the send rate.
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(d.sendInterval):
+		}
+	}
+}
+
+// send delivers a batch of events, retrying with an exponential backoff on failure.
+func (d *Dispatcher) send(ctx context.Context, batch []*Event) error {
+	buf, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("encoding %d webhook event(s): %w", len(batch), err)
+	}
+
+	sendFunc := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(buf))
+		if err != nil {
+			return fmt.Errorf("creating webhook request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+
+		// Sign the body so that the endpoint can verify the origin of the events.
+		if d.secret != "" {
+			req.Header.Set(SignatureHeader, "sha256="+signature(d.secret, buf))
+		}
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("sending webhook request: %w", err)
+		}
+
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected webhook response status %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+
+	if err := retry.Do(
+		sendFunc,
+		retry.Context(ctx),
+		retry.Attempts(d.retryAttempts),
+		retry.Delay(d.retryDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+	); err != nil {
+		return fmt.Errorf("delivering %d webhook event(s): %w", len(batch), err)
+	}
+
+	return nil
+}
+
+// signature returns the hex-encoded HMAC-SHA256 of the body keyed with the secret.
+func signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
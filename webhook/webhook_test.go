@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDispatcherRun(t *testing.T) {
+	const secret = "test-secret"
+
+	received := make(chan []*Event, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %v", err)
+		}
+
+		if got, want := r.Header.Get(SignatureHeader), "sha256="+signature(secret, body); got != want {
+			t.Errorf("expected signature %q, got %q", want, got)
+		}
+
+		var events []*Event
+		if err := json.Unmarshal(body, &events); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+
+		received <- events
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(srv.URL, 4).
+		WithBatchSize(2).
+		WithSecret(secret)
+
+	d.Enqueue(NewEvent(EventTypePeerAdded, nil))
+	d.Enqueue(NewEvent(EventTypePeerRemoved, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errs := make(chan error, 1)
+	go func() { errs <- d.Run(ctx) }()
+
+	select {
+	case events := <-received:
+		if len(events) != 2 || events[0].Type != EventTypePeerAdded || events[1].Type != EventTypePeerRemoved {
+			t.Fatalf("expected a batch of the two queued events, got %+v", events)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for webhook delivery")
+	}
+
+	cancel()
+
+	if err := <-errs; err != nil {
+		t.Fatalf("running dispatcher: %v", err)
+	}
+}
+
+func TestDispatcherPending(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "webhook_pending.json")
+
+	d := NewDispatcher(srv.URL, 4).
+		WithFlushTimeout(time.Second).
+		WithPendingFile(file)
+
+	d.Enqueue(NewEvent(EventTypeNodeRegistered, nil))
+
+	// The endpoint refuses the flush, so the event is saved for the next start.
+	if err := d.Shutdown(); err != nil {
+		t.Fatalf("shutting down dispatcher: %v", err)
+	}
+
+	d = NewDispatcher(srv.URL, 4).WithPendingFile(file)
+	if err := d.LoadPending(); err != nil {
+		t.Fatalf("loading pending events: %v", err)
+	}
+
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Fatalf("expected the pending file to be removed, got %v", err)
+	}
+
+	select {
+	case event := <-d.queue:
+		if event.Type != EventTypeNodeRegistered {
+			t.Fatalf("expected a %q event, got %q", EventTypeNodeRegistered, event.Type)
+		}
+	default:
+		t.Fatal("expected the pending event to be queued")
+	}
+}
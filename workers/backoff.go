@@ -0,0 +1,91 @@
+package workers
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+)
+
+// backoffDelay returns the delay before the retry following the given number of consecutive failures.
+// The delay doubles with each failure starting from base up to maxDelay, and a random jitter replaces up to half of
+// it, so that the delay never exceeds maxDelay and nodes retrying at the cap still spread out.
+func backoffDelay(base, maxDelay time.Duration, failures uint) time.Duration {
+	delay := base
+	for i := uint(1); i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, maxDelay)
+	if half := int64(delay / 2); half > 0 {
+		delay -= time.Duration(rand.Int64N(half + 1))
+	}
+
+	return delay
+}
+
+// withBackoff wraps the handler to wait with an exponential backoff plus jitter before running again after a
+// failure, so that nodes retrying against a flaky endpoint spread out instead of retrying in lockstep.
+// The first retry waits about base and the backoff resets once the handler succeeds or reset is called. The worker
+// using the wrapped handler should have no retry delay of its own.
+func withBackoff(
+	handler func(ctx context.Context) error, base, maxDelay time.Duration,
+) (wrapped func(ctx context.Context) error, reset func()) {
+	var (
+		mu       sync.Mutex
+		failures uint
+	)
+
+	wrapped = func(ctx context.Context) error {
+		mu.Lock()
+		n := failures
+		mu.Unlock()
+
+		if n > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoffDelay(base, maxDelay, n)):
+			}
+		}
+
+		err := handler(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			failures++
+		} else {
+			failures = 0
+		}
+
+		return err
+	}
+
+	reset = func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		failures = 0
+	}
+
+	return wrapped, reset
+}
+
+// newBackoffWorker creates a worker running the handler wrapped by withBackoff. The backoff resets once the retries
+// of a scheduled run are exhausted, so that the next scheduled run starts without waiting.
+func newBackoffWorker(name string, handler func(ctx context.Context) error, base, maxDelay time.Duration) *cron.BasicWorker {
+	wrapped, reset := withBackoff(handler, base, maxDelay)
+
+	return cron.NewBasicWorker(name).
+		WithHandler(wrapped).
+		WithOnError(func(error) bool {
+			reset()
+
+			return false
+		}).
+		WithRetryDelay(0)
+}
@@ -0,0 +1,41 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+const NameBlocklistSync = "blocklist_sync"
+
+// NewBlocklistSyncWorker creates a worker that periodically fetches the remote blocklist shared by a trust group.
+// The verified list replaces the previously fetched one and is merged with the local list by the handshake check.
+func NewBlocklistSyncWorker(c *core.Context, interval time.Duration) cron.Worker {
+	log := logger.With("module", "workers", "name", NameBlocklistSync)
+
+	// Handler function that fetches and applies the remote blocklist.
+	handlerFunc := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		count, err := c.Blocklist().Refresh(ctx)
+		if err != nil {
+			return fmt.Errorf("refreshing remote blocklist: %w", err)
+		}
+
+		log.Info("Updated remote blocklist", "accounts", count)
+
+		return nil
+	}
+
+	// Initialize and return the worker.
+	return cron.NewBasicWorker(NameBlocklistSync).
+		WithHandler(handlerFunc).
+		WithInterval(interval).
+		WithRetryDelay(5 * time.Second)
+}
@@ -15,7 +15,9 @@ const NameGeoIPLocation = "geoip_location"
 
 // NewGeoIPLocationWorker creates a worker to periodically update the GeoIP location in the context.
 // This worker fetches the GeoIP location and updates the context at regular intervals.
-func NewGeoIPLocationWorker(c *core.Context, interval time.Duration) cron.Worker {
+// Failed lookups are retried with an exponential backoff between backoffBase and backoffMax, since the location
+// stays unresolved until the first lookup succeeds.
+func NewGeoIPLocationWorker(c *core.Context, interval, backoffBase, backoffMax time.Duration) cron.Worker {
 	log := logger.With("module", "workers", "name", NameGeoIPLocation)
 
 	// Handler function that fetches the GeoIP location and updates the context.
@@ -33,11 +35,17 @@ func NewGeoIPLocationWorker(c *core.Context, interval time.Duration) cron.Worker
 		log.Debug("Updating context", "city", loc.City, "country", loc.Country)
 		c.SetLocation(loc)
 
+		// Persist the location so that it is available right after a restart.
+		if c.PersistState() {
+			if err := c.SaveState(); err != nil {
+				log.Error("Failed to persist state", "cause", err)
+			}
+		}
+
 		return nil
 	}
 
 	// Initialize and return the worker.
-	return cron.NewBasicWorker(NameGeoIPLocation).
-		WithHandler(handlerFunc).
+	return newBackoffWorker(NameGeoIPLocation, handlerFunc, backoffBase, backoffMax).
 		WithInterval(interval)
 }
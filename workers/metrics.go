@@ -0,0 +1,82 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+	"github.com/sentinel-official/sentinel-dvpnx/metrics"
+)
+
+const NameMetricsSessions = "metrics_sessions"
+
+// NewMetricsSessionsWorker creates a worker that periodically updates the session metrics.
+// This worker derives the status of each session from the database records and the peer statistics of the service.
+func NewMetricsSessionsWorker(c *core.Context, interval time.Duration) cron.Worker {
+	log := logger.With("module", "workers", "name", NameMetricsSessions)
+
+	handlerFunc := func(_ context.Context) error {
+		// Retrieve session records from the database.
+		query := map[string]interface{}{
+			"node_addr":    c.NodeAddr().String(),
+			"service_type": c.Service().Type().String(),
+		}
+
+		items, err := c.SessionStore().Find(query)
+		if err != nil {
+			return fmt.Errorf("retrieving sessions from database: %w", err)
+		}
+
+		// Fetch peer usage statistics from the service.
+		stats, err := c.Service().PeerStatistics()
+		if err != nil {
+			return fmt.Errorf("retrieving peer statistics from service: %w", err)
+		}
+
+		// Aggregate the sessions by derived status.
+		counts := make(map[string]int)
+
+		for _, item := range items {
+			maxBytes := item.GetMaxBytes()
+			maxDuration := item.GetMaxDuration()
+
+			stat, ok := stats[item.GetPeerID()]
+
+			switch {
+			case !maxBytes.IsZero() && item.GetTotalBytes().GTE(maxBytes):
+				counts[metrics.SessionStatusOverLimit]++
+			case maxDuration != 0 && item.GetDuration() >= maxDuration:
+				counts[metrics.SessionStatusOverLimit]++
+			case !ok:
+				counts[metrics.SessionStatusDisconnected]++
+			case time.Since(stat.UpdatedAt) > interval:
+				counts[metrics.SessionStatusIdle]++
+			default:
+				counts[metrics.SessionStatusActive]++
+			}
+		}
+
+		log.Debug("Updating session metrics", "counts", counts)
+		metrics.SetSessions(counts)
+
+		return nil
+	}
+
+	// Initialize and return the worker.
+	return cron.NewBasicWorker(NameMetricsSessions).
+		WithHandler(handlerFunc).
+		WithInterval(interval)
+}
+
+// recordSessionEarnings adds the estimated earnings of a completed session to the metrics for each advertised denom.
+func recordSessionEarnings(c *core.Context, item *models.Session) {
+	_, earnings := core.EstimateSessionEarnings(item, c.GigabytePrices(), c.HourlyPrices())
+	for denom, amount := range earnings {
+		metrics.AddEarnings(denom, amount.MustFloat64())
+	}
+}
@@ -3,25 +3,227 @@ package workers
 import (
 	"context"
 	"fmt"
+	"net"
+	"slices"
 	"time"
 
 	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
 	"github.com/sentinel-official/sentinelhub/v12/types/v1"
 	"github.com/sentinel-official/sentinelhub/v12/x/node/types/v3"
 
 	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/metrics"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
 )
 
 const (
-	NameNodeStatusUpdate = "node_status_update"
-	NameNodePricesUpdate = "node_prices_update"
+	NameNodeRemoteAddrsUpdate = "node_remote_addrs_update"
+	NameNodeStatusCheck       = "node_status_check"
+	NameNodeStatusUpdate      = "node_status_update"
+	NameNodePricesUpdate      = "node_prices_update"
 )
 
+// NewNodeRemoteAddrsUpdateWorker creates a worker to follow changes of the node's public IP address.
+// When no IP remote address of the same family matches the public IP address, the first one is replaced with it and
+// the remote addresses are updated on the blockchain. Once the update is broadcast, a new TLS certificate is issued
+// and served through reloadTLS if the API server serves TLS. Domain name and other IP remote addresses are left
+// unchanged.
+func NewNodeRemoteAddrsUpdateWorker(c *core.Context, interval time.Duration, reloadTLS func() error) cron.Worker {
+	log := logger.With("module", "workers", "name", NameNodeRemoteAddrsUpdate)
+
+	handlerFunc := func(ctx context.Context) error {
+		geoIPCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		// Discover the public IP address using the GeoIP client.
+		loc, err := c.GeoIPClient().Get(geoIPCtx, "")
+		if err != nil {
+			return fmt.Errorf("getting GeoIP location: %w", err)
+		}
+
+		ip := net.ParseIP(loc.IP)
+		if ip == nil {
+			return fmt.Errorf("parsing public IP address %q", loc.IP)
+		}
+
+		// Replace the first IP remote address of the same family as the public IP address, unless one of them
+		// already matches it.
+		current := c.RemoteAddrs()
+
+		addrs, ok := replaceRemoteIP(current, ip)
+		if !ok {
+			log.Debug("Skipping remote addrs update", "cause", "no IP remote addrs of the same family", "ip", ip,
+				"remote_addrs", current)
+
+			return nil
+		}
+
+		if slices.Equal(addrs, current) {
+			return nil
+		}
+
+		log.Info("Public IP address changed", "ip", ip, "remote_addrs", current)
+
+		// Keep the prices of the current pricing window, if any.
+		gigabytePrices, hourlyPrices, err := c.ScheduledPrices(ctx, time.Now())
+		if err != nil {
+			return fmt.Errorf("getting scheduled prices: %w", err)
+		}
+
+		// Broadcast the new remote addresses; the context and the TLS certificate are left unchanged on failure,
+		// so that the next run retries the update.
+		apiAddrs := c.APIAddrsFor(addrs)
+
+		msg := v3.NewMsgUpdateNodeDetailsRequest(
+			c.NodeAddr(),
+			gigabytePrices,
+			hourlyPrices,
+			apiAddrs,
+		)
+
+		if err := c.BroadcastTx(ctx, msg); err != nil {
+			return fmt.Errorf("broadcasting tx with update_node_details msg: %w", err)
+		}
+
+		// Issue and serve a new TLS certificate covering the new remote addresses, unless a reverse proxy
+		// terminates TLS. A certificate issued by a previous run that failed to serve it is kept.
+		if c.APITLSEnable() {
+			if err := core.ValidateTLS(c.HomeDir(), addrs); err != nil {
+				log.Info("Reissuing TLS certificate", "remote_addrs", addrs, "cause", err)
+
+				if err := core.ResetTLS(c.HomeDir(), addrs); err != nil {
+					return fmt.Errorf("resetting TLS certificate for remote addrs %v: %w", addrs, err)
+				}
+			}
+
+			if err := reloadTLS(); err != nil {
+				return fmt.Errorf("reloading TLS certificate: %w", err)
+			}
+
+			log.Info("TLS certificate reloaded", "remote_addrs", addrs)
+		}
+
+		c.SetRemoteAddrs(addrs)
+
+		log.Info("Remote addrs updated successfully", "remote_addrs", apiAddrs)
+
+		return nil
+	}
+
+	// Initialize and return the worker.
+	return cron.NewBasicWorker(NameNodeRemoteAddrsUpdate).
+		WithHandler(handlerFunc).
+		WithInterval(interval).
+		WithRetryDelay(5 * time.Second)
+}
+
+// replaceRemoteIP returns the remote addresses with the first IP address of the same family as ip replaced by it,
+// leaving them unchanged if one of them already equals ip. It reports false if no IP address of that family exists.
+func replaceRemoteIP(addrs []string, ip net.IP) ([]string, bool) {
+	idx := -1
+
+	for i, addr := range addrs {
+		v := net.ParseIP(addr)
+		if v == nil || (v.To4() == nil) != (ip.To4() == nil) {
+			continue
+		}
+
+		if v.Equal(ip) {
+			return addrs, true
+		}
+
+		if idx < 0 {
+			idx = i
+		}
+	}
+
+	if idx < 0 {
+		return nil, false
+	}
+
+	res := slices.Clone(addrs)
+	res[idx] = ip.String()
+
+	return res, true
+}
+
+// NewNodeStatusCheckWorker creates a worker to periodically check the node's own status on the blockchain.
+// While the node is inactive, new handshakes are rejected and existing peers are optionally removed.
+func NewNodeStatusCheckWorker(c *core.Context, interval time.Duration) cron.Worker {
+	log := logger.With("module", "workers", "name", NameNodeStatusCheck)
+
+	handlerFunc := func(ctx context.Context) error {
+		// Query the node details from the blockchain.
+		node, err := c.Client().Node(ctx, c.NodeAddr())
+		if err != nil {
+			return fmt.Errorf("querying node %q from blockchain: %w", c.NodeAddr(), err)
+		}
+
+		inactive := node == nil || !node.Status.Equal(v1.StatusActive)
+		if inactive != c.Inactive() {
+			log.Info("Updating node status in context", "inactive", inactive)
+			c.SetInactive(inactive)
+		}
+
+		if !inactive || !c.RemovePeersIfInactive() {
+			return nil
+		}
+
+		// Remove the existing peers from the service.
+		items, err := c.Service().PeerStatistics()
+		if err != nil {
+			return fmt.Errorf("retrieving peer statistics from service: %w", err)
+		}
+
+		for peerID := range items {
+			log.Debug("Removing peer from service", "peer_id", peerID, "cause", "inactive node")
+
+			if err := c.RemovePeerIfExists(ctx, peerID); err != nil {
+				return fmt.Errorf("removing peer %q from service: %w", peerID, err)
+			}
+		}
+
+		return nil
+	}
+
+	// Initialize and return the worker.
+	return cron.NewBasicWorker(NameNodeStatusCheck).
+		WithHandler(handlerFunc).
+		WithInterval(interval).
+		WithRetryDelay(5 * time.Second)
+}
+
 // NewNodeStatusUpdateWorker creates a worker to periodically update the node's status to active on the blockchain.
-// This worker broadcasts a transaction to mark the node as active at regular intervals.
-func NewNodeStatusUpdateWorker(c *core.Context, interval time.Duration) cron.Worker {
+// This worker broadcasts a transaction to mark the node as active at regular intervals. When the node had lapsed
+// to inactive before the update, the recovery is logged, recorded in the metrics, and emitted as an event.
+// When the node is no longer found on-chain and register is not nil, it is called to register the node again before
+// the update. Failed updates and registrations are retried with an exponential backoff between backoffBase and
+// backoffMax.
+func NewNodeStatusUpdateWorker(
+	c *core.Context, interval, backoffBase, backoffMax time.Duration, register func(context.Context) error,
+) cron.Worker {
+	log := logger.With("module", "workers", "name", NameNodeStatusUpdate)
+
 	// Handler function that updates the node's status to active.
 	handlerFunc := func(ctx context.Context) error {
+		// Query the prior status of the node to detect a recovery from the inactive status.
+		node, err := c.Client().Node(ctx, c.NodeAddr())
+		if err != nil {
+			log.Warn("Failed to query prior node status", "cause", err)
+		}
+
+		// Register the node again if it disappeared from the blockchain, such as after a chain state reset.
+		if err == nil && node == nil && register != nil {
+			log.Warn("Node not found on-chain, registering again", "addr", c.NodeAddr())
+
+			if err := register(ctx); err != nil {
+				return fmt.Errorf("re-registering node: %w", err)
+			}
+
+			log.Info("Node registered again", "addr", c.NodeAddr())
+		}
+
 		// Create a message to update the node's status to active.
 		msg := v3.NewMsgUpdateNodeStatusRequest(
 			c.AccAddr().Bytes(),
@@ -30,60 +232,78 @@ func NewNodeStatusUpdateWorker(c *core.Context, interval time.Duration) cron.Wor
 
 		// Broadcast the transaction message to the blockchain.
 		if err := c.BroadcastTx(ctx, msg); err != nil {
+			c.EmitEvent(webhook.EventTypeNodeStatusUpdateFailed, map[string]interface{}{
+				"cause": err.Error(),
+			})
+
 			return fmt.Errorf("broadcasting tx with update_node_status msg: %w", err)
 		}
 
+		if node == nil || !node.Status.Equal(v1.StatusInactive) {
+			return nil
+		}
+
+		var downtime time.Duration
+		if !node.StatusAt.IsZero() {
+			downtime = time.Since(node.StatusAt).Truncate(time.Second)
+		}
+
+		log.Warn("Node status recovered from inactive", "inactive_at", node.StatusAt, "downtime", downtime)
+		metrics.ObserveNodeRecovery(downtime)
+
+		c.EmitEvent(webhook.EventTypeNodeRecovered, map[string]interface{}{
+			"downtime":    downtime.String(),
+			"inactive_at": node.StatusAt,
+		})
+
 		return nil
 	}
 
 	// Initialize and return the worker.
-	return cron.NewBasicWorker(NameNodeStatusUpdate).
-		WithHandler(handlerFunc).
-		WithInterval(interval).
-		WithRetryDelay(5 * time.Second)
+	return newBackoffWorker(NameNodeStatusUpdate, handlerFunc, backoffBase, backoffMax).
+		WithInterval(interval)
 }
 
 // NewNodePricesUpdateWorker creates a worker that periodically updates the node's prices on the blockchain.
-// The worker computes the current quote prices using the OracleClient and broadcasts a MsgUpdateNodeDetailsRequest.
+// The prices of the current window of the pricing schedule, or the node prices outside every window, are
+// broadcast in a MsgUpdateNodeDetailsRequest. Their quote values are updated using the OracleClient if one is
+// set; without an oracle, the prices are broadcast only when they change, such as when a pricing window starts.
 func NewNodePricesUpdateWorker(c *core.Context, interval time.Duration) cron.Worker {
-	handlerFunc := func(ctx context.Context) (err error) {
+	log := logger.With("module", "workers", "name", NameNodePricesUpdate)
+
+	// The fixed prices most recently broadcast, used to skip broadcasting unchanged prices without an oracle.
+	var lastBroadcast string
+
+	handlerFunc := func(ctx context.Context) error {
 		client := c.OracleClient()
-		if client == nil {
+		if client == nil && len(c.PricingSchedule()) == 0 {
 			return nil
 		}
 
-		var (
-			prices         v1.Prices
-			gigabytePrices v1.Prices
-			hourlyPrices   v1.Prices
-		)
-
-		prices, err = c.SanitizedGigabytePrices(ctx)
+		gigabytePrices, hourlyPrices, err := c.ScheduledPrices(ctx, time.Now())
 		if err != nil {
-			return fmt.Errorf("sanitizing gigabyte prices: %w", err)
+			return fmt.Errorf("getting scheduled prices: %w", err)
 		}
 
-		for _, price := range prices {
-			price, err := price.UpdateQuoteValue(ctx, client.GetQuotePrice)
-			if err != nil {
-				return fmt.Errorf("updating quote price for denom %q: %w", price.Denom, err)
-			}
+		// Fixed prices are identified by their string form to detect a change.
+		key := gigabytePrices.String() + ";" + hourlyPrices.String()
 
-			gigabytePrices = gigabytePrices.Add(price)
-		}
-
-		prices, err = c.SanitizedHourlyPrices(ctx)
-		if err != nil {
-			return fmt.Errorf("sanitizing hourly prices: %w", err)
-		}
+		if client != nil {
+			if gigabytePrices, err = updateQuoteValues(ctx, gigabytePrices, client.GetQuotePrice); err != nil {
+				return err
+			}
 
-		for _, price := range prices {
-			price, err := price.UpdateQuoteValue(ctx, client.GetQuotePrice)
-			if err != nil {
-				return fmt.Errorf("updating quote price for denom %q: %w", price.Denom, err)
+			if hourlyPrices, err = updateQuoteValues(ctx, hourlyPrices, client.GetQuotePrice); err != nil {
+				return err
 			}
+		} else if key == lastBroadcast {
+			return nil
+		}
 
-			hourlyPrices = hourlyPrices.Add(price)
+		if window := c.PricingWindow(time.Now()); window != nil {
+			log.Info("Applying prices of pricing window",
+				"start", window.Start, "end", window.End, "gigabyte_prices", gigabytePrices, "hourly_prices", hourlyPrices,
+			)
 		}
 
 		// Construct the message to update node details with new prices.
@@ -99,12 +319,49 @@ func NewNodePricesUpdateWorker(c *core.Context, interval time.Duration) cron.Wor
 			return fmt.Errorf("broadcasting tx with update_node_details msg: %w", err)
 		}
 
+		lastBroadcast = key
+
 		return nil
 	}
 
 	// Initialize and return the worker.
-	return cron.NewBasicWorker(NameNodePricesUpdate).
-		WithHandler(handlerFunc).
-		WithInterval(interval).
-		WithRetryDelay(5 * time.Second)
+	return &pricesUpdateWorker{
+		BasicWorker: cron.NewBasicWorker(NameNodePricesUpdate).
+			WithHandler(handlerFunc).
+			WithInterval(interval).
+			WithRetryDelay(5 * time.Second),
+		c: c,
+	}
+}
+
+// pricesUpdateWorker is the prices update worker, which also runs at the start and end of each pricing window so
+// that the prices of a window are applied when it starts rather than at the next interval.
+type pricesUpdateWorker struct {
+	*cron.BasicWorker
+
+	c *core.Context
+}
+
+// Interval returns the interval of the worker, shortened to end at the next start or end of a pricing window.
+func (w *pricesUpdateWorker) Interval() time.Duration {
+	interval := w.BasicWorker.Interval()
+	if d := w.c.NextPricingWindowChange(time.Now()); d > 0 {
+		return min(interval, d)
+	}
+
+	return interval
+}
+
+// updateQuoteValues returns the prices with their quote values updated from the quote price function.
+func updateQuoteValues(ctx context.Context, prices v1.Prices, fn v1.GetQuotePriceFunc) (items v1.Prices, err error) {
+	for _, price := range prices {
+		price, err := price.UpdateQuoteValue(ctx, fn)
+		if err != nil {
+			return nil, fmt.Errorf("updating quote price for denom %q: %w", price.Denom, err)
+		}
+
+		items = items.Add(price)
+	}
+
+	return items, nil
 }
@@ -0,0 +1,102 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/metrics"
+)
+
+const (
+	NamePeerReconcile = "peer_reconcile"
+)
+
+// peerReconcileGracePeriod is the age below which a peer is not considered orphaned, since a handshake in progress
+// adds the peer to the service shortly before inserting its session record.
+const peerReconcileGracePeriod = time.Minute
+
+// NewPeerReconcileWorker creates a worker that periodically checks the session records against the peers of the service.
+// This worker reports orphaned peers without a session record and missing peers of session records, logs a warning
+// when their total exceeds the tolerance, and optionally removes the orphaned peers from the service.
+func NewPeerReconcileWorker(c *core.Context, interval time.Duration, tolerance uint, removeOrphans bool) cron.Worker {
+	log := logger.With("module", "workers", "name", NamePeerReconcile)
+
+	handlerFunc := func(ctx context.Context) error {
+		// Retrieve session records from the database.
+		query := map[string]interface{}{
+			"node_addr":    c.NodeAddr().String(),
+			"service_type": c.Service().Type().String(),
+		}
+
+		items, err := c.SessionStore().Find(query)
+		if err != nil {
+			return fmt.Errorf("retrieving sessions from database: %w", err)
+		}
+
+		// Fetch the peers of the service.
+		stats, err := c.Service().PeerStatistics()
+		if err != nil {
+			return fmt.Errorf("retrieving peer statistics from service: %w", err)
+		}
+
+		// Count the session records whose peer is missing from the service.
+		var (
+			missing int
+			records = make(map[string]bool)
+		)
+
+		for _, item := range items {
+			if core.IsReleasedPeerID(item.GetPeerID()) {
+				continue
+			}
+
+			records[item.GetPeerID()] = true
+			if _, ok := stats[item.GetPeerID()]; !ok {
+				missing++
+			}
+		}
+
+		// Collect the peers of the service without a session record.
+		var orphans []string
+
+		for id, stat := range stats {
+			if !records[id] && time.Since(stat.CreatedAt) > peerReconcileGracePeriod {
+				orphans = append(orphans, id)
+			}
+		}
+
+		metrics.SetPeerDivergence(len(orphans), missing)
+
+		if uint(len(orphans)+missing) > tolerance {
+			log.Warn("Sessions and peers diverge",
+				"sessions", len(records), "peers", len(stats), "orphaned", len(orphans), "missing", missing,
+				"tolerance", tolerance,
+			)
+		}
+
+		if !removeOrphans {
+			return nil
+		}
+
+		// Remove the orphaned peers from the service.
+		for _, id := range orphans {
+			log.Info("Removing orphaned peer from service", "peer_id", id)
+
+			if err := c.RemovePeerIfExists(ctx, id); err != nil {
+				return fmt.Errorf("removing orphaned peer %q from service: %w", id, err)
+			}
+		}
+
+		return nil
+	}
+
+	// Initialize and return the worker.
+	return cron.NewBasicWorker(NamePeerReconcile).
+		WithHandler(handlerFunc).
+		WithInterval(interval)
+}
@@ -37,13 +37,19 @@ func NewBestRPCAddrWorker(c *core.Context, interval time.Duration) cron.Worker {
 			go func(addr string) {
 				defer wg.Done()
 
+				// Skip probing addresses backed off after rate limiting the node.
+				if b := c.RPCBackoff(); b != nil && b.IsBackedOff(addr) {
+					return
+				}
+
 				endpoint, err := url.JoinPath(addr, "/status")
 				if err != nil {
 					return
 				}
 
 				// Create the HTTP GET request to the endpoint.
-				req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+				// Probe the address itself instead of falling back to the next one.
+				req, err := http.NewRequestWithContext(core.WithoutRPCFallback(ctx), http.MethodGet, endpoint, nil)
 				if err != nil {
 					return
 				}
@@ -78,6 +84,16 @@ func NewBestRPCAddrWorker(c *core.Context, interval time.Duration) cron.Worker {
 		// Wait for all goroutines to complete.
 		wg.Wait()
 
+		// Collect the addresses backed off after rate limiting the node, including during this run.
+		var backedOff []string
+		if b := c.RPCBackoff(); b != nil {
+			for _, addr := range addrs {
+				if _, ok := latencies[addr]; !ok && b.IsBackedOff(addr) {
+					backedOff = append(backedOff, addr)
+				}
+			}
+		}
+
 		// Return early if no RPC addresses are available.
 		if len(latencies) == 0 {
 			return nil
@@ -93,6 +109,9 @@ func NewBestRPCAddrWorker(c *core.Context, interval time.Duration) cron.Worker {
 			return latencies[addrs[i]] < latencies[addrs[j]]
 		})
 
+		// Demote the backed off addresses to the end instead of dropping them.
+		addrs = append(addrs, backedOff...)
+
 		log.Debug("Updating context", "addrs", addrs)
 		c.SetRPCAddrs(addrs)
 
@@ -0,0 +1,73 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
+	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+)
+
+const NameServiceHealth = "service_health"
+
+// NewServiceHealthWorker creates a worker that periodically checks that the service is running and restarts it if
+// it has stopped unexpectedly. The context of every started service is passed to restarted, so that the node waits
+// on the new service. After maxRestarts consecutive failed restarts, shutdown is called so that the process
+// supervisor can restart the whole node.
+func NewServiceHealthWorker(
+	c *core.Context, cfg *config.Config, interval time.Duration, maxRestarts uint,
+	restarted func(context.Context), shutdown func(error),
+) cron.Worker {
+	log := logger.With("module", "workers", "name", NameServiceHealth)
+
+	var failures uint
+
+	// Handler function that checks the service and restarts it if it is down.
+	handlerFunc := func(ctx context.Context) error {
+		ok, err := c.Service().IsRunning()
+		if err != nil {
+			return fmt.Errorf("checking service status: %w", err)
+		}
+
+		if ok {
+			failures = 0
+
+			return nil
+		}
+
+		log.Error("Service is not running, restarting it", "type", c.Service().Type(), "failures", failures)
+
+		serviceCtx, err := c.RestartService(ctx, cfg)
+		if serviceCtx != nil {
+			restarted(serviceCtx)
+		}
+
+		if err != nil {
+			failures++
+
+			log.Error("Failed to restart service", "failures", failures, "max_restarts", maxRestarts, "cause", err)
+
+			if failures >= maxRestarts {
+				log.Error("Shutting down node after failing to restart service", "failures", failures)
+				shutdown(fmt.Errorf("restarting service %d time(s): %w", failures, err))
+			}
+
+			return fmt.Errorf("restarting service: %w", err)
+		}
+
+		log.Warn("Service has been restarted", "type", c.Service().Type(), "peers", c.Service().PeersLen())
+		failures = 0
+
+		return nil
+	}
+
+	// Initialize and return the worker.
+	return cron.NewBasicWorker(NameServiceHealth).
+		WithHandler(handlerFunc).
+		WithInterval(interval).
+		WithRetryAttempts(1)
+}
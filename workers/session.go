@@ -2,11 +2,13 @@ package workers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"cosmossdk.io/math"
+	"github.com/avast/retry-go/v4"
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
 	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
@@ -14,41 +16,55 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
 	"github.com/sentinel-official/sentinel-dvpnx/database/operations"
+	"github.com/sentinel-official/sentinel-dvpnx/events"
+	"github.com/sentinel-official/sentinel-dvpnx/metrics"
+	"github.com/sentinel-official/sentinel-dvpnx/webhook"
 )
 
 const (
+	NameSessionRetention               = "session_retention"
 	NameSessionUsageSyncWithBlockchain = "session_usage_sync_with_blockchain"
 	NameSessionUsageSyncWithDatabase   = "session_usage_sync_with_database"
 	NameSessionUsageValidate           = "session_usage_validate"
 	NameSessionValidate                = "session_validate"
 )
 
-// NewSessionUsageSyncWithBlockchainWorker creates a worker that synchronizes session usage with the blockchain.
-// This worker retrieves session data from the database, validates it against the blockchain,
-// and broadcasts any updates as transactions.
-func NewSessionUsageSyncWithBlockchainWorker(c *core.Context, interval time.Duration) cron.Worker {
-	log := logger.With("module", "workers", "name", NameSessionUsageSyncWithBlockchain)
+// sessionPageSize is the number of session records loaded from the database at once by the workers iterating over
+// all the sessions of the node.
+const sessionPageSize = 500
 
-	handlerFunc := func(ctx context.Context) error {
-		// Retrieve session records from the database.
-		query := map[string]interface{}{
-			"node_addr": c.NodeAddr().String(),
-		}
+// sessionUpdate holds an update message of a session and the total bytes it confirms once broadcast.
+type sessionUpdate struct {
+	id         uint64
+	msg        types.Msg
+	totalBytes math.Int
+}
 
-		items, err := operations.SessionFind(c.Database(), query)
-		if err != nil {
-			return fmt.Errorf("retrieving sessions from database: %w", err)
-		}
+// SyncSessionUsageWithBlockchain broadcasts the usage of the node's sessions that is not yet confirmed on the
+// blockchain, and records the confirmed usage in the database. At most concurrency sessions are queried at once.
+// The update messages are broadcast in transactions of at most batchSize messages; a failed batch does not stop
+// the remaining ones, and the errors of all failed batches are returned together.
+func SyncSessionUsageWithBlockchain(ctx context.Context, c *core.Context, concurrency, batchSize uint) error {
+	log := logger.With("module", "workers", "name", NameSessionUsageSyncWithBlockchain)
 
-		// Prepare a slice to collect messages.
-		var (
-			msgs []types.Msg
-			mu   sync.Mutex
-		)
+	// Retrieve session records from the database.
+	query := map[string]interface{}{
+		"node_addr": c.NodeAddr().String(),
+	}
+
+	// Prepare a slice to collect the update messages along with the total bytes they confirm.
+	var (
+		updates []sessionUpdate
+		mu      sync.Mutex
+	)
 
+	// Process the sessions page by page so that only one page of records is held in memory at once.
+	pageFunc := func(items []models.Session) error {
 		jobGroup, jobCtx := errgroup.WithContext(ctx)
-		jobGroup.SetLimit(2)
+		jobGroup.SetLimit(int(concurrency))
 
 		// Iterate over sessions and prepare messages for updates.
 		for _, val := range items {
@@ -61,6 +77,16 @@ func NewSessionUsageSyncWithBlockchainWorker(c *core.Context, interval time.Dura
 				default:
 				}
 
+				// Skip session if its usage is already confirmed on the blockchain
+				totalBytes := item.GetTotalBytes()
+				if totalBytes.Equal(item.GetLastSyncedBytes()) {
+					log.Debug("Skipping session",
+						"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "already synced",
+					)
+
+					return nil
+				}
+
 				session, err := c.Client().Session(jobCtx, item.GetID())
 				if err != nil {
 					return fmt.Errorf("querying session %d from blockchain: %w", item.GetID(), err)
@@ -75,26 +101,36 @@ func NewSessionUsageSyncWithBlockchainWorker(c *core.Context, interval time.Dura
 					return nil
 				}
 
-				// Skip session if it is already up-to-date
+				// Skip session if it is already up-to-date and mark its usage as synced
 				if session.GetUploadBytes().Equal(item.GetRxBytes()) {
 					log.Debug("Skipping session",
 						"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "already up-to-date",
 					)
 
+					if err := updateLastSyncedBytes(c, item.GetID(), totalBytes); err != nil {
+						return err
+					}
+
 					return nil
 				}
 
 				// Generate an update message for the session.
 				msg := item.MsgUpdateSessionRequest()
 				log.Debug("Adding session to update list",
-					"id", item.GetID(), "peer_id", item.GetPeerID(), "download_bytes", msg.DownloadBytes,
-					"duration", msg.Duration, "upload_bytes", msg.UploadBytes,
+					"id", item.GetID(), "peer_id", item.GetPeerID(), "download_bytes", c.DisplayBytes(msg.DownloadBytes),
+					"duration", msg.Duration, "upload_bytes", c.DisplayBytes(msg.UploadBytes),
 				)
 
+				// Record a snapshot event of the session usage.
+				event := models.NewSessionEventFromSession(&item, models.SessionEventTypeSnapshot)
+				if err := operations.SessionEventInsertOne(c.Database(), event); err != nil {
+					return fmt.Errorf("inserting snapshot event for session %d into database: %w", item.GetID(), err)
+				}
+
 				mu.Lock()
 				defer mu.Unlock()
 
-				msgs = append(msgs, msg)
+				updates = append(updates, sessionUpdate{id: item.GetID(), msg: msg, totalBytes: totalBytes})
 
 				return nil
 			})
@@ -105,89 +141,223 @@ func NewSessionUsageSyncWithBlockchainWorker(c *core.Context, interval time.Dura
 			return fmt.Errorf("waiting job group: %w", err)
 		}
 
-		// Broadcast the prepared messages as a transaction.
+		return nil
+	}
+
+	if err := forEachSessionPage(c, query, pageFunc); err != nil {
+		return err
+	}
+
+	// Broadcast the prepared messages in batches so that a single transaction stays within the block gas limit.
+	var errs []error
+
+	for start := 0; start < len(updates); start += int(batchSize) {
+		batch := updates[start:min(start+int(batchSize), len(updates))]
+
+		msgs := make([]types.Msg, 0, len(batch))
+		for _, update := range batch {
+			msgs = append(msgs, update.msg)
+		}
+
 		if err := c.BroadcastTx(ctx, msgs...); err != nil {
-			return fmt.Errorf("broadcasting tx with %d update_session msg(s): %w", len(msgs), err)
+			log.Error("Failed to broadcast batch of update_session msgs",
+				"from", start, "msgs", len(msgs), "total", len(updates), "cause", err,
+			)
+
+			errs = append(errs, fmt.Errorf("broadcasting tx with %d update_session msg(s): %w", len(msgs), err))
+
+			continue
 		}
 
-		return nil
+		// Persist the confirmed usage so only unconfirmed updates are sent again.
+		for _, update := range batch {
+			if err := updateLastSyncedBytes(c, update.id, update.totalBytes); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		log.Info("Broadcasted batch of update_session msgs",
+			"done", start+len(batch), "msgs", len(msgs), "total", len(updates),
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewSessionUsageSyncWithBlockchainWorker creates a worker that synchronizes session usage with the blockchain.
+// This worker retrieves session data from the database, validates it against the blockchain,
+// and broadcasts any updates as transactions. Failed runs are retried with an exponential backoff between
+// backoffBase and backoffMax, at most concurrency sessions are queried at once, and the updates are broadcast
+// in transactions of at most batchSize messages.
+func NewSessionUsageSyncWithBlockchainWorker(
+	c *core.Context, interval, backoffBase, backoffMax time.Duration, concurrency, batchSize uint,
+) cron.Worker {
+	handlerFunc := func(ctx context.Context) error {
+		return SyncSessionUsageWithBlockchain(ctx, c, concurrency, batchSize)
 	}
 
 	// Initialize and return the worker.
-	return cron.NewBasicWorker(NameSessionUsageSyncWithBlockchain).
-		WithHandler(handlerFunc).
-		WithInterval(interval).
-		WithRetryDelay(5 * time.Second)
+	return newBackoffWorker(NameSessionUsageSyncWithBlockchain, handlerFunc, backoffBase, backoffMax).
+		WithInterval(interval)
 }
 
-// NewSessionUsageSyncWithDatabaseWorker creates a worker that updates session usage in the database.
-// This worker fetches usage data from the peer service and updates the corresponding database records.
-func NewSessionUsageSyncWithDatabaseWorker(c *core.Context, interval time.Duration) cron.Worker {
+// SyncSessionUsageWithDatabase records the usage statistics of the peers in the service in their session records.
+// Statistics not updated within interval are skipped unless idle tracking is enabled, and none are skipped if
+// interval is zero. At most concurrency sessions are processed at once.
+func SyncSessionUsageWithDatabase(ctx context.Context, c *core.Context, interval time.Duration, concurrency uint) error {
 	log := logger.With("module", "workers", "name", NameSessionUsageSyncWithDatabase)
 
-	handlerFunc := func(ctx context.Context) error {
-		// Fetch peer usage statistics from the service.
-		items, err := c.Service().PeerStatistics()
-		if err != nil {
-			return fmt.Errorf("retrieving peer statistics from service: %w", err)
-		}
+	// Fetch peer usage statistics from the service.
+	items, err := c.Service().PeerStatistics()
+	if err != nil {
+		return fmt.Errorf("retrieving peer statistics from service: %w", err)
+	}
 
-		jobGroup, jobCtx := errgroup.WithContext(ctx)
-		jobGroup.SetLimit(2)
+	jobGroup, jobCtx := errgroup.WithContext(ctx)
+	jobGroup.SetLimit(int(concurrency))
 
-		// Update the database with the fetched statistics.
-		for key, val := range items {
-			peerID, item := key, val
+	// Update the database with the fetched statistics.
+	for key, val := range items {
+		peerID, item := key, val
 
-			jobGroup.Go(func() error {
-				select {
-				case <-jobCtx.Done():
-					return nil
-				default:
-				}
+		jobGroup.Go(func() error {
+			select {
+			case <-jobCtx.Done():
+				return nil
+			default:
+			}
+
+			// Skip stale statistics unless idle tracking needs them to account for silent peers.
+			threshold, _ := c.SessionIdle()
+			if threshold == 0 && interval > 0 && time.Since(item.UpdatedAt) > interval {
+				log.Debug("Skipping session",
+					"id", 0, "peer_id", peerID, "cause", "already up-to-date",
+					"updated_at", item.UpdatedAt,
+				)
 
-				if time.Since(item.UpdatedAt) > interval {
-					log.Debug("Skipping session",
-						"id", 0, "peer_id", peerID, "cause", "already up-to-date",
-						"updated_at", item.UpdatedAt,
-					)
+				return nil
+			}
 
-					return nil
-				}
+			// Define query to find the session by peer id.
+			query := map[string]interface{}{
+				"peer_id": peerID,
+			}
 
-				// Convert usage statistics to strings for database storage.
-				rxBytes := math.NewInt(item.RxBytes).String()
-				txBytes := math.NewInt(item.TxBytes).String()
+			session, err := c.SessionStore().FindOne(query)
+			if err != nil {
+				return fmt.Errorf("retrieving session for peer %q from database: %w", peerID, err)
+			}
 
-				// Define query to find the session by peer id.
-				query := map[string]interface{}{
-					"peer_id": peerID,
-				}
+			if session == nil {
+				return nil
+			}
+
+			// Add the bytes carried over from a previous node instance to the usage statistics.
+			rxBytes := session.GetRxBytesBase().Add(math.NewInt(item.RxBytes))
+			txBytes := session.GetTxBytesBase().Add(math.NewInt(item.TxBytes))
+
+			// Drop usage implying a throughput beyond the measured speeds of the node, so that it is never billed.
+			if multiplier, action := c.UsageAnomaly(); multiplier > 0 {
+				dlSpeed, ulSpeed := c.SpeedtestResults()
+				if err := checkUsagePlausible(session, rxBytes, txBytes, dlSpeed, ulSpeed, multiplier); err != nil {
+					log.Warn("Dropping implausible session usage",
+						"id", session.GetID(), "peer_id", peerID, "action", action, "cause", err,
+					)
 
-				// Define updates to apply to the session record.
-				updates := map[string]interface{}{
-					"rx_bytes": rxBytes,
-					"tx_bytes": txBytes,
-				}
+					// Fold the deltas into the bases, so that later statistics of the peer count from the current
+					// values without the implausible jump.
+					updates := map[string]interface{}{
+						"rx_bytes_base":   session.GetRxBytes().Sub(math.NewInt(item.RxBytes)).String(),
+						"tx_bytes_base":   session.GetTxBytes().Sub(math.NewInt(item.TxBytes)).String(),
+						"usage_synced_at": time.Now(),
+					}
 
-				log.Debug("Updating session in database",
-					"id", 0, "peer_id", peerID, "rx_bytes", rxBytes, "tx_bytes", txBytes,
-				)
+					if _, err := c.SessionStore().FindOneAndUpdate(query, updates); err != nil {
+						return fmt.Errorf("dropping implausible usage of peer %q in database: %w", peerID, err)
+					}
+
+					if action == "remove" {
+						if err := c.RemovePeerIfExists(jobCtx, peerID); err != nil {
+							return fmt.Errorf("removing peer %q with implausible usage from service: %w", peerID, err)
+						}
+					}
 
-				if _, err := operations.SessionFindOneAndUpdate(c.Database(), query, updates); err != nil {
-					return fmt.Errorf("updating session for peer %q in database: %w", peerID, err)
+					return nil
+				}
+			}
+
+			// Define updates to apply to the session record, with the time of the byte snapshot from which the next
+			// sync computes the byte rate.
+			now := time.Now()
+			updates := map[string]interface{}{
+				"rx_bytes":        rxBytes.String(),
+				"tx_bytes":        txBytes.String(),
+				"usage_synced_at": now,
+			}
+
+			// Track how long the session has stayed below the idle byte rate threshold if enabled.
+			if threshold > 0 {
+				updates["idle_duration"] = idleDuration(session, rxBytes.Add(txBytes), threshold, now)
+			}
+
+			log.Debug("Updating session in database",
+				"id", session.GetID(), "peer_id", peerID, "rx_bytes", c.DisplayBytes(rxBytes),
+				"tx_bytes", c.DisplayBytes(txBytes),
+			)
+
+			// Count the bytes served since the previous update towards the usage of the current month.
+			bytes := rxBytes.Add(txBytes).Sub(session.GetTotalBytes())
+			if !bytes.IsPositive() || !bytes.IsInt64() {
+				bytes = math.ZeroInt()
+			}
+
+			// Retry the update with a jittered delay while the database is busy.
+			attempts, delay := c.DatabaseBusyRetry()
+			updateFunc := func() error {
+				_, err := c.SessionStore().FindOneAndUpdateUsage(query, updates, bytes.Int64(), now)
+				if database.IsBusyError(err) {
+					metrics.IncDatabaseBusyErrors()
 				}
 
-				return nil
-			})
-		}
+				return err //nolint:wrapcheck
+			}
+
+			if err := retry.Do(
+				updateFunc,
+				retry.Context(jobCtx),
+				retry.Attempts(attempts),
+				retry.Delay(delay),
+				retry.MaxJitter(delay),
+				retry.DelayType(retry.CombineDelay(retry.FixedDelay, retry.RandomDelay)),
+				retry.RetryIf(database.IsBusyError),
+				retry.LastErrorOnly(true),
+			); err != nil {
+				return fmt.Errorf("updating session for peer %q in database: %w", peerID, err)
+			}
+
+			// Record the usage update of the session in the event log, only if the usage has changed.
+			if bytes.IsPositive() {
+				c.RecordSessionEvent(events.EventTypeSessionUpdated, session)
+			}
+
+			return nil
+		})
+	}
 
-		// Wait until all routines complete.
-		if err := jobGroup.Wait(); err != nil {
-			return fmt.Errorf("waiting job group: %w", err)
-		}
+	// Wait until all routines complete.
+	if err := jobGroup.Wait(); err != nil {
+		return fmt.Errorf("waiting job group: %w", err)
+	}
 
-		return nil
+	return nil
+}
+
+// NewSessionUsageSyncWithDatabaseWorker creates a worker that updates session usage in the database.
+// This worker fetches usage data from the peer service and updates the corresponding database records.
+// At most concurrency sessions are processed at once.
+func NewSessionUsageSyncWithDatabaseWorker(c *core.Context, interval time.Duration, concurrency uint) cron.Worker {
+	handlerFunc := func(ctx context.Context) error {
+		return SyncSessionUsageWithDatabase(ctx, c, interval, concurrency)
 	}
 
 	// Initialize and return the worker.
@@ -198,7 +368,8 @@ func NewSessionUsageSyncWithDatabaseWorker(c *core.Context, interval time.Durati
 
 // NewSessionUsageValidateWorker creates a worker that validates session usage limits and removes peers if necessary.
 // This worker checks if sessions exceed their maximum byte or duration limits and removes peers accordingly.
-func NewSessionUsageValidateWorker(c *core.Context, interval time.Duration) cron.Worker {
+// At most concurrency sessions are processed at once.
+func NewSessionUsageValidateWorker(c *core.Context, interval time.Duration, concurrency uint) cron.Worker {
 	log := logger.With("module", "workers", "name", NameSessionUsageValidate)
 
 	handlerFunc := func(ctx context.Context) error {
@@ -208,68 +379,87 @@ func NewSessionUsageValidateWorker(c *core.Context, interval time.Duration) cron
 			"service_type": c.Service().Type().String(),
 		}
 
-		items, err := operations.SessionFind(c.Database(), query)
-		if err != nil {
-			return fmt.Errorf("retrieving sessions from database: %w", err)
-		}
+		// Process the sessions page by page so that only one page of records is held in memory at once.
+		pageFunc := func(items []models.Session) error {
+			jobGroup, jobCtx := errgroup.WithContext(ctx)
+			jobGroup.SetLimit(int(concurrency))
 
-		jobGroup, jobCtx := errgroup.WithContext(ctx)
-		jobGroup.SetLimit(2)
+			// Validate session limits and remove peers if needed.
+			for _, val := range items {
+				item := val
 
-		// Validate session limits and remove peers if needed.
-		for _, val := range items {
-			item := val
+				jobGroup.Go(func() error {
+					select {
+					case <-jobCtx.Done():
+						return nil
+					default:
+					}
 
-			jobGroup.Go(func() error {
-				select {
-				case <-jobCtx.Done():
-					return nil
-				default:
-				}
+					// Causes of the session exceeding its limits, if any.
+					var causes []string
 
-				removePeer := false
+					// Check if the session exceeds the maximum allowed bytes.
+					maxBytes := item.GetMaxBytes()
+					if !maxBytes.IsZero() && item.GetTotalBytes().GTE(maxBytes) {
+						log.Debug("Marking peer for removing from service",
+							"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "exceeds max bytes",
+							"total_bytes", c.DisplayBytes(item.GetTotalBytes()), "max_bytes", c.DisplayBytes(item.GetMaxBytes()),
+						)
 
-				// Check if the session exceeds the maximum allowed bytes.
-				maxBytes := item.GetMaxBytes()
-				if !maxBytes.IsZero() && item.GetTotalBytes().GTE(maxBytes) {
-					log.Debug("Marking peer for removing from service",
-						"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "exceeds max bytes",
-						"total_bytes", item.GetTotalBytes(), "max_bytes", item.GetMaxBytes(),
-					)
+						causes = append(causes, "exceeds max bytes")
+					}
 
-					removePeer = true
-				}
+					// Check if the session exceeds the maximum allowed duration.
+					maxDuration := item.GetMaxDuration()
+					if maxDuration != 0 && item.GetDuration() >= maxDuration {
+						log.Debug("Marking peer for removing from service",
+							"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "exceeds max duration",
+							"duration", item.GetDuration(), "max_duration", maxDuration,
+						)
 
-				// Check if the session exceeds the maximum allowed duration.
-				maxDuration := item.GetMaxDuration()
-				if maxDuration != 0 && item.GetDuration() >= maxDuration {
-					log.Debug("Marking peer for removing from service",
-						"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "exceeds max duration",
-						"duration", item.GetDuration(), "max_duration", maxDuration,
-					)
+						causes = append(causes, "exceeds max duration")
+					}
 
-					removePeer = true
-				}
+					// Check if the session has stayed idle for longer than the idle timeout.
+					threshold, idleTimeout := c.SessionIdle()
+					if threshold > 0 && item.GetIdleDuration() >= idleTimeout {
+						log.Debug("Marking peer for removing from service",
+							"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "exceeds idle timeout",
+							"idle_duration", item.GetIdleDuration(), "idle_timeout", idleTimeout,
+						)
+
+						causes = append(causes, "exceeds idle timeout")
+					}
 
-				// If the session exceeded any limits, remove the associated peer.
-				if removePeer {
-					log.Debug("Removing peer from service", "id", item.GetID(), "peer_id", item.GetPeerID())
+					// If the session exceeded any limits, remove the associated peer.
+					if len(causes) > 0 {
+						log.Debug("Removing peer from service", "id", item.GetID(), "peer_id", item.GetPeerID())
 
-					if err := c.RemovePeerIfExists(jobCtx, item.GetPeerID()); err != nil {
-						return fmt.Errorf("removing peer %q for session %d from service: %w", item.GetPeerID(), item.GetID(), err)
+						if err := c.RemovePeerIfExists(jobCtx, item.GetPeerID()); err != nil {
+							return fmt.Errorf("removing peer %q for session %d from service: %w", item.GetPeerID(), item.GetID(), err)
+						}
+
+						c.EmitEvent(webhook.EventTypeSessionLimitExceeded, map[string]interface{}{
+							"acc_addr": item.AccAddr,
+							"causes":   causes,
+							"id":       item.GetID(),
+							"peer_id":  item.GetPeerID(),
+						})
 					}
-				}
 
-				return nil
-			})
-		}
+					return nil
+				})
+			}
 
-		// Wait until all routines complete.
-		if err := jobGroup.Wait(); err != nil {
-			return fmt.Errorf("waiting job group: %w", err)
+			// Wait until all routines complete.
+			if err := jobGroup.Wait(); err != nil {
+				return fmt.Errorf("waiting job group: %w", err)
+			}
+
+			return nil
 		}
 
-		return nil
+		return forEachSessionPage(c, query, pageFunc)
 	}
 
 	// Initialize and return the worker.
@@ -280,7 +470,8 @@ func NewSessionUsageValidateWorker(c *core.Context, interval time.Duration) cron
 
 // NewSessionValidateWorker creates a worker that validates session status and removes peers if necessary.
 // This worker ensures sessions are active and consistent between the database and blockchain.
-func NewSessionValidateWorker(c *core.Context, interval time.Duration) cron.Worker {
+// At most concurrency sessions are processed at once.
+func NewSessionValidateWorker(c *core.Context, interval time.Duration, concurrency uint) cron.Worker {
 	log := logger.With("module", "workers", "name", NameSessionValidate)
 
 	handlerFunc := func(ctx context.Context) error {
@@ -289,13 +480,13 @@ func NewSessionValidateWorker(c *core.Context, interval time.Duration) cron.Work
 			"node_addr": c.NodeAddr().String(),
 		}
 
-		items, err := operations.SessionFind(c.Database(), query)
+		items, err := c.SessionStore().Find(query)
 		if err != nil {
 			return fmt.Errorf("retrieving sessions from database: %w", err)
 		}
 
 		jobGroup, jobCtx := errgroup.WithContext(ctx)
-		jobGroup.SetLimit(2)
+		jobGroup.SetLimit(int(concurrency))
 
 		// Validate session status and consistency.
 		for _, val := range items {
@@ -375,15 +566,9 @@ func NewSessionValidateWorker(c *core.Context, interval time.Duration) cron.Work
 
 					// Delete the session record from the database if not found on the blockchain.
 					if remove {
-						query := map[string]interface{}{
-							"id": item.GetID(),
-						}
-
 						log.Info("Deleting session from database", "id", item.GetID(), "peer_id", item.GetPeerID())
 
-						if _, err := operations.SessionFindOneAndDelete(c.Database(), query); err != nil {
-							return fmt.Errorf("deleting session %d from database: %w", item.GetID(), err)
-						}
+						return deleteCompletedSession(c, &item)
 					}
 
 					return nil
@@ -410,3 +595,181 @@ func NewSessionValidateWorker(c *core.Context, interval time.Duration) cron.Work
 		WithHandler(handlerFunc).
 		WithInterval(interval)
 }
+
+// deleteCompletedSession deletes the record of a session that no longer exists on the blockchain from the database,
+// accounts its usage in the earnings metrics, and records its deletion in the event log.
+func deleteCompletedSession(c *core.Context, item *models.Session) error {
+	query := map[string]interface{}{
+		"id": item.GetID(),
+	}
+
+	if _, err := c.SessionStore().FindOneAndDelete(query); err != nil {
+		return fmt.Errorf("deleting session %d from database: %w", item.GetID(), err)
+	}
+
+	recordSessionEarnings(c, item)
+	c.RecordSessionEvent(events.EventTypeSessionDeleted, item)
+
+	return nil
+}
+
+// NewSessionRetentionWorker creates a worker that deletes expired sessions and reclaims the space of the database.
+// Sessions created more than retention ago are deleted only once their absence on the blockchain is confirmed, so
+// that no billable usage is lost, and are accounted in the earnings metrics like those deleted by the session
+// validation. Events older than retention of sessions no longer in the database are purged, and the database is
+// vacuumed afterward if anything was deleted.
+func NewSessionRetentionWorker(c *core.Context, interval, retention time.Duration) cron.Worker {
+	log := logger.With("module", "workers", "name", NameSessionRetention)
+
+	handlerFunc := func(ctx context.Context) error {
+		before := time.Now().Add(-retention)
+
+		items, err := c.SessionStore().FindCreatedBefore(before)
+		if err != nil {
+			return fmt.Errorf("retrieving expired sessions from database: %w", err)
+		}
+
+		deleted := 0
+
+		for i := range items {
+			item := &items[i]
+
+			// A failed query leaves the session in place, since its absence on the blockchain is not confirmed.
+			session, err := c.Client().Session(ctx, item.GetID())
+			if err != nil {
+				return fmt.Errorf("querying session %d from blockchain: %w", item.GetID(), err)
+			}
+
+			if session != nil {
+				log.Debug("Skipping session",
+					"id", item.GetID(), "peer_id", item.GetPeerID(), "cause", "exists on blockchain",
+				)
+
+				continue
+			}
+
+			if err := c.RemovePeerIfExists(ctx, item.GetPeerID()); err != nil {
+				return fmt.Errorf("removing peer %q for session %d from service: %w", item.GetPeerID(), item.GetID(), err)
+			}
+
+			log.Info("Deleting expired session from database", "id", item.GetID(), "peer_id", item.GetPeerID())
+
+			if err := deleteCompletedSession(c, item); err != nil {
+				return err
+			}
+
+			deleted++
+		}
+
+		// Purge the expired events of the deleted sessions, including those deleted once they ended on the blockchain.
+		purged, err := c.SessionStore().DeleteOrphanedEvents(before)
+		if err != nil {
+			return fmt.Errorf("deleting orphaned session events from database: %w", err)
+		}
+
+		// Reclaim the space left by the deleted rows, if any.
+		if deleted > 0 || purged > 0 {
+			if err := database.Vacuum(c.Database()); err != nil {
+				return fmt.Errorf("vacuuming database: %w", err)
+			}
+		}
+
+		log.Info("Database retention finished", "deleted", deleted, "expired", len(items), "purged_events", purged)
+
+		return nil
+	}
+
+	// Initialize and return the worker.
+	return cron.NewBasicWorker(NameSessionRetention).
+		WithHandler(handlerFunc).
+		WithInterval(interval)
+}
+
+// idleDuration returns the continuous duration a session has stayed idle, given its total bytes at now.
+// The byte rate is computed over the time elapsed since the previous byte snapshot of the session, rather than its
+// last update, which other writes to the record also advance.
+func idleDuration(session *models.Session, totalBytes math.Int, threshold uint64, now time.Time) time.Duration {
+	elapsed := now.Sub(session.GetUsageSyncedAt())
+	if elapsed <= 0 {
+		return session.GetIdleDuration()
+	}
+
+	delta := totalBytes.Sub(session.GetTotalBytes())
+	if delta.IsNegative() {
+		delta = math.ZeroInt()
+	}
+
+	// Reset the idle duration once the transferred bytes reach the threshold rate over the elapsed time.
+	minBytes := math.NewIntFromUint64(threshold).MulRaw(int64(elapsed)).QuoRaw(int64(time.Second))
+	if delta.GTE(minBytes) {
+		return 0
+	}
+
+	return session.GetIdleDuration() + elapsed
+}
+
+// checkUsagePlausible returns an error if the bytes received or transmitted since the usage of the session was last
+// synced exceed the multiplier times the measured download or upload speed of the node over the elapsed time.
+// Usage is not checked against a speed that has not been measured.
+func checkUsagePlausible(session *models.Session, rxBytes, txBytes, dlSpeed, ulSpeed math.Int, multiplier float64) error {
+	elapsed := max(time.Since(session.GetUsageSyncedAt()), time.Second)
+
+	check := func(name string, delta, speed math.Int) error {
+		if !speed.IsPositive() || !delta.IsPositive() {
+			return nil
+		}
+
+		limit := speed.ToLegacyDec().MustFloat64() * multiplier * elapsed.Seconds()
+		if got := delta.ToLegacyDec().MustFloat64(); got > limit {
+			return fmt.Errorf("%s bytes increased by %s in %s, above the plausible %.0f", name, delta, elapsed.Round(time.Second), limit)
+		}
+
+		return nil
+	}
+
+	// Bytes received from the peer are bounded by the download speed and bytes sent by the upload speed.
+	if err := check("rx", rxBytes.Sub(session.GetRxBytes()), dlSpeed); err != nil {
+		return err
+	}
+
+	return check("tx", txBytes.Sub(session.GetTxBytes()), ulSpeed)
+}
+
+// forEachSessionPage calls fn with consecutive pages of the session records matching the query, ordered by id,
+// until all the records are processed or fn returns an error. Records must not be deleted by fn, since the pages
+// are selected by offset.
+func forEachSessionPage(c *core.Context, query map[string]interface{}, fn func(items []models.Session) error) error {
+	for offset := 0; ; offset += sessionPageSize {
+		items, err := c.SessionStore().FindPaginated(query, sessionPageSize, offset, "id ASC")
+		if err != nil {
+			return fmt.Errorf("retrieving sessions from database: %w", err)
+		}
+
+		if len(items) > 0 {
+			if err := fn(items); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < sessionPageSize {
+			return nil
+		}
+	}
+}
+
+// updateLastSyncedBytes records the total bytes of a session confirmed on the blockchain in the database.
+func updateLastSyncedBytes(c *core.Context, id uint64, totalBytes math.Int) error {
+	query := map[string]interface{}{
+		"id": id,
+	}
+
+	updates := map[string]interface{}{
+		"last_synced_bytes": totalBytes.String(),
+	}
+
+	if _, err := c.SessionStore().FindOneAndUpdate(query, updates); err != nil {
+		return fmt.Errorf("updating last synced bytes for session %d in database: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package workers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/sentinel-official/sentinelhub/v12/types/v1"
+
+	"github.com/sentinel-official/sentinel-dvpnx/config"
+	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
+	"github.com/sentinel-official/sentinel-dvpnx/metrics"
+)
+
+func TestIdleDuration(t *testing.T) {
+	now := time.Now()
+	syncedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		session    *models.Session
+		totalBytes int64
+		want       time.Duration
+	}{
+		{
+			name:       "below threshold since snapshot",
+			session:    &models.Session{RxBytes: "0", TxBytes: "0", IdleDuration: time.Minute, UsageSyncedAt: &syncedAt},
+			totalBytes: 59,
+			want:       2 * time.Minute,
+		},
+		{
+			name:       "at threshold since snapshot",
+			session:    &models.Session{RxBytes: "0", TxBytes: "0", IdleDuration: time.Minute, UsageSyncedAt: &syncedAt},
+			totalBytes: 60,
+			want:       0,
+		},
+		{
+			// An unrelated write an instant ago advances UpdatedAt, which must not shrink the measured window.
+			name: "unrelated write after snapshot",
+			session: &models.Session{
+				RxBytes: "0", TxBytes: "0", UpdatedAt: now, UsageSyncedAt: &syncedAt,
+			},
+			totalBytes: 59,
+			want:       time.Minute,
+		},
+		{
+			name:       "never synced counts from creation",
+			session:    &models.Session{RxBytes: "0", TxBytes: "0", CreatedAt: now.Add(-2 * time.Minute)},
+			totalBytes: 0,
+			want:       2 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idleDuration(tt.session, math.NewInt(tt.totalBytes), 1, now); got != tt.want {
+				t.Fatalf("expected idle duration %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// scrapeMetrics returns the metrics exposed by the node in the text format.
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	buf, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("reading metrics: %v", err)
+	}
+
+	return string(buf)
+}
+
+func TestDeleteCompletedSession(t *testing.T) {
+	db, err := database.NewDefault(config.DatabaseDriverSQLite, filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+
+	const denom = "udeletetest"
+
+	c := core.NewContext().
+		WithSessionStore(database.NewGormSessionStore(db)).
+		WithGigabytePrices(v1.Prices{{Denom: denom, BaseValue: math.LegacyOneDec(), QuoteValue: math.NewInt(5)}})
+
+	// The session served 2 gigabytes, which are priced at 5 per gigabyte.
+	session := &models.Session{
+		ID:          1,
+		MaxBytes:    "10000000000",
+		PeerID:      "peer-1",
+		PeerRequest: "req-1",
+		RxBytes:     "1000000000",
+		TxBytes:     "1000000000",
+	}
+	if err := db.Create(session).Error; err != nil {
+		t.Fatalf("inserting session: %v", err)
+	}
+
+	if err := deleteCompletedSession(c, session); err != nil {
+		t.Fatalf("deleting session: %v", err)
+	}
+
+	item, err := c.SessionStore().FindOne(map[string]interface{}{"id": session.GetID()})
+	if err != nil {
+		t.Fatalf("finding session: %v", err)
+	}
+
+	if item != nil {
+		t.Fatalf("expected the session to be deleted, got %+v", item)
+	}
+
+	want := `dvpnx_earnings_total{denom="` + denom + `"} 10`
+	if out := scrapeMetrics(t); !strings.Contains(out, want) {
+		t.Fatalf("expected the earnings of the deleted session %q in the metrics, got:\n%s", want, out)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"cosmossdk.io/math"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/cron"
 	logger "github.com/sentinel-official/sentinel-go-sdk/libs/log"
 	"github.com/sentinel-official/sentinel-go-sdk/libs/speedtest"
 
+	"github.com/sentinel-official/sentinel-dvpnx/config"
 	"github.com/sentinel-official/sentinel-dvpnx/core"
+	"github.com/sentinel-official/sentinel-dvpnx/database/models"
 )
 
 const NameSpeedtest = "speedtest"
@@ -30,6 +33,38 @@ func NewSpeedtestWorker(c *core.Context, interval time.Duration) cron.Worker {
 		log.Debug("Updating context", "dl_speed", dlSpeed, "ul_speed", ulSpeed)
 		c.SetSpeedtestResults(dlSpeed, ulSpeed)
 
+		// Record the results in the speed test history.
+		result := models.NewSpeedtest().
+			WithDownloadBytesPerSec(dlSpeed).
+			WithNodeAddr(c.NodeAddr()).
+			WithUploadBytesPerSec(ulSpeed)
+		if err := c.SpeedtestStore().InsertOne(result); err != nil {
+			log.Error("Failed to record speedtest in database", "cause", err)
+		}
+
+		// Persist the results so that they are available right after a restart.
+		if c.PersistState() {
+			if err := c.SaveState(); err != nil {
+				log.Error("Failed to persist state", "cause", err)
+			}
+		}
+
+		// Derive the maximum peers from the measured upload speed if enabled.
+		if bandwidth := c.PeerBandwidth(); bandwidth.IsPositive() {
+			maxPeers := math.NewInt(config.MaxQoSMaxPeers)
+			if v := ulSpeed.Quo(bandwidth); v.LT(maxPeers) {
+				maxPeers = math.MaxInt(v, math.OneInt())
+			}
+
+			// Keep the derived value within the address capacity of the service.
+			if capacity := c.PeerCapacity(); capacity > 0 {
+				maxPeers = math.MinInt(maxPeers, math.NewIntFromUint64(uint64(capacity)))
+			}
+
+			log.Info("Updating max peers", "max_peers", maxPeers, "peer_bandwidth", bandwidth, "ul_speed", ulSpeed)
+			c.SetMaxPeers(uint(maxPeers.Uint64()))
+		}
+
 		return nil
 	}
 